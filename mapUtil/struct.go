@@ -0,0 +1,640 @@
+package mapUtil
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// StructMapOpt 结构体与map互转的配置
+type StructMapOpt struct {
+	TagName string // 字段名取自的tag，如 "json"、"bson" 或自定义tag，默认为 "json"
+	Flatten bool   // StructToMap 时是否展开为 "top.sub" 形式的扁平map
+}
+
+// structField 解析后的结构体字段信息
+type structField struct {
+	index     []int  // 字段索引路径（含嵌入结构体）
+	name      string // map中的键名
+	omitEmpty bool   // 零值时是否忽略
+	tagged    bool   // 键名是否来自tag，同名字段冲突时优先
+}
+
+func getStructMapOpt(opts []*StructMapOpt) *StructMapOpt {
+	opt := &StructMapOpt{TagName: "json"}
+	if len(opts) > 0 && opts[0] != nil {
+		*opt = *opts[0]
+		if opt.TagName == "" {
+			opt.TagName = "json"
+		}
+	}
+	return opt
+}
+
+// StructToMap 将结构体转换为 map[string]any
+// 字段名取自 opt.TagName 指定的tag（默认json），支持 "-"、omitempty、嵌入结构体以及 bson 的 inline
+// 与 mongo 驱动一致，TagName 为 bson 时只提升 inline 的嵌入结构体，其余嵌入结构体作为以小写类型名为键的子文档
+// 嵌套结构体转换为 map[string]any，切片转换为 []any，time.Time 保持原样
+// opt.Flatten 为 true 时返回扁平map，键为 GetByPath 可用的路径，eg "top.sub" "list.0.name"
+func StructToMap(obj any, opts ...*StructMapOpt) (map[string]any, error) {
+	opt := getStructMapOpt(opts)
+
+	rv := reflect.ValueOf(obj)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("input parameter#obj must not be nil")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("input parameter#obj must be a Struct, but was %s", rv.Kind())
+	}
+
+	mp := structToMap(rv, opt.TagName)
+	if opt.Flatten {
+		return Flatten(mp), nil
+	}
+	return mp, nil
+}
+
+// MapToStruct 将 map[string]any 的值写入 out 指向的结构体
+// 字段名规则与 StructToMap 一致，键中包含 "." 时会先按路径展开，eg {"top.sub": 1} => {"top": {"sub": 1}}
+// 展开后子键为下标的map只在字段为切片或数组时还原，map类型的字段保持为map
+// 数值类型之间会自动转换，溢出或浮点数有小数部分无法转换为整数时返回错误
+// time.Time 字段支持 time.Time、bson.DateTime 和 RFC3339 字符串，nil 转换为零值
+func MapToStruct(mp map[string]any, out any, opts ...*StructMapOpt) error {
+	opt := getStructMapOpt(opts)
+
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("input parameter#out must be a non-nil Pointer, but was %T", out)
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("input parameter#out must point to a Struct, but was %s", rv.Kind())
+	}
+
+	for k := range mp {
+		if strings.Contains(k, KeySepStr) {
+			mp = unflatten(mp)
+			break
+		}
+	}
+	return mapToStruct(mp, rv, opt.TagName, "")
+}
+
+// Flatten 将嵌套map展开为单层map，键为 GetByPath 可用的路径
+// 支持 bson.M 和 bson.A，切片元素使用下标作为路径，空map和空切片保留为叶子节点
+//
+// Example:
+//
+//	Flatten(map[string]any{"top": map[string]any{"sub": 1}, "list": []any{"a"}})
+//	// return map[string]any{"top.sub": 1, "list.0": "a"}
+func Flatten(mp map[string]any) map[string]any {
+	result := make(map[string]any, len(mp))
+	for k, v := range mp {
+		flattenValue(result, k, v)
+	}
+	return result
+}
+
+func flattenValue(result map[string]any, path string, val any) {
+	switch tData := val.(type) {
+	case bson.M:
+		flattenValue(result, path, map[string]any(tData))
+	case bson.A:
+		flattenValue(result, path, []any(tData))
+	case map[string]any:
+		if len(tData) == 0 {
+			result[path] = tData
+			return
+		}
+		for k, v := range tData {
+			flattenValue(result, path+KeySepStr+k, v)
+		}
+	case []any:
+		if len(tData) == 0 {
+			result[path] = tData
+			return
+		}
+		for i, v := range tData {
+			flattenValue(result, path+KeySepStr+strconv.Itoa(i), v)
+		}
+	default:
+		result[path] = val
+	}
+}
+
+// Unflatten 是 Flatten 的逆操作，将路径键还原为嵌套map
+// 子键全部为从0开始的连续下标时还原为 []any
+func Unflatten(mp map[string]any) map[string]any {
+	return restoreSlices(unflatten(mp)).(map[string]any)
+}
+
+// unflatten 将路径键还原为嵌套map，不还原切片
+func unflatten(mp map[string]any) map[string]any {
+	result := make(map[string]any, len(mp))
+
+	// 按键排序，保证同一路径上短键先写入
+	keys := Keys(mp)
+	sort.Strings(keys)
+	for _, path := range keys {
+		cur := result
+		parts := strings.Split(path, KeySepStr)
+		for i, k := range parts {
+			if i == len(parts)-1 {
+				// 复制map类型的值，之后写入子键或还原切片时不修改调用方的map
+				cur[k] = cloneNestedMap(mp[path])
+				break
+			}
+			sub, ok := cur[k].(map[string]any)
+			if !ok {
+				sub = make(map[string]any)
+				cur[k] = sub
+			}
+			cur = sub
+		}
+	}
+	return result
+}
+
+// cloneNestedMap 逐层复制嵌套的 map[string]any，其他类型的值原样返回
+func cloneNestedMap(val any) any {
+	mp, ok := val.(map[string]any)
+	if !ok {
+		return val
+	}
+	mp = maps.Clone(mp)
+	for k, v := range mp {
+		mp[k] = cloneNestedMap(v)
+	}
+	return mp
+}
+
+// restoreSlices 将子键全部为连续下标的map还原为 []any
+func restoreSlices(val any) any {
+	mp, ok := val.(map[string]any)
+	if !ok || len(mp) == 0 {
+		return val
+	}
+	for k, v := range mp {
+		mp[k] = restoreSlices(v)
+	}
+	if sl, ok := indexMapToSlice(mp); ok {
+		return sl
+	}
+	return mp
+}
+
+// indexMapToSlice 子键全部为从0开始的连续下标时转换为 []any
+func indexMapToSlice(mp map[string]any) ([]any, bool) {
+	sl := make([]any, len(mp))
+	for k, v := range mp {
+		idx, err := strconv.Atoi(k)
+		if err != nil || idx < 0 || idx >= len(mp) || strconv.Itoa(idx) != k {
+			return nil, false
+		}
+		sl[idx] = v
+	}
+	return sl, true
+}
+
+// parseStructTag 解析tag，返回字段名和选项
+func parseStructTag(tag string) (name string, opts []string) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+// getStructFields 获取结构体的可导出字段，嵌入结构体的字段会被提升，tagName 为 bson 时只提升 inline 的字段
+// 没有tag名时使用字段名，bson 与 mongo 驱动一致使用小写的字段名
+// 同名字段与 encoding/json 一致：嵌入层级浅的优先，同一层级有tag名的优先，仍无法区分时全部忽略
+// 按层级展开嵌入结构体，已在更浅层级展开过的类型不再展开，避免自引用的嵌入指针无限递归
+func getStructFields(rt reflect.Type, tagName string) []structField {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	var fields []structField
+	visited := make(map[reflect.Type]bool)
+	next := []embedded{{typ: rt}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				tag := sf.Tag.Get(tagName)
+				if tag == "-" {
+					continue
+				}
+				name, tagOpts := parseStructTag(tag)
+				inline := false
+				omitEmpty := false
+				for _, o := range tagOpts {
+					switch o {
+					case "inline":
+						inline = true
+					case "omitempty":
+						omitEmpty = true
+					}
+				}
+				index := append(slices.Clone(e.index), i)
+
+				ft := sf.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				// 嵌入结构体（无tag名）或 inline 字段，在下一层级提升其字段，mongo 驱动只提升 inline 的字段
+				promote := inline || (sf.Anonymous && name == "" && tagName != "bson")
+				if promote && ft.Kind() == reflect.Struct && ft != timeType {
+					if sf.Type.Kind() == reflect.Pointer && !sf.IsExported() {
+						continue
+					}
+					next = append(next, embedded{typ: ft, index: index})
+					continue
+				}
+				if !sf.IsExported() {
+					continue
+				}
+				tagged := name != ""
+				if !tagged {
+					name = sf.Name
+					if tagName == "bson" {
+						name = strings.ToLower(name)
+					}
+				}
+				fields = append(fields, structField{index: index, name: name, omitEmpty: omitEmpty, tagged: tagged})
+			}
+		}
+		// 同一层级重复嵌入的类型都会展开，产生的同名字段互相抵消
+		for _, e := range current {
+			visited[e.typ] = true
+		}
+	}
+	return dominantFields(fields)
+}
+
+// dominantFields 同名字段中保留层级最浅的字段，同一层级有多个时保留唯一有tag名的字段，否则全部忽略
+// 返回的字段按声明顺序排列
+func dominantFields(fields []structField) []structField {
+	slices.SortStableFunc(fields, func(a, b structField) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := len(a.index) - len(b.index); c != 0 {
+			return c
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+	result := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		// fields[i] 层级最浅且有tag名的排在前面，下一个字段同样层级且tag情况相同时无法区分
+		if j == i+1 || len(fields[i+1].index) > len(fields[i].index) || fields[i].tagged != fields[i+1].tagged {
+			result = append(result, fields[i])
+		}
+		i = j
+	}
+	slices.SortFunc(result, func(a, b structField) int { return slices.Compare(a.index, b.index) })
+	return result
+}
+
+// fieldByIndex 按索引路径获取字段，路径上的nil指针返回无效值
+func fieldByIndex(rv reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return reflect.Value{}
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv
+}
+
+// fieldByIndexAlloc 按索引路径获取字段，路径上的nil指针会被初始化
+func fieldByIndexAlloc(rv reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv
+}
+
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	default:
+		return rv.IsZero()
+	}
+}
+
+func structToMap(rv reflect.Value, tagName string) map[string]any {
+	fields := getStructFields(rv.Type(), tagName)
+	mp := make(map[string]any, len(fields))
+	for _, f := range fields {
+		fv := fieldByIndex(rv, f.index)
+		if !fv.IsValid() || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		mp[f.name] = toMapValue(fv, tagName)
+	}
+	return mp
+}
+
+// toMapValue 将字段值转换为map中的值
+func toMapValue(rv reflect.Value, tagName string) any {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		if rv.Type() == timeType {
+			return rv.Interface()
+		}
+		return structToMap(rv, tagName)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		// []byte 保持原样
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Interface()
+		}
+		sl := make([]any, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			sl[i] = toMapValue(rv.Index(i), tagName)
+		}
+		return sl
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil
+		}
+		if rv.Type().Key().Kind() != reflect.String {
+			return rv.Interface()
+		}
+		mp := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			mp[iter.Key().String()] = toMapValue(iter.Value(), tagName)
+		}
+		return mp
+	default:
+		return rv.Interface()
+	}
+}
+
+func mapToStruct(mp map[string]any, rv reflect.Value, tagName string, path string) error {
+	for _, f := range getStructFields(rv.Type(), tagName) {
+		val, ok := mp[f.name]
+		if !ok {
+			continue
+		}
+		fv := fieldByIndexAlloc(rv, f.index)
+		if err := setFieldValue(fv, val, tagName, joinPath(path, f.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + KeySepStr + key
+}
+
+// setFieldValue 将 val 转换为 fv 的类型并赋值
+func setFieldValue(fv reflect.Value, val any, tagName string, path string) error {
+	if val == nil {
+		fv.Set(reflect.Zero(fv.Type()))
+		return nil
+	}
+
+	vv := reflect.ValueOf(val)
+	ft := fv.Type()
+
+	if ft == timeType {
+		t, err := toTime(val)
+		if err != nil {
+			return fmt.Errorf("cannot set field %q: %w", path, err)
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if vv.Type().AssignableTo(ft) {
+		fv.Set(vv)
+		return nil
+	}
+
+	switch ft.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(ft.Elem())
+		if err := setFieldValue(elem.Elem(), val, tagName, path); err != nil {
+			return err
+		}
+		fv.Set(elem)
+		return nil
+	case reflect.Interface:
+		if vv.Type().Implements(ft) {
+			fv.Set(vv)
+			return nil
+		}
+	case reflect.Struct:
+		sub, ok := toStringMap(vv)
+		if !ok {
+			break
+		}
+		return mapToStruct(sub, fv, tagName, path)
+	case reflect.Slice:
+		vv, ok := toSliceValue(vv)
+		if !ok {
+			break
+		}
+		sl := reflect.MakeSlice(ft, vv.Len(), vv.Len())
+		for i := 0; i < vv.Len(); i++ {
+			if err := setFieldValue(sl.Index(i), vv.Index(i).Interface(), tagName, joinPath(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		fv.Set(sl)
+		return nil
+	case reflect.Array:
+		vv, ok := toSliceValue(vv)
+		if !ok {
+			break
+		}
+		for i := 0; i < vv.Len() && i < fv.Len(); i++ {
+			if err := setFieldValue(fv.Index(i), vv.Index(i).Interface(), tagName, joinPath(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if vv.Kind() != reflect.Map || ft.Key().Kind() != reflect.String || vv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		mv := reflect.MakeMapWithSize(ft, vv.Len())
+		iter := vv.MapRange()
+		for iter.Next() {
+			ev := reflect.New(ft.Elem()).Elem()
+			key := iter.Key().String()
+			if err := setFieldValue(ev, iter.Value().Interface(), tagName, joinPath(path, key)); err != nil {
+				return err
+			}
+			mv.SetMapIndex(reflect.ValueOf(key).Convert(ft.Key()), ev)
+		}
+		fv.Set(mv)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if isNumberKind(vv.Kind()) {
+			if err := checkNumber(vv, fv); err != nil {
+				return fmt.Errorf("cannot set field %q: %w", path, err)
+			}
+			fv.Set(vv.Convert(ft))
+			return nil
+		}
+	default:
+		if vv.Type().ConvertibleTo(ft) && vv.Kind() == ft.Kind() {
+			fv.Set(vv.Convert(ft))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cannot set field %q: value type %T is not compatible with %s", path, val, ft)
+}
+
+// toStringMap 将键为string的map转换为 map[string]any，支持 bson.M 等自定义map类型
+func toStringMap(vv reflect.Value) (map[string]any, bool) {
+	if vv.Kind() != reflect.Map || vv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	if mp, ok := vv.Interface().(map[string]any); ok {
+		return mp, true
+	}
+	mp := make(map[string]any, vv.Len())
+	iter := vv.MapRange()
+	for iter.Next() {
+		mp[iter.Key().String()] = iter.Value().Interface()
+	}
+	return mp, true
+}
+
+// toSliceValue 获取可按下标赋值的值，子键全部为下标的map视为切片
+func toSliceValue(vv reflect.Value) (reflect.Value, bool) {
+	if vv.Kind() == reflect.Slice || vv.Kind() == reflect.Array {
+		return vv, true
+	}
+	mp, ok := toStringMap(vv)
+	if !ok {
+		return vv, false
+	}
+	sl, ok := indexMapToSlice(mp)
+	return reflect.ValueOf(sl), ok
+}
+
+// checkNumber 检查数值 vv 能否无损地转换为 fv 的类型，整数不能溢出，浮点数转换为整数时不能有小数部分
+func checkNumber(vv reflect.Value, fv reflect.Value) error {
+	overflow := false
+	switch {
+	case fv.CanInt():
+		switch {
+		case vv.CanInt():
+			overflow = fv.OverflowInt(vv.Int())
+		case vv.CanUint():
+			overflow = vv.Uint() > math.MaxInt64 || fv.OverflowInt(int64(vv.Uint()))
+		default:
+			f := vv.Float()
+			if f != math.Trunc(f) {
+				return fmt.Errorf("value %v has a fractional part and cannot be converted to %s", f, fv.Type())
+			}
+			overflow = f < math.MinInt64 || f >= math.MaxInt64 || fv.OverflowInt(int64(f))
+		}
+	case fv.CanUint():
+		switch {
+		case vv.CanInt():
+			overflow = vv.Int() < 0 || fv.OverflowUint(uint64(vv.Int()))
+		case vv.CanUint():
+			overflow = fv.OverflowUint(vv.Uint())
+		default:
+			f := vv.Float()
+			if f != math.Trunc(f) {
+				return fmt.Errorf("value %v has a fractional part and cannot be converted to %s", f, fv.Type())
+			}
+			overflow = f < 0 || f >= math.MaxUint64 || fv.OverflowUint(uint64(f))
+		}
+	default:
+		if vv.CanFloat() {
+			overflow = fv.OverflowFloat(vv.Float())
+		}
+	}
+	if overflow {
+		return fmt.Errorf("value %v overflows %s", vv.Interface(), fv.Type())
+	}
+	return nil
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func toTime(val any) (time.Time, error) {
+	switch t := val.(type) {
+	case time.Time:
+		return t, nil
+	case *time.Time:
+		if t == nil {
+			return time.Time{}, nil
+		}
+		return *t, nil
+	case bson.DateTime:
+		return t.Time(), nil
+	case string:
+		return time.Parse(time.RFC3339Nano, t)
+	default:
+		return time.Time{}, fmt.Errorf("value type %T cannot be converted to time.Time", val)
+	}
+}
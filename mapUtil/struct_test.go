@@ -0,0 +1,429 @@
+package mapUtil
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type testBase struct {
+	ID      int64     `json:"id" bson:"_id"`
+	Created time.Time `json:"created" bson:"created"`
+}
+
+type testAddress struct {
+	City string `json:"city" bson:"city"`
+	Zip  string `json:"zip,omitempty" bson:"zip,omitempty"`
+}
+
+type testUser struct {
+	testBase `bson:",inline"`
+	Name     string            `json:"name" bson:"name"`
+	Age      int               `json:"age,omitempty" bson:"age"`
+	Password string            `json:"-" bson:"password"`
+	Address  testAddress       `json:"address" bson:"address"`
+	Tags     []string          `json:"tags" bson:"tags"`
+	Friends  []testAddress     `json:"friends" bson:"friends"`
+	Extra    map[string]int    `json:"extra" bson:"extra"`
+	Backup   *testAddress      `json:"backup,omitempty" bson:"backup,omitempty"`
+	Meta     map[string]string `custom:"m" json:"meta,omitempty"`
+	private  int
+}
+
+func newTestUser() testUser {
+	return testUser{
+		testBase: testBase{ID: 1, Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		Name:     "tom",
+		Password: "secret",
+		Address:  testAddress{City: "shanghai"},
+		Tags:     []string{"a", "b"},
+		Friends:  []testAddress{{City: "beijing", Zip: "100000"}},
+		Extra:    map[string]int{"x": 1},
+		Meta:     map[string]string{"k": "v"},
+		private:  1,
+	}
+}
+
+func TestStructToMap(t *testing.T) {
+	user := newTestUser()
+
+	t.Run("json tag", func(t *testing.T) {
+		mp, err := StructToMap(&user)
+		if err != nil {
+			t.Fatalf("StructToMap() error = %v", err)
+		}
+		expected := map[string]any{
+			"id":      int64(1),
+			"created": user.Created,
+			"name":    "tom",
+			"address": map[string]any{"city": "shanghai"},
+			"tags":    []any{"a", "b"},
+			"friends": []any{map[string]any{"city": "beijing", "zip": "100000"}},
+			"extra":   map[string]any{"x": 1},
+			"meta":    map[string]any{"k": "v"},
+		}
+		if !reflect.DeepEqual(mp, expected) {
+			t.Errorf("StructToMap() = %v, want %v", mp, expected)
+		}
+	})
+
+	t.Run("bson tag", func(t *testing.T) {
+		mp, err := StructToMap(user, &StructMapOpt{TagName: "bson"})
+		if err != nil {
+			t.Fatalf("StructToMap() error = %v", err)
+		}
+		if mp["_id"] != int64(1) || mp["password"] != "secret" || mp["age"] != 0 {
+			t.Errorf("StructToMap() with bson tag = %v", mp)
+		}
+		if _, ok := mp["backup"]; ok {
+			t.Error("expected omitempty nil pointer to be skipped")
+		}
+		if _, ok := mp["meta"]; !ok {
+			t.Error("expected field without tag to use lowercase field name")
+		}
+	})
+
+	t.Run("bson embedded", func(t *testing.T) {
+		type Audit struct {
+			By string `bson:"by"`
+		}
+		type Version struct {
+			Rev int `bson:"rev"`
+		}
+		type doc struct {
+			Audit
+			Version `bson:",inline"`
+			Name    string `bson:"name"`
+		}
+		in := doc{Audit: Audit{By: "tom"}, Version: Version{Rev: 2}, Name: "a"}
+		mp, err := StructToMap(in, &StructMapOpt{TagName: "bson"})
+		if err != nil {
+			t.Fatalf("StructToMap() error = %v", err)
+		}
+		expected := map[string]any{"audit": map[string]any{"by": "tom"}, "rev": 2, "name": "a"}
+		if !reflect.DeepEqual(mp, expected) {
+			t.Errorf("StructToMap() with bson embedded = %v, want %v", mp, expected)
+		}
+
+		// 与 mongo 驱动编码的文档互通
+		data, err := bson.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dec := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(data)))
+		dec.DefaultDocumentMap()
+		var raw map[string]any
+		if err := dec.Decode(&raw); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(raw, map[string]any{"audit": map[string]any{"by": "tom"}, "rev": int32(2), "name": "a"}) {
+			t.Errorf("Unexpected driver document %v", raw)
+		}
+		var got doc
+		if err := MapToStruct(raw, &got, &StructMapOpt{TagName: "bson"}); err != nil || got != in {
+			t.Errorf("MapToStruct() from driver document = %+v, %v", got, err)
+		}
+	})
+
+	t.Run("custom tag", func(t *testing.T) {
+		mp, err := StructToMap(user, &StructMapOpt{TagName: "custom"})
+		if err != nil {
+			t.Fatalf("StructToMap() error = %v", err)
+		}
+		if _, ok := mp["m"]; !ok {
+			t.Errorf("expected custom tag name m, got %v", mp)
+		}
+		if _, ok := mp["Name"]; !ok {
+			t.Errorf("expected field name Name, got %v", mp)
+		}
+	})
+
+	t.Run("flatten", func(t *testing.T) {
+		mp, err := StructToMap(user, &StructMapOpt{Flatten: true})
+		if err != nil {
+			t.Fatalf("StructToMap() error = %v", err)
+		}
+		if mp["address.city"] != "shanghai" || mp["friends.0.zip"] != "100000" || mp["tags.1"] != "b" {
+			t.Errorf("StructToMap() flatten = %v", mp)
+		}
+
+		// 扁平键与 GetByPath 的路径一致
+		nested, _ := StructToMap(user)
+		for k, v := range mp {
+			got, ok := GetByPath(nested, k)
+			if !ok || !reflect.DeepEqual(got, v) {
+				t.Errorf("GetByPath(%q) = %v, %v, want %v", k, got, ok, v)
+			}
+		}
+	})
+
+	t.Run("self embedded pointer", func(t *testing.T) {
+		type node struct {
+			*node
+			Name string `json:"name"`
+		}
+		mp, err := StructToMap(node{Name: "root"})
+		if err != nil || !reflect.DeepEqual(mp, map[string]any{"name": "root"}) {
+			t.Errorf("StructToMap() = %v, %v", mp, err)
+		}
+	})
+
+	t.Run("duplicate promoted names", func(t *testing.T) {
+		type inner struct {
+			Name  string `json:"name"`
+			Plain string
+			Code  string
+		}
+		type other struct {
+			Plain string
+			Code  string `json:"Code"`
+		}
+		type outer struct {
+			inner
+			other
+			Name string `json:"name"`
+		}
+		mp, err := StructToMap(outer{
+			inner: inner{Name: "inner", Plain: "a", Code: "inner"},
+			other: other{Plain: "b", Code: "other"},
+			Name:  "outer",
+		})
+		// 浅层级优先，同层级有tag名优先，同层级都无tag名时全部忽略
+		want := map[string]any{"name": "outer", "Code": "other"}
+		if err != nil || !reflect.DeepEqual(mp, want) {
+			t.Errorf("StructToMap() = %v, %v, want %v", mp, err, want)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		if _, err := StructToMap(1); err == nil {
+			t.Error("expected error for non-struct input")
+		}
+		var nilUser *testUser
+		if _, err := StructToMap(nilUser); err == nil {
+			t.Error("expected error for nil pointer")
+		}
+	})
+}
+
+func TestMapToStruct(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		user := newTestUser()
+		user.Password = ""
+		user.private = 0
+		mp, _ := StructToMap(user)
+
+		var got testUser
+		if err := MapToStruct(mp, &got); err != nil {
+			t.Fatalf("MapToStruct() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, user) {
+			t.Errorf("MapToStruct() = %+v, want %+v", got, user)
+		}
+	})
+
+	t.Run("flatten round trip", func(t *testing.T) {
+		user := newTestUser()
+		user.Password = ""
+		user.private = 0
+		mp, _ := StructToMap(user, &StructMapOpt{Flatten: true})
+
+		var got testUser
+		if err := MapToStruct(mp, &got); err != nil {
+			t.Fatalf("MapToStruct() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, user) {
+			t.Errorf("MapToStruct() = %+v, want %+v", got, user)
+		}
+	})
+
+	t.Run("bson values", func(t *testing.T) {
+		created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		mp := map[string]any{
+			"_id":     int32(7),
+			"created": bson.NewDateTimeFromTime(created),
+			"name":    "jerry",
+			"address": bson.M{"city": "hangzhou"},
+			"friends": bson.A{bson.M{"city": "suzhou"}},
+			"backup":  map[string]any{"city": "ningbo"},
+		}
+		var got testUser
+		if err := MapToStruct(mp, &got, &StructMapOpt{TagName: "bson"}); err != nil {
+			t.Fatalf("MapToStruct() error = %v", err)
+		}
+		if got.ID != 7 || !got.Created.Equal(created) || got.Address.City != "hangzhou" {
+			t.Errorf("MapToStruct() = %+v", got)
+		}
+		if len(got.Friends) != 1 || got.Friends[0].City != "suzhou" {
+			t.Errorf("MapToStruct() friends = %+v", got.Friends)
+		}
+		if got.Backup == nil || got.Backup.City != "ningbo" {
+			t.Errorf("MapToStruct() backup = %+v", got.Backup)
+		}
+	})
+
+	t.Run("json numbers and time string", func(t *testing.T) {
+		mp := map[string]any{
+			"id":      float64(3),
+			"age":     float64(18),
+			"created": "2024-01-02T03:04:05Z",
+		}
+		var got testUser
+		if err := MapToStruct(mp, &got); err != nil {
+			t.Fatalf("MapToStruct() error = %v", err)
+		}
+		if got.ID != 3 || got.Age != 18 || got.Created.Year() != 2024 {
+			t.Errorf("MapToStruct() = %+v", got)
+		}
+	})
+
+	t.Run("number overflow", func(t *testing.T) {
+		type numbers struct {
+			Small int8    `json:"small"`
+			Count uint    `json:"count"`
+			Ratio float32 `json:"ratio"`
+			Total int64   `json:"total"`
+		}
+		var got numbers
+		if err := MapToStruct(map[string]any{"small": float64(100), "count": int64(5), "total": 2.0}, &got); err != nil {
+			t.Fatalf("MapToStruct() error = %v", err)
+		}
+		if got.Small != 100 || got.Count != 5 || got.Total != 2 {
+			t.Errorf("MapToStruct() = %+v", got)
+		}
+		for _, mp := range []map[string]any{
+			{"small": 200},
+			{"small": uint64(1 << 63)},
+			{"count": -1},
+			{"count": float64(-1)},
+			{"total": 1.5},
+			{"total": 1e19},
+			{"ratio": 1e300},
+		} {
+			if err := MapToStruct(mp, &got); err == nil {
+				t.Errorf("MapToStruct(%v) expected error", mp)
+			}
+		}
+	})
+
+	t.Run("nil time pointer", func(t *testing.T) {
+		var got testUser
+		got.Created = time.Now()
+		if err := MapToStruct(map[string]any{"created": (*time.Time)(nil)}, &got); err != nil {
+			t.Fatalf("MapToStruct() error = %v", err)
+		}
+		if !got.Created.IsZero() {
+			t.Errorf("Expected zero time, got %v", got.Created)
+		}
+	})
+
+	t.Run("flattened index keys", func(t *testing.T) {
+		mp := map[string]any{"extra.0": 1, "extra.1": 2, "tags.0": "a", "tags.1": "b"}
+		var got testUser
+		if err := MapToStruct(mp, &got); err != nil {
+			t.Fatalf("MapToStruct() error = %v", err)
+		}
+		// map 字段保持为 map，切片字段还原为切片
+		if !reflect.DeepEqual(got.Extra, map[string]int{"0": 1, "1": 2}) {
+			t.Errorf("MapToStruct() extra = %v", got.Extra)
+		}
+		if !reflect.DeepEqual(got.Tags, []string{"a", "b"}) {
+			t.Errorf("MapToStruct() tags = %v", got.Tags)
+		}
+	})
+
+	t.Run("bson default name", func(t *testing.T) {
+		type account struct {
+			UserName string
+		}
+		mp, err := StructToMap(account{UserName: "tom"}, &StructMapOpt{TagName: "bson"})
+		if err != nil || mp["username"] != "tom" {
+			t.Fatalf("StructToMap() = %v, %v", mp, err)
+		}
+		var got account
+		if err := MapToStruct(mp, &got, &StructMapOpt{TagName: "bson"}); err != nil || got.UserName != "tom" {
+			t.Errorf("MapToStruct() = %+v, %v", got, err)
+		}
+	})
+
+	t.Run("incompatible type", func(t *testing.T) {
+		var got testUser
+		err := MapToStruct(map[string]any{"address": map[string]any{"city": 1}}, &got)
+		if err == nil {
+			t.Error("expected error for incompatible type")
+		}
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		var got testUser
+		if err := MapToStruct(map[string]any{}, got); err == nil {
+			t.Error("expected error for non-pointer output")
+		}
+	})
+}
+
+func TestFlattenAndUnflatten(t *testing.T) {
+	nested := map[string]any{
+		"top": map[string]any{
+			"sub":   "value",
+			"empty": map[string]any{},
+		},
+		"list": []any{
+			map[string]any{"id": 1},
+			"raw",
+		},
+		"direct": 1,
+	}
+	flat := Flatten(nested)
+	expected := map[string]any{
+		"top.sub":   "value",
+		"top.empty": map[string]any{},
+		"list.0.id": 1,
+		"list.1":    "raw",
+		"direct":    1,
+	}
+	if !reflect.DeepEqual(flat, expected) {
+		t.Errorf("Flatten() = %v, want %v", flat, expected)
+	}
+	if got := Unflatten(flat); !reflect.DeepEqual(got, nested) {
+		t.Errorf("Unflatten() = %v, want %v", got, nested)
+	}
+
+	// map类型的值与路径键混用时不修改输入
+	newInput := func() map[string]any {
+		return map[string]any{
+			"top":     map[string]any{"a": 1, "list": map[string]any{"0": "x"}},
+			"top.sub": 2,
+		}
+	}
+	input := newInput()
+	want := map[string]any{"top": map[string]any{"a": 1, "list": []any{"x"}, "sub": 2}}
+	if got := Unflatten(input); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unflatten() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(input, newInput()) {
+		t.Errorf("Unflatten() modified input: %v", input)
+	}
+	var out struct {
+		Top struct {
+			A    int      `json:"a"`
+			Sub  int      `json:"sub"`
+			List []string `json:"list"`
+		} `json:"top"`
+	}
+	if err := MapToStruct(input, &out); err != nil || out.Top.A != 1 || out.Top.Sub != 2 {
+		t.Errorf("MapToStruct() = %+v, %v", out, err)
+	}
+	if !reflect.DeepEqual(input, newInput()) {
+		t.Errorf("MapToStruct() modified input: %v", input)
+	}
+
+	flat = Flatten(map[string]any{"doc": bson.M{"id": 1, "list": bson.A{"x"}}})
+	expected = map[string]any{"doc.id": 1, "doc.list.0": "x"}
+	if !reflect.DeepEqual(flat, expected) {
+		t.Errorf("Flatten() with bson = %v, want %v", flat, expected)
+	}
+}
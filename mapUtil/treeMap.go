@@ -15,10 +15,10 @@ const (
 )
 
 var _ IMap[string, int] = (*TreeMap[string, int])(nil)
-var _ bson.Marshaler = (*TreeMap)(nil)
-var _ bson.Unmarshaler = (*TreeMap)(nil)
-var _ json.Marshaler = (*TreeMap)(nil)
-var _ json.Unmarshaler = (*TreeMap)(nil)
+var _ bson.Marshaler = (*TreeMap[string, int])(nil)
+var _ bson.Unmarshaler = (*TreeMap[string, int])(nil)
+var _ json.Marshaler = (*TreeMap[string, int])(nil)
+var _ json.Unmarshaler = (*TreeMap[string, int])(nil)
 
 // TreeMap 是一个基于红黑树实现的并发安全的有序映射
 type TreeMap[K comparable, V any] struct {
@@ -561,6 +561,89 @@ func (tm *TreeMap[K, V]) lastNode(n *node[K, V]) *node[K, V] {
 	return n
 }
 
+// FloorKey 返回小于等于给定键的最大键
+func (tm *TreeMap[K, V]) FloorKey(key K) (K, bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return nodeKey(tm.floorNode(key, true))
+}
+
+// LowerKey 返回严格小于给定键的最大键
+func (tm *TreeMap[K, V]) LowerKey(key K) (K, bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return nodeKey(tm.floorNode(key, false))
+}
+
+// CeilingKey 返回大于等于给定键的最小键
+func (tm *TreeMap[K, V]) CeilingKey(key K) (K, bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return nodeKey(tm.ceilingNode(key, true))
+}
+
+// HigherKey 返回严格大于给定键的最小键
+func (tm *TreeMap[K, V]) HigherKey(key K) (K, bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return nodeKey(tm.ceilingNode(key, false))
+}
+
+// floorNode 内部方法，查找小于（inclusive为true时小于等于）给定键的最大节点
+func (tm *TreeMap[K, V]) floorNode(key K, inclusive bool) *node[K, V] {
+	var result *node[K, V]
+	current := tm.root
+	for current != nil {
+		if tm.less(current.key, key) {
+			result = current
+			current = current.right
+		} else if tm.less(key, current.key) || !inclusive {
+			current = current.left
+		} else {
+			return current
+		}
+	}
+	return result
+}
+
+// ceilingNode 内部方法，查找大于（inclusive为true时大于等于）给定键的最小节点
+func (tm *TreeMap[K, V]) ceilingNode(key K, inclusive bool) *node[K, V] {
+	var result *node[K, V]
+	current := tm.root
+	for current != nil {
+		if tm.less(key, current.key) {
+			result = current
+			current = current.left
+		} else if tm.less(current.key, key) || !inclusive {
+			current = current.right
+		} else {
+			return current
+		}
+	}
+	return result
+}
+
+// nodeKey 返回节点的键，节点为nil时返回零值和false
+func nodeKey[K comparable, V any](n *node[K, V]) (K, bool) {
+	if n == nil {
+		var zero K
+		return zero, false
+	}
+	return n.key, true
+}
+
+// RangeFrom 从大于等于from的第一个键开始按升序遍历
+// f: 遍历函数，返回false可提前终止遍历
+func (tm *TreeMap[K, V]) RangeFrom(from K, f func(key K, value V) bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	for n := tm.ceilingNode(from, true); n != nil; n = tm.successor(n) {
+		if !f(n.key, n.value) {
+			return
+		}
+	}
+}
+
 // Range 遍历映射中的键值对
 // f: 遍历函数，返回false可提前终止遍历
 func (tm *TreeMap[K, V]) Range(f func(key K, value V) bool) {
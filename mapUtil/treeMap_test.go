@@ -893,3 +893,80 @@ func BenchmarkTreeMapRemove(b *testing.B) {
 		b.StopTimer()
 	}
 }
+
+// TestTreeMapNavigableKeys 测试FloorKey、CeilingKey、LowerKey、HigherKey方法
+func TestTreeMapNavigableKeys(t *testing.T) {
+	tm := NewTreeMap[int, string](func(a, b int) bool {
+		return a < b
+	})
+
+	// 测试空树
+	if _, ok := tm.FloorKey(1); ok {
+		t.Error("空树的FloorKey应返回false")
+	}
+	if _, ok := tm.CeilingKey(1); ok {
+		t.Error("空树的CeilingKey应返回false")
+	}
+
+	for _, v := range []int{10, 20, 30, 40, 50} {
+		tm.Put(v, fmt.Sprintf("value%d", v))
+	}
+
+	tests := []struct {
+		name   string
+		fn     func(int) (int, bool)
+		key    int
+		want   int
+		wantOk bool
+	}{
+		{"FloorKey存在", tm.FloorKey, 30, 30, true},
+		{"FloorKey介于之间", tm.FloorKey, 35, 30, true},
+		{"FloorKey小于最小值", tm.FloorKey, 5, 0, false},
+		{"LowerKey存在", tm.LowerKey, 30, 20, true},
+		{"LowerKey最小值", tm.LowerKey, 10, 0, false},
+		{"CeilingKey存在", tm.CeilingKey, 30, 30, true},
+		{"CeilingKey介于之间", tm.CeilingKey, 35, 40, true},
+		{"CeilingKey大于最大值", tm.CeilingKey, 55, 0, false},
+		{"HigherKey存在", tm.HigherKey, 30, 40, true},
+		{"HigherKey最大值", tm.HigherKey, 50, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.fn(tt.key)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("got (%d, %v), want (%d, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+// TestTreeMapRangeFrom 测试RangeFrom方法
+func TestTreeMapRangeFrom(t *testing.T) {
+	tm := NewTreeMap[int, string](func(a, b int) bool {
+		return a < b
+	})
+	for _, v := range []int{5, 2, 8, 1, 9, 3} {
+		tm.Put(v, fmt.Sprintf("value%d", v))
+	}
+
+	var keys []int
+	tm.RangeFrom(3, func(key int, value string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	expected := []int{3, 5, 8, 9}
+	if fmt.Sprint(keys) != fmt.Sprint(expected) {
+		t.Errorf("RangeFrom(3) = %v, want %v", keys, expected)
+	}
+
+	// 测试提前终止
+	keys = keys[:0]
+	tm.RangeFrom(4, func(key int, value string) bool {
+		keys = append(keys, key)
+		return len(keys) < 2
+	})
+	expected = []int{5, 8}
+	if fmt.Sprint(keys) != fmt.Sprint(expected) {
+		t.Errorf("RangeFrom(4) with break = %v, want %v", keys, expected)
+	}
+}
@@ -5,3 +5,13 @@ type Number interface {
 	~int | ~int8 | ~int16 | ~int32 | ~int64 |
 		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
 }
+
+// Float 约束，限制为所有浮点类型
+type Float interface {
+	~float32 | ~float64
+}
+
+// Real 约束，包含所有整数和浮点类型
+type Real interface {
+	Number | Float
+}
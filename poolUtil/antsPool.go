@@ -6,20 +6,32 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Tomatosky/jo-util/idUtil"
 	"github.com/Tomatosky/jo-util/logger"
+	"github.com/Tomatosky/jo-util/mapUtil"
 	"github.com/panjf2000/ants/v2"
 )
 
 var _ IPool = (*AntsPool)(nil)
 
 type AntsPool struct {
-	pool *ants.Pool
-	wg   sync.WaitGroup
+	pool    *ants.Pool
+	wg      sync.WaitGroup
+	metrics poolMetrics
+	tasks   *mapUtil.ConcurrentHashMap[string, *customTask] // 未完成的任务
+	ctx     context.Context                                 // 根 ctx，传给通过 SubmitCtx 提交的任务，关闭超时时取消
+	cancel  context.CancelFunc
 }
 
 func NewAntsPool(size int) *AntsPool {
 	pool, _ := ants.NewPool(size)
-	return &AntsPool{pool: pool}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &AntsPool{
+		pool:   pool,
+		tasks:  mapUtil.NewConcurrentHashMap[string, *customTask](),
+		ctx:    ctx,
+		cancel: cancel,
+	}
 }
 
 func (p *AntsPool) SubmitWithId(id any, task func()) {
@@ -27,15 +39,71 @@ func (p *AntsPool) SubmitWithId(id any, task func()) {
 }
 
 func (p *AntsPool) Submit(task func()) {
+	_ = p.TrySubmit(task)
+}
+
+// TrySubmitWithId 添加任务，id 仅用于兼容 IPool 和慢任务日志，线程池已关闭时返回错误
+func (p *AntsPool) TrySubmitWithId(id any, task func()) error {
+	if task == nil {
+		logger.Log.Error(fmt.Sprintf("%v", "task cannot be nil"))
+		panic("task cannot be nil")
+	}
+	return p.submit(newCustomTask(idUtil.RandomUUID(), id, task))
+}
+
+// SubmitCtx 添加可取消的任务，见 SubmitWithIdCtx
+func (p *AntsPool) SubmitCtx(ctx context.Context, task func(ctx context.Context)) error {
+	return p.SubmitWithIdCtx(ctx, nil, task)
+}
+
+// SubmitWithIdCtx 添加可取消的任务，id 仅用于慢任务日志和关闭报告
+// 任务开始前 ctx 已结束（如超过截止时间）时跳过该任务，任务收到的 ctx 在关闭线程池超时时也会被取消
+// ctx 已结束时返回 ctx 的错误，线程池已关闭时返回错误
+func (p *AntsPool) SubmitWithIdCtx(ctx context.Context, id any, task func(ctx context.Context)) error {
 	if task == nil {
 		logger.Log.Error(fmt.Sprintf("%v", "task cannot be nil"))
 		panic("task cannot be nil")
 	}
+	if err := ctx.Err(); err != nil {
+		p.metrics.submitted.Add(1)
+		p.metrics.skipped.Add(1)
+		return err
+	}
+	return p.submit(newCtxTask(ctx, idUtil.RandomUUID(), id, task))
+}
+
+func (p *AntsPool) submit(t *customTask) error {
+	p.metrics.submitted.Add(1)
 	p.wg.Add(1)
-	_ = p.pool.Submit(func() {
+	p.tasks.Put(t.taskID, t)
+	err := p.pool.Submit(func() {
 		defer p.wg.Done()
-		task()
+		defer p.tasks.Remove(t.taskID)
+		p.metrics.run("AntsPool", p.ctx, t)
 	})
+	if err != nil {
+		p.tasks.Remove(t.taskID)
+		p.wg.Done()
+		p.metrics.rejected.Add(1)
+	}
+	return err
+}
+
+// TrySubmit 添加任务，线程池已关闭时返回错误
+func (p *AntsPool) TrySubmit(task func()) error {
+	return p.TrySubmitWithId(nil, task)
+}
+
+// Stats 获取统计信息，Queued 为等待空闲 worker 的提交数量
+func (p *AntsPool) Stats() PoolStats {
+	stats := p.metrics.stats()
+	stats.Queued = p.pool.Waiting()
+	return stats
+}
+
+// SetSlowTaskThreshold 设置慢任务阈值，<=0 表示不检查
+func (p *AntsPool) SetSlowTaskThreshold(threshold time.Duration) {
+	p.metrics.slow.Store(int64(threshold))
 }
 
 // ScheduleAtFixedRate 类似于Java的scheduleAtFixedRate
@@ -92,11 +160,23 @@ func (p *AntsPool) scheduleNextWithDelay(delay time.Duration, task func(), done
 	}
 }
 
+// Shutdown 关闭线程池，超时时取消任务的 ctx 并记录仍未完成的任务
 func (p *AntsPool) Shutdown(timeout time.Duration) (isTimeout bool) {
+	report := p.ShutdownWithReport(timeout)
+	if report.TimedOut {
+		logger.Log.Warn(fmt.Sprintf("AntsPool shutdown timeout, %s", report))
+	}
+	return report.TimedOut
+}
+
+// ShutdownWithReport 关闭线程池，等待已提交的任务执行完成
+// 超时时取消传给任务的根 ctx，并返回仍在等待和执行的任务
+func (p *AntsPool) ShutdownWithReport(timeout time.Duration) ShutdownReport {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	defer p.pool.Release()
+	defer p.cancel()
 
 	// 创建一个通道用于通知等待完成
 	done := make(chan struct{})
@@ -108,8 +188,8 @@ func (p *AntsPool) Shutdown(timeout time.Duration) (isTimeout bool) {
 	// 使用select实现超时控制
 	select {
 	case <-done:
-		return false
+		return ShutdownReport{}
 	case <-ctx.Done():
-		return true
+		return shutdownReport(p.tasks)
 	}
 }
@@ -0,0 +1,253 @@
+package poolUtil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tomatosky/jo-util/dateUtil"
+)
+
+// CronSchedule 解析后的 cron 表达式
+type CronSchedule struct {
+	second, minute, hour, dom, month, dow uint64 // 每个字段允许的取值，按位表示
+	domAny, dowAny                        bool   // 日和星期字段是否为 * 或 ?
+	loc                                   *time.Location
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondField = cronField{name: "second", min: 0, max: 59}
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}}
+	// 星期允许 0-7，0 和 7 都表示周日
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// ParseCron 解析 cron 表达式，默认使用 dateUtil.Loc 时区
+// 支持5个字段（分 时 日 月 星期）和6个字段（秒 分 时 日 月 星期）
+// 字段支持 *、?、数字、范围 a-b、步长 */n 和 a-b/n、逗号分隔的列表，月份和星期支持英文缩写
+// 支持 @yearly、@monthly、@weekly、@daily、@hourly 等宏，可使用 TZ=Asia/Tokyo 或 CRON_TZ= 前缀指定时区
+// 与标准 cron 一致，日和星期都不为任意值时满足任一条件即可触发，1-31、0-6 等完整范围视为任意值
+// 例: ParseCron("0 5 * * MON-FRI") // 工作日每天5点
+func ParseCron(expr string) (*CronSchedule, error) {
+	return ParseCronIn(expr, dateUtil.Loc)
+}
+
+// ParseCronIn 使用指定的默认时区解析 cron 表达式，表达式中的 TZ 前缀优先
+func ParseCronIn(expr string, loc *time.Location) (*CronSchedule, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	spec := strings.TrimSpace(expr)
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		i := strings.IndexAny(spec, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid cron expression %q: missing fields after time zone", expr)
+		}
+		var err error
+		name := spec[strings.Index(spec, "=")+1 : i]
+		if loc, err = time.LoadLocation(name); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		spec = strings.TrimSpace(spec[i:])
+	}
+	if strings.HasPrefix(spec, "@") {
+		macro, ok := cronMacros[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("invalid cron expression %q: unknown macro", expr)
+		}
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 or 6 fields, got %d", expr, len(fields))
+	}
+
+	s := &CronSchedule{loc: loc}
+	targets := []*uint64{&s.second, &s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, f := range []cronField{secondField, minuteField, hourField, domField, monthField, dowField} {
+		mask, err := f.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		*targets[i] = mask
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	// 按解析后的取值判断是否为任意值，1-31、0-6、*/1 等与 * 等价
+	s.domAny = s.dom == domField.mask()
+	s.dowAny = s.dow == cronField{min: 0, max: 6}.mask()
+	return s, nil
+}
+
+// parse 解析单个字段
+func (f cronField) parse(field string) (uint64, error) {
+	var result uint64
+	for _, part := range strings.Split(field, ",") {
+		expr, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepStr, f.name)
+			}
+		}
+
+		var lo, hi int
+		switch {
+		case expr == "*" || expr == "?":
+			if expr == "?" && f.name != domField.name && f.name != dowField.name {
+				return 0, fmt.Errorf("'?' is not allowed in %s field", f.name)
+			}
+			lo, hi = f.min, f.max
+			if f.name == dowField.name {
+				hi = 6
+			}
+		default:
+			loStr, hiStr, isRange := strings.Cut(expr, "-")
+			var err error
+			if lo, err = f.value(loStr); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(hiStr); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// a/n 表示从 a 开始到最大值，星期字段的 7 与 0 同为周日，只到 6 避免重复计入周日
+				hi = f.max
+				if f.name == dowField.name {
+					hi = max(lo, 6)
+				}
+			}
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range %q in %s field", expr, f.name)
+		}
+		for v := lo; v <= hi; v += step {
+			result |= 1 << v
+		}
+	}
+	return result, nil
+}
+
+// mask 字段取值范围内的所有值
+func (f cronField) mask() uint64 {
+	return (1<<(f.max+1) - 1) &^ (1<<f.min - 1)
+}
+
+// value 解析字段中的单个取值
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", s, f.name)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d] in %s field", v, f.min, f.max, f.name)
+	}
+	return v, nil
+}
+
+// Location 表达式使用的时区
+func (s *CronSchedule) Location() *time.Location {
+	return s.loc
+}
+
+// Next 返回 t 之后的下一个触发时间，5年内没有触发时间时返回零值
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.loc).Truncate(time.Second).Add(time.Second)
+	yearLimit := t.Year() + 5
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+	for s.month&(1<<uint(t.Month())) == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.loc)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.loc)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, s.loc).Add(time.Hour)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		t = t.Truncate(time.Minute).Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+	for s.second&(1<<uint(t.Second())) == 0 {
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto wrap
+		}
+	}
+	return t
+}
+
+// NextN 返回 t 之后的 n 个触发时间
+func (s *CronSchedule) NextN(t time.Time, n int) []time.Time {
+	result := make([]time.Time, 0, max(n, 0))
+	for len(result) < n {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+// dayMatches 日和星期是否满足条件
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
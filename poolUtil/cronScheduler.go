@@ -0,0 +1,300 @@
+package poolUtil
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Tomatosky/jo-util/dateUtil"
+	"github.com/Tomatosky/jo-util/logger"
+)
+
+// OverlapPolicy 任务触发时上一次执行尚未结束的处理策略
+type OverlapPolicy int
+
+const (
+	OverlapSkip  OverlapPolicy = iota // 跳过本次执行（默认）
+	OverlapQueue                      // 上一次结束后补执行，多次触发依次排队
+	OverlapAllow                      // 允许并发执行
+)
+
+// CronSchedulerOpt CronScheduler 的配置
+type CronSchedulerOpt struct {
+	Pool     IPool          // 执行任务的线程池，必填
+	Location *time.Location // 表达式的默认时区，默认 dateUtil.Loc
+}
+
+// CronEntry 任务的调度信息
+type CronEntry struct {
+	Name string    `json:"name" bson:"name"`
+	Spec string    `json:"spec" bson:"spec"`
+	Next time.Time `json:"next" bson:"next"` // 下一次触发时间
+	Prev time.Time `json:"prev" bson:"prev"` // 上一次触发时间，未触发过时为零值
+}
+
+// CronScheduler 按 cron 表达式在线程池中执行任务，可在运行时添加和移除任务
+type CronScheduler struct {
+	pool    IPool
+	loc     *time.Location
+	mu      sync.Mutex
+	jobs    map[string]*cronJob
+	changed chan struct{} // 任务变化时通知调度协程重新计算等待时间
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+type cronJob struct {
+	name     string
+	spec     string
+	schedule *CronSchedule
+	task     func()
+	overlap  OverlapPolicy
+	next     time.Time
+	prev     time.Time
+
+	mu      sync.Mutex
+	running int  // 正在执行的数量
+	pending int  // OverlapQueue 策略下排队等待执行的数量
+	removed bool // 是否已被移除
+}
+
+// NewCronScheduler 创建调度器，需调用 Start 开始调度
+func NewCronScheduler(opt *CronSchedulerOpt) *CronScheduler {
+	if opt == nil || opt.Pool == nil {
+		logger.Log.Error(fmt.Sprintf("%v", "pool cannot be nil"))
+		panic("pool cannot be nil")
+	}
+	loc := opt.Location
+	if loc == nil {
+		loc = dateUtil.Loc
+	}
+	return &CronScheduler{
+		pool:    opt.Pool,
+		loc:     loc,
+		jobs:    make(map[string]*cronJob),
+		changed: make(chan struct{}, 1),
+	}
+}
+
+// AddJob 添加任务，name 不能重复
+// overlap 为可选的重叠策略，默认 OverlapSkip
+// 例: AddJob("dailyReset", "@daily", reset) 或 AddJob("weeklyEvent", "0 20 * * FRI", start, OverlapQueue)
+func (s *CronScheduler) AddJob(name, spec string, task func(), overlap ...OverlapPolicy) error {
+	if name == "" {
+		return errors.New("job name cannot be empty")
+	}
+	if task == nil {
+		return errors.New("task cannot be nil")
+	}
+	schedule, err := ParseCronIn(spec, s.loc)
+	if err != nil {
+		return err
+	}
+	next := schedule.Next(time.Now())
+	if next.IsZero() {
+		return fmt.Errorf("cron expression %q never fires", spec)
+	}
+	job := &cronJob{
+		name:     name,
+		spec:     spec,
+		schedule: schedule,
+		task:     task,
+		next:     next,
+	}
+	if len(overlap) > 0 {
+		job.overlap = overlap[0]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("job %s already exists", name)
+	}
+	s.jobs[name] = job
+	s.notify()
+	return nil
+}
+
+// RemoveJob 移除任务，正在执行的任务不受影响，排队等待的执行将被丢弃
+func (s *CronScheduler) RemoveJob(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[name]
+	if !ok {
+		return false
+	}
+	delete(s.jobs, name)
+	job.mu.Lock()
+	job.removed = true
+	job.mu.Unlock()
+	s.notify()
+	return true
+}
+
+// Entries 返回所有任务的调度信息，按下一次触发时间排序
+func (s *CronScheduler) Entries() []CronEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make([]CronEntry, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		entries = append(entries, CronEntry{Name: job.name, Spec: job.spec, Next: job.next, Prev: job.prev})
+	}
+	slices.SortFunc(entries, func(a, b CronEntry) int {
+		return a.Next.Compare(b.Next)
+	})
+	return entries
+}
+
+// NextFireTimes 返回任务接下来的 n 个触发时间，任务不存在时返回false
+func (s *CronScheduler) NextFireTimes(name string, n int) ([]time.Time, bool) {
+	s.mu.Lock()
+	job, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return job.schedule.NextN(time.Now(), n), true
+}
+
+// Start 开始调度，重复调用无效
+func (s *CronScheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+// Stop 停止调度并等待调度协程退出，已提交到线程池的任务不受影响，之后可再次 Start
+func (s *CronScheduler) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// run 调度循环
+func (s *CronScheduler) run(stop, done chan struct{}) {
+	defer close(done)
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		now := time.Now()
+		var due []*cronJob
+		var earliest time.Time
+		s.mu.Lock()
+		for _, job := range s.jobs {
+			if !job.next.After(now) {
+				// 错过的触发只执行一次
+				job.prev = job.next
+				job.next = job.schedule.Next(now)
+				due = append(due, job)
+			}
+			if !job.next.IsZero() && (earliest.IsZero() || job.next.Before(earliest)) {
+				earliest = job.next
+			}
+		}
+		s.mu.Unlock()
+
+		for _, job := range due {
+			s.dispatch(job)
+		}
+
+		var fire <-chan time.Time
+		if !earliest.IsZero() {
+			timer.Reset(time.Until(earliest))
+			fire = timer.C
+		}
+		select {
+		case <-fire:
+		case <-s.changed:
+			timer.Stop()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// dispatch 按重叠策略提交任务
+func (s *CronScheduler) dispatch(job *cronJob) {
+	job.mu.Lock()
+	if job.running > 0 {
+		switch job.overlap {
+		case OverlapSkip:
+			job.mu.Unlock()
+			logger.Log.Warn(fmt.Sprintf("cron job %s is still running, skip", job.name))
+			return
+		case OverlapQueue:
+			job.pending++
+			job.mu.Unlock()
+			return
+		}
+	}
+	job.running++
+	job.mu.Unlock()
+
+	err := trySubmit(s.pool, func() { s.execute(job) }, func() {
+		logger.Log.Warn(fmt.Sprintf("cron job %s is discarded by pool", job.name))
+		s.abandon(job)
+	})
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("cron job %s submit error: %v", job.name, err))
+		s.abandon(job)
+	}
+}
+
+// abandon 提交的执行被拒绝或被线程池丢弃时释放执行状态，等待该执行的排队触发一并丢弃
+func (s *CronScheduler) abandon(job *cronJob) {
+	job.mu.Lock()
+	job.running--
+	if job.running == 0 {
+		job.pending = 0
+	}
+	job.mu.Unlock()
+}
+
+// execute 执行任务，OverlapQueue 策略下依次执行排队的触发
+func (s *CronScheduler) execute(job *cronJob) {
+	for {
+		s.runTask(job)
+		job.mu.Lock()
+		if job.pending > 0 && !job.removed {
+			job.pending--
+			job.mu.Unlock()
+			continue
+		}
+		job.pending = 0
+		job.running--
+		job.mu.Unlock()
+		return
+	}
+}
+
+func (s *CronScheduler) runTask(job *cronJob) {
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Log.Error(fmt.Sprintf("cron job %s err=%v", job.name, err))
+		}
+	}()
+	job.task()
+}
+
+// notify 通知调度协程任务已变化，调用时需持有锁
+func (s *CronScheduler) notify() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,199 @@
+package poolUtil
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCronScheduler(t *testing.T) {
+	t.Run("Jobs", func(t *testing.T) {
+		pool := NewAntsPool(2)
+		defer pool.Shutdown(time.Second)
+		s := NewCronScheduler(&CronSchedulerOpt{Pool: pool})
+
+		if err := s.AddJob("daily", "@daily", func() {}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.AddJob("hourly", "0 * * * *", func() {}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.AddJob("daily", "@daily", func() {}); err == nil {
+			t.Error("Duplicate job name should fail")
+		}
+		if err := s.AddJob("bad", "* * *", func() {}); err == nil {
+			t.Error("Invalid expression should fail")
+		}
+		if err := s.AddJob("never", "0 0 30 2 *", func() {}); err == nil {
+			t.Error("Expression that never fires should fail")
+		}
+		if err := s.AddJob("nil", "@daily", nil); err == nil {
+			t.Error("Nil task should fail")
+		}
+
+		entries := s.Entries()
+		if len(entries) != 2 || entries[0].Name != "hourly" || entries[1].Name != "daily" {
+			t.Errorf("Unexpected entries %+v", entries)
+		}
+		times, ok := s.NextFireTimes("daily", 3)
+		if !ok || len(times) != 3 || times[1].Sub(times[0]) != 24*time.Hour {
+			t.Errorf("Unexpected fire times %v", times)
+		}
+		if !s.RemoveJob("daily") || s.RemoveJob("daily") {
+			t.Error("RemoveJob should succeed only once")
+		}
+		if _, ok := s.NextFireTimes("daily", 1); ok {
+			t.Error("Removed job should not have fire times")
+		}
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 2, QueueSize: 10})
+		defer pool.Shutdown(time.Second)
+		s := NewCronScheduler(&CronSchedulerOpt{Pool: pool})
+		s.Start()
+		s.Start()
+		defer s.Stop()
+
+		var count atomic.Int32
+		fired := make(chan struct{}, 10)
+		// 调度开始后添加任务
+		if err := s.AddJob("everySecond", "* * * * * *", func() {
+			count.Add(1)
+			fired <- struct{}{}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-fired:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Job was not fired")
+		}
+		if entry := s.Entries()[0]; entry.Prev.IsZero() || !entry.Next.After(entry.Prev) {
+			t.Errorf("Unexpected entry %+v", entry)
+		}
+
+		s.RemoveJob("everySecond")
+		time.Sleep(100 * time.Millisecond) // 等待已提交的执行结束
+		n := count.Load()
+		time.Sleep(1200 * time.Millisecond)
+		if count.Load() != n {
+			t.Error("Removed job should not fire")
+		}
+	})
+}
+
+func TestCronSchedulerOverlap(t *testing.T) {
+	tests := []struct {
+		name          string
+		overlap       OverlapPolicy
+		wantRuns      int32
+		wantMaxActive int32
+	}{
+		{"Skip", OverlapSkip, 1, 1},
+		{"Queue", OverlapQueue, 3, 1},
+		{"Allow", OverlapAllow, 3, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NewAntsPool(4)
+			defer pool.Shutdown(time.Second)
+			s := NewCronScheduler(&CronSchedulerOpt{Pool: pool})
+
+			release := make(chan struct{})
+			var runs, active, maxActive atomic.Int32
+			var wg sync.WaitGroup
+			wg.Add(int(tt.wantRuns))
+			job := &cronJob{name: tt.name, overlap: tt.overlap, task: func() {
+				defer wg.Done()
+				runs.Add(1)
+				n := active.Add(1)
+				for {
+					m := maxActive.Load()
+					if n <= m || maxActive.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				<-release
+				active.Add(-1)
+			}}
+
+			// 模拟任务执行期间连续触发3次
+			for i := 0; i < 3; i++ {
+				s.dispatch(job)
+			}
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			wg.Wait()
+			if runs.Load() != tt.wantRuns || maxActive.Load() != tt.wantMaxActive {
+				t.Errorf("runs = %d, max active = %d, want %d, %d", runs.Load(), maxActive.Load(), tt.wantRuns, tt.wantMaxActive)
+			}
+		})
+	}
+}
+
+func TestCronSchedulerDiscarded(t *testing.T) {
+	for _, overlap := range []OverlapPolicy{OverlapSkip, OverlapQueue} {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectDiscard})
+		s := NewCronScheduler(&CronSchedulerOpt{Pool: pool})
+
+		fired := make(chan struct{}, 10)
+		job := &cronJob{name: "discarded", overlap: overlap, task: func() { fired <- struct{}{} }}
+		// 线程池已满，本次触发被丢弃，不应残留执行状态
+		s.dispatch(job)
+		job.mu.Lock()
+		running, pending := job.running, job.pending
+		job.mu.Unlock()
+		if running != 0 || pending != 0 {
+			t.Fatalf("Overlap %d: discarded run left running=%d pending=%d", overlap, running, pending)
+		}
+
+		// 线程池空闲后再次触发能正常执行
+		release()
+		for pool.GetTaskCount(0) != 0 {
+			time.Sleep(time.Millisecond)
+		}
+		s.dispatch(job)
+		select {
+		case <-fired:
+		case <-time.After(time.Second):
+			t.Fatalf("Overlap %d: job should fire again after capacity frees", overlap)
+		}
+		pool.Shutdown(time.Second)
+	}
+}
+
+func TestCronSchedulerEvicted(t *testing.T) {
+	for _, overlap := range []OverlapPolicy{OverlapSkip, OverlapQueue} {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectDiscardOldest})
+		s := NewCronScheduler(&CronSchedulerOpt{Pool: pool})
+
+		fired := make(chan struct{}, 10)
+		job := &cronJob{name: "evicted", overlap: overlap, task: func() { fired <- struct{}{} }}
+		// 本次触发进入队列后被新提交的任务挤掉，不应残留执行状态
+		s.dispatch(job)
+		s.dispatch(job)
+		if err := pool.TrySubmitWithId(0, func() {}); err != nil {
+			t.Fatal(err)
+		}
+		job.mu.Lock()
+		running, pending := job.running, job.pending
+		job.mu.Unlock()
+		if running != 0 || pending != 0 {
+			t.Fatalf("Overlap %d: evicted run left running=%d pending=%d", overlap, running, pending)
+		}
+
+		release()
+		for pool.GetTaskCount(0) != 0 {
+			time.Sleep(time.Millisecond)
+		}
+		s.dispatch(job)
+		select {
+		case <-fired:
+		case <-time.After(time.Second):
+			t.Fatalf("Overlap %d: job should fire again after eviction", overlap)
+		}
+		pool.Shutdown(time.Second)
+	}
+}
@@ -0,0 +1,85 @@
+package poolUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tomatosky/jo-util/dateUtil"
+)
+
+func TestParseCron(t *testing.T) {
+	at := func(year int, month time.Month, day, hour, min, sec int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, 0, dateUtil.Loc)
+	}
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want []time.Time
+	}{
+		// 2026-10-17 为周六
+		{"0 5 * * MON-FRI", at(2026, 10, 17, 10, 0, 0), []time.Time{at(2026, 10, 19, 5, 0, 0), at(2026, 10, 20, 5, 0, 0)}},
+		{"*/15 * * * *", at(2026, 10, 17, 10, 7, 30), []time.Time{at(2026, 10, 17, 10, 15, 0), at(2026, 10, 17, 10, 30, 0)}},
+		{"0 0 31 * *", at(2026, 1, 31, 0, 0, 0), []time.Time{at(2026, 3, 31, 0, 0, 0), at(2026, 5, 31, 0, 0, 0)}},
+		{"0 0 29 2 *", at(2026, 1, 1, 0, 0, 0), []time.Time{at(2028, 2, 29, 0, 0, 0)}},
+		{"30 * * * * *", at(2026, 10, 17, 10, 0, 30), []time.Time{at(2026, 10, 17, 10, 1, 30), at(2026, 10, 17, 10, 2, 30)}},
+		{"0 9-17/4 * * *", at(2026, 10, 17, 0, 0, 0), []time.Time{at(2026, 10, 17, 9, 0, 0), at(2026, 10, 17, 13, 0, 0), at(2026, 10, 17, 17, 0, 0)}},
+		{"0 0 1,15 jan,jul *", at(2026, 1, 10, 0, 0, 0), []time.Time{at(2026, 1, 15, 0, 0, 0), at(2026, 7, 1, 0, 0, 0)}},
+		{"0 0 * * 7", at(2026, 10, 17, 0, 0, 0), []time.Time{at(2026, 10, 18, 0, 0, 0)}},
+		{"0 0 * * 5-7", at(2026, 10, 17, 1, 0, 0), []time.Time{at(2026, 10, 18, 0, 0, 0), at(2026, 10, 23, 0, 0, 0)}},
+		// 日和星期同时指定时满足任一条件
+		{"0 0 13 * FRI", at(2026, 10, 1, 0, 0, 0), []time.Time{at(2026, 10, 2, 0, 0, 0), at(2026, 10, 9, 0, 0, 0), at(2026, 10, 13, 0, 0, 0)}},
+		{"0 0 ? * FRI", at(2026, 10, 1, 0, 0, 0), []time.Time{at(2026, 10, 2, 0, 0, 0), at(2026, 10, 9, 0, 0, 0)}},
+		// 完整范围与 * 等价，不触发任一条件的规则
+		{"0 0 1-31 * FRI", at(2026, 10, 1, 0, 0, 0), []time.Time{at(2026, 10, 2, 0, 0, 0), at(2026, 10, 9, 0, 0, 0)}},
+		{"0 0 13 * 0-6", at(2026, 10, 1, 0, 0, 0), []time.Time{at(2026, 10, 13, 0, 0, 0), at(2026, 11, 13, 0, 0, 0)}},
+		{"0 0 13 * */1", at(2026, 10, 1, 0, 0, 0), []time.Time{at(2026, 10, 13, 0, 0, 0), at(2026, 11, 13, 0, 0, 0)}},
+		{"0 0 13 * 0/1", at(2026, 10, 1, 0, 0, 0), []time.Time{at(2026, 10, 13, 0, 0, 0), at(2026, 11, 13, 0, 0, 0)}},
+		// 星期字段的 a/n 不会因 7 计入周日
+		{"* * * * * 1/2", at(2026, 10, 17, 23, 59, 59), []time.Time{at(2026, 10, 19, 0, 0, 0)}},
+		{"0 0 0 * * 7/2", at(2026, 10, 17, 0, 0, 0), []time.Time{at(2026, 10, 18, 0, 0, 0), at(2026, 10, 25, 0, 0, 0)}},
+		{"@daily", at(2026, 12, 31, 12, 0, 0), []time.Time{at(2027, 1, 1, 0, 0, 0)}},
+		{"@hourly", at(2026, 10, 17, 10, 0, 0), []time.Time{at(2026, 10, 17, 11, 0, 0)}},
+		{"@weekly", at(2026, 10, 17, 10, 0, 0), []time.Time{at(2026, 10, 18, 0, 0, 0)}},
+		{"@monthly", at(2026, 10, 17, 10, 0, 0), []time.Time{at(2026, 11, 1, 0, 0, 0)}},
+		{"@yearly", at(2026, 10, 17, 10, 0, 0), []time.Time{at(2027, 1, 1, 0, 0, 0)}},
+		{"TZ=UTC 0 0 * * *", at(2026, 10, 17, 10, 0, 0), []time.Time{time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)}},
+		{"CRON_TZ=UTC @daily", at(2026, 10, 17, 10, 0, 0), []time.Time{time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)}},
+	}
+	for _, tt := range tests {
+		s, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q) error: %v", tt.expr, err)
+			continue
+		}
+		got := s.NextN(tt.from, len(tt.want))
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseCron(%q).NextN() = %v, want %v", tt.expr, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("ParseCron(%q) fire %d = %v, want %v", tt.expr, i, got[i], tt.want[i])
+			}
+		}
+	}
+
+	invalid := []string{
+		"", "* * * *", "* * * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8",
+		"@every", "*/0 * * * *", "5-1 * * * *", "? * * * *", "a * * * *", "1- * * * *", "TZ=Bad/Zone * * * * *", "TZ=UTC",
+	}
+	for _, expr := range invalid {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) should fail", expr)
+		}
+	}
+
+	// 永远不会触发的表达式
+	s, _ := ParseCron("0 0 30 2 *")
+	if next := s.Next(time.Now()); !next.IsZero() {
+		t.Errorf("Expected zero time, got %v", next)
+	}
+	if s.Location() != dateUtil.Loc {
+		t.Error("Expected default location dateUtil.Loc")
+	}
+}
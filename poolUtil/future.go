@@ -0,0 +1,260 @@
+package poolUtil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Tomatosky/jo-util/logger"
+)
+
+// ErrFutureCancelled 任务已取消
+var ErrFutureCancelled = errors.New("future is cancelled")
+
+// PanicError 任务发生 panic 时返回的错误
+type PanicError struct {
+	Value any    // panic 的值
+	Stack []byte // panic 时的调用栈
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("task panic: %v", e.Value)
+}
+
+// trySubmitter 提交失败时能返回错误的线程池
+type trySubmitter interface {
+	TrySubmit(task func()) error
+	TrySubmitWithId(id any, task func()) error
+}
+
+// trySubmit 提交任务，线程池支持时返回提交失败的错误，并在提交成功的任务被丢弃时调用 onDiscard
+func trySubmit(pool IPool, task func(), onDiscard func()) error {
+	if p, ok := pool.(DiscardNotifier); ok {
+		return p.TrySubmitOnDiscard(task, onDiscard)
+	}
+	if p, ok := pool.(trySubmitter); ok {
+		return p.TrySubmit(task)
+	}
+	pool.Submit(task)
+	return nil
+}
+
+// trySubmitWithId 按 id 提交任务，线程池支持时返回提交失败的错误，并在提交成功的任务被丢弃时调用 onDiscard
+func trySubmitWithId(pool IPool, id any, task func(), onDiscard func()) error {
+	if p, ok := pool.(DiscardNotifier); ok {
+		return p.TrySubmitWithIdOnDiscard(id, task, onDiscard)
+	}
+	if p, ok := pool.(trySubmitter); ok {
+		return p.TrySubmitWithId(id, task)
+	}
+	pool.SubmitWithId(id, task)
+	return nil
+}
+
+// Future 异步任务的结果
+type Future[T any] struct {
+	done      chan struct{}
+	once      sync.Once
+	value     T
+	err       error
+	mu        sync.Mutex
+	callbacks []func() // 完成时在完成的协程中依次执行
+}
+
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+// SubmitFunc 提交有返回值的任务，返回任务的 Future
+// 任务 panic 时 Future 返回 *PanicError，提交失败时返回提交的错误，提交成功后被 RejectDiscardOldest 策略丢弃时返回 ErrQueueFull
+// 注意：不支持 DiscardNotifier 的线程池在提交成功后丢弃任务时 Future 不会完成，需通过 Get 的 ctx 控制等待时间
+func SubmitFunc[T any](pool IPool, task func() (T, error)) *Future[T] {
+	f := newFuture[T]()
+	if err := trySubmit(pool, f.wrap(task), f.discard); err != nil {
+		var zero T
+		f.complete(zero, err)
+	}
+	return f
+}
+
+// SubmitFuncWithId 提交有返回值的任务，在 IdPool 中相同 id 的任务按提交顺序执行
+func SubmitFuncWithId[T any](pool IPool, id any, task func() (T, error)) *Future[T] {
+	f := newFuture[T]()
+	if err := trySubmitWithId(pool, id, f.wrap(task), f.discard); err != nil {
+		var zero T
+		f.complete(zero, err)
+	}
+	return f
+}
+
+// Get 等待任务完成并返回结果，ctx 结束时返回 ctx 的错误
+func (f *Future[T]) Get(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Done 返回任务完成时关闭的通道
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// IsDone 任务是否已完成，包括成功、失败和取消
+func (f *Future[T]) IsDone() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Cancel 取消任务，未开始的任务不再执行，已在执行的任务的结果将被丢弃
+// 任务已完成时返回false
+func (f *Future[T]) Cancel() bool {
+	var zero T
+	return f.complete(zero, ErrFutureCancelled)
+}
+
+// complete 设置结果，只有第一次调用生效
+func (f *Future[T]) complete(value T, err error) bool {
+	completed := false
+	var callbacks []func()
+	f.once.Do(func() {
+		f.value = value
+		f.err = err
+		f.mu.Lock()
+		callbacks = f.callbacks
+		f.callbacks = nil
+		close(f.done)
+		f.mu.Unlock()
+		completed = true
+	})
+	for _, cb := range callbacks {
+		cb()
+	}
+	return completed
+}
+
+// onDone 注册完成时执行的回调，已完成时立即在当前协程执行
+func (f *Future[T]) onDone(cb func()) {
+	f.mu.Lock()
+	select {
+	case <-f.done:
+		f.mu.Unlock()
+		cb()
+	default:
+		f.callbacks = append(f.callbacks, cb)
+		f.mu.Unlock()
+	}
+}
+
+// discard 提交成功的任务被线程池丢弃时以 ErrQueueFull 完成
+func (f *Future[T]) discard() {
+	var zero T
+	f.complete(zero, ErrQueueFull)
+}
+
+// wrap 将任务包装为提交给线程池的函数
+func (f *Future[T]) wrap(task func() (T, error)) func() {
+	return func() {
+		if f.IsDone() {
+			return
+		}
+		f.complete(callSafely(task))
+	}
+}
+
+// callSafely 执行任务，将 panic 转换为 *PanicError
+func callSafely[T any](task func() (T, error)) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Log.Error(fmt.Sprintf("err=%v", r))
+			var zero T
+			value, err = zero, &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return task()
+}
+
+// AllOf 所有任务成功后返回按顺序排列的结果，任一任务失败时立即返回该错误
+func AllOf[T any](futures ...*Future[T]) *Future[[]T] {
+	result := newFuture[[]T]()
+	values := make([]T, len(futures))
+	if len(futures) == 0 {
+		result.complete(values, nil)
+		return result
+	}
+	var remaining atomic.Int32
+	remaining.Store(int32(len(futures)))
+	for i, f := range futures {
+		f.onDone(func() {
+			if f.err != nil {
+				result.complete(nil, f.err)
+				return
+			}
+			values[i] = f.value
+			if remaining.Add(-1) == 0 {
+				result.complete(values, nil)
+			}
+		})
+	}
+	return result
+}
+
+// AnyOf 返回第一个成功完成的任务的结果，全部失败时返回所有错误
+func AnyOf[T any](futures ...*Future[T]) *Future[T] {
+	result := newFuture[T]()
+	if len(futures) == 0 {
+		var zero T
+		result.complete(zero, errors.New("no futures given"))
+		return result
+	}
+	var mu sync.Mutex
+	errs := make([]error, 0, len(futures))
+	for _, f := range futures {
+		f.onDone(func() {
+			if f.err == nil {
+				result.complete(f.value, nil)
+				return
+			}
+			mu.Lock()
+			errs = append(errs, f.err)
+			allFailed := len(errs) == len(futures)
+			mu.Unlock()
+			if allFailed {
+				var zero T
+				result.complete(zero, errors.Join(errs...))
+			}
+		})
+	}
+	return result
+}
+
+// Then 任务成功后使用其结果执行 fn，任务失败时直接返回该错误
+// fn 在完成 f 的协程中同步执行（通常是线程池的 worker，f 已完成时为调用 Then 的协程），不会创建新的协程
+// fn 应尽快返回，耗时操作可在 fn 中通过 SubmitFunc 提交到线程池
+func Then[T, R any](f *Future[T], fn func(T) (R, error)) *Future[R] {
+	result := newFuture[R]()
+	f.onDone(func() {
+		if result.IsDone() {
+			return
+		}
+		if f.err != nil {
+			var zero R
+			result.complete(zero, f.err)
+			return
+		}
+		result.complete(callSafely(func() (R, error) {
+			return fn(f.value)
+		}))
+	})
+	return result
+}
@@ -0,0 +1,221 @@
+package poolUtil
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFuture(t *testing.T) {
+	pools := map[string]func() IPool{
+		"AntsPool": func() IPool { return NewAntsPool(4) },
+		"IdPool":   func() IPool { return NewIdPool(&IdPoolOpt{PoolSize: 4, QueueSize: 16}) },
+	}
+	for name, newPool := range pools {
+		t.Run(name, func(t *testing.T) {
+			pool := newPool()
+			defer pool.Shutdown(time.Second)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			f := SubmitFunc(pool, func() (int, error) { return 42, nil })
+			if v, err := f.Get(ctx); err != nil || v != 42 {
+				t.Errorf("Get() = %d, %v", v, err)
+			}
+			if !f.IsDone() {
+				t.Error("Future should be done")
+			}
+
+			errTask := errors.New("task error")
+			f = SubmitFuncWithId(pool, 1, func() (int, error) { return 0, errTask })
+			if _, err := f.Get(ctx); !errors.Is(err, errTask) {
+				t.Errorf("Expected task error, got %v", err)
+			}
+
+			f = SubmitFunc(pool, func() (int, error) { panic("boom") })
+			_, err := f.Get(ctx)
+			var panicErr *PanicError
+			if !errors.As(err, &panicErr) || panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+				t.Errorf("Expected PanicError, got %v", err)
+			}
+		})
+	}
+
+	t.Run("SubmitAfterShutdown", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 1, QueueSize: 1})
+		pool.Shutdown(time.Second)
+		f := SubmitFunc(pool, func() (int, error) { return 1, nil })
+		if _, err := f.Get(context.Background()); !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("Expected ErrPoolClosed, got %v", err)
+		}
+
+		ants := NewAntsPool(1)
+		ants.Shutdown(time.Second)
+		if _, err := SubmitFunc(ants, func() (int, error) { return 1, nil }).Get(context.Background()); err == nil {
+			t.Error("Expected error from released AntsPool")
+		}
+	})
+
+	t.Run("DiscardedTask", func(t *testing.T) {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectDiscard})
+		defer pool.Shutdown(time.Second)
+		defer release()
+		var ran atomic.Bool
+		f := SubmitFuncWithId(pool, 0, func() (int, error) {
+			ran.Store(true)
+			return 1, nil
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if _, err := f.Get(ctx); !errors.Is(err, ErrQueueFull) {
+			t.Errorf("Expected ErrQueueFull, got %v", err)
+		}
+		if ran.Load() {
+			t.Error("Discarded task should not run")
+		}
+	})
+
+	t.Run("EvictedTask", func(t *testing.T) {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectDiscardOldest})
+		defer pool.Shutdown(time.Second)
+		defer release()
+		evicted := SubmitFuncWithId(pool, 0, func() (int, error) { return 1, nil })
+		kept := SubmitFuncWithId(pool, 0, func() (int, error) { return 2, nil })
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if _, err := evicted.Get(ctx); !errors.Is(err, ErrQueueFull) {
+			t.Errorf("Expected ErrQueueFull for evicted task, got %v", err)
+		}
+		release()
+		if v, err := kept.Get(ctx); err != nil || v != 2 {
+			t.Errorf("Expected (2, nil), got (%v, %v)", v, err)
+		}
+	})
+
+	t.Run("CancelAndTimeout", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 1, QueueSize: 4})
+		defer pool.Shutdown(time.Second)
+		release := make(chan struct{})
+		blocker := SubmitFuncWithId(pool, 0, func() (int, error) {
+			<-release
+			return 1, nil
+		})
+
+		var ran atomic.Bool
+		f := SubmitFuncWithId(pool, 0, func() (int, error) {
+			ran.Store(true)
+			return 2, nil
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if _, err := f.Get(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected deadline exceeded, got %v", err)
+		}
+		if !f.Cancel() {
+			t.Error("Cancel should succeed on pending future")
+		}
+		if f.Cancel() {
+			t.Error("Cancel should fail on completed future")
+		}
+		close(release)
+		if v, _ := blocker.Get(context.Background()); v != 1 {
+			t.Errorf("Expected 1, got %d", v)
+		}
+		// 等待队列中被取消的任务出队
+		_, _ = SubmitFuncWithId(pool, 0, func() (int, error) { return 0, nil }).Get(context.Background())
+		if ran.Load() {
+			t.Error("Cancelled task should not run")
+		}
+		if _, err := f.Get(context.Background()); !errors.Is(err, ErrFutureCancelled) {
+			t.Errorf("Expected ErrFutureCancelled, got %v", err)
+		}
+	})
+}
+
+func TestFutureCombinators(t *testing.T) {
+	pool := NewAntsPool(8)
+	defer pool.Shutdown(time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	delayed := func(v int, d time.Duration, err error) *Future[int] {
+		return SubmitFunc(pool, func() (int, error) {
+			time.Sleep(d)
+			return v, err
+		})
+	}
+
+	t.Run("AllOf", func(t *testing.T) {
+		all := AllOf(delayed(1, 30*time.Millisecond, nil), delayed(2, 0, nil), delayed(3, 10*time.Millisecond, nil))
+		if v, err := all.Get(ctx); err != nil || !reflect.DeepEqual(v, []int{1, 2, 3}) {
+			t.Errorf("AllOf() = %v, %v", v, err)
+		}
+
+		errFail := errors.New("fail")
+		start := time.Now()
+		all = AllOf(delayed(1, time.Second, nil), delayed(0, 0, errFail))
+		if _, err := all.Get(ctx); !errors.Is(err, errFail) {
+			t.Errorf("Expected fail error, got %v", err)
+		}
+		if time.Since(start) > 500*time.Millisecond {
+			t.Error("AllOf should fail fast")
+		}
+
+		if v, err := AllOf[int]().Get(ctx); err != nil || len(v) != 0 {
+			t.Errorf("AllOf() with no futures = %v, %v", v, err)
+		}
+	})
+
+	t.Run("AnyOf", func(t *testing.T) {
+		errFail := errors.New("fail")
+		anyF := AnyOf(delayed(0, 0, errFail), delayed(1, 50*time.Millisecond, nil), delayed(2, 10*time.Millisecond, nil))
+		if v, err := anyF.Get(ctx); err != nil || v != 2 {
+			t.Errorf("AnyOf() = %d, %v", v, err)
+		}
+
+		errOther := errors.New("other")
+		anyF = AnyOf(delayed(0, 0, errFail), delayed(0, 0, errOther))
+		if _, err := anyF.Get(ctx); !errors.Is(err, errFail) || !errors.Is(err, errOther) {
+			t.Errorf("Expected joined errors, got %v", err)
+		}
+		if _, err := AnyOf[int]().Get(ctx); err == nil {
+			t.Error("AnyOf() with no futures should fail")
+		}
+	})
+
+	t.Run("Then", func(t *testing.T) {
+		f := Then(delayed(21, 0, nil), func(v int) (string, error) {
+			return strconv.Itoa(v * 2), nil
+		})
+		if v, err := f.Get(ctx); err != nil || v != "42" {
+			t.Errorf("Then() = %q, %v", v, err)
+		}
+
+		errFail := errors.New("fail")
+		called := false
+		f = Then(delayed(0, 0, errFail), func(v int) (string, error) {
+			called = true
+			return "", nil
+		})
+		if _, err := f.Get(ctx); !errors.Is(err, errFail) || called {
+			t.Errorf("Expected propagated error without calling fn, got %v", err)
+		}
+
+		// 源 Future 已完成时 fn 在调用 Then 的协程中同步执行
+		done := SubmitFunc(pool, func() (int, error) { return 1, nil })
+		_, _ = done.Get(ctx)
+		if f = Then(done, func(v int) (string, error) { return "sync", nil }); !f.IsDone() {
+			t.Error("Then on completed future should run fn synchronously")
+		}
+
+		f = Then(delayed(0, 0, nil), func(v int) (string, error) { panic("then panic") })
+		var panicErr *PanicError
+		if _, err := f.Get(ctx); !errors.As(err, &panicErr) {
+			t.Errorf("Expected PanicError, got %v", err)
+		}
+	})
+}
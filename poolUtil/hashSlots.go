@@ -0,0 +1,61 @@
+package poolUtil
+
+import "slices"
+
+const minHashSlots = 4096 // 哈希槽的最少数量，也是 worker 数量的有效上限
+
+// hashSlots 一致性哈希槽，id 按取模分配到固定数量的槽，槽再分配给 worker
+// 初始分配与按 worker 数量取模一致，增减 worker 时只迁移必要的槽，只有少部分 id 会改变所在的 worker
+type hashSlots struct {
+	slots []*worker
+}
+
+// newHashSlots 创建哈希槽，槽数量为 worker 数量的整数倍，保证初始分配与取模一致
+func newHashSlots(workers []*worker) *hashSlots {
+	n := len(workers)
+	slots := make([]*worker, (minHashSlots+n-1)/n*n)
+	for i := range slots {
+		slots[i] = workers[i%n]
+	}
+	return &hashSlots{slots: slots}
+}
+
+// get 返回 key 所在槽的 worker
+func (h *hashSlots) get(key int64) *worker {
+	n := int64(len(h.slots))
+	return h.slots[(key%n+n)%n]
+}
+
+// rebalance 返回将槽平均分配给 workers 的新哈希槽，已有的 worker 保留原来的槽，只迁移超出份额和被移除的 worker 的槽
+// 原哈希槽不会被修改，提交方可以无锁读取
+func (h *hashSlots) rebalance(workers []*worker) *hashSlots {
+	slots := slices.Clone(h.slots)
+	n, total := len(workers), len(slots)
+	index := make(map[*worker]int, n)
+	for i, w := range workers {
+		index[w] = i
+	}
+	quota := func(i int) int {
+		if i < total%n {
+			return total/n + 1
+		}
+		return total / n
+	}
+
+	kept := make([]int, n)
+	var free []int
+	for s, w := range slots {
+		if i, ok := index[w]; ok && kept[i] < quota(i) {
+			kept[i]++
+		} else {
+			free = append(free, s)
+		}
+	}
+	for i, w := range workers {
+		for ; kept[i] < quota(i); kept[i]++ {
+			slots[free[0]] = w
+			free = free[1:]
+		}
+	}
+	return &hashSlots{slots: slots}
+}
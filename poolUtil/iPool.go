@@ -7,3 +7,10 @@ type IPool interface {
 	SubmitWithId(id any, task func())
 	Shutdown(timeout time.Duration) (isTimeout bool)
 }
+
+// DiscardNotifier 提交成功的任务之后可能被丢弃的线程池，如 RejectDiscardOldest 策略的 IdPool
+// 依赖任务一定执行的封装（如 Future、KeyedExecutor）通过 onDiscard 完成或清理被丢弃的任务
+type DiscardNotifier interface {
+	TrySubmitOnDiscard(task func(), onDiscard func()) error
+	TrySubmitWithIdOnDiscard(id any, task func(), onDiscard func()) error
+}
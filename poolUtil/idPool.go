@@ -2,7 +2,9 @@ package poolUtil
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,32 +17,78 @@ import (
 )
 
 var _ IPool = (*IdPool)(nil)
+var _ DiscardNotifier = (*IdPool)(nil)
+
+var (
+	// ErrPoolClosed 线程池已关闭
+	ErrPoolClosed = errors.New("pool is shut down")
+	// ErrQueueFull 任务队列已满
+	ErrQueueFull = errors.New("queue is full")
+)
+
+// RejectPolicy worker 队列已满时的拒绝策略
+type RejectPolicy int
+
+const (
+	RejectDiscard       RejectPolicy = iota // 丢弃新任务，记录日志并返回 ErrQueueFull（默认）
+	RejectBlock                             // 阻塞等待队列有空位，可通过 BlockTimeout 或 ctx 限制等待时间
+	RejectCallerRuns                        // 在提交任务的协程中直接执行，该任务不再与同 id 的任务保持顺序
+	RejectDiscardOldest                     // 丢弃队列中最早的任务后重新提交，被丢弃任务的提交方只能通过 TrySubmitWithIdOnDiscard 的回调得知
+	RejectReturnError                       // 拒绝新任务并返回 ErrQueueFull，不记录日志
+)
 
 type IdPool struct {
-	workers      []*worker
-	taskIdMap    *mapUtil.ConcurrentHashMap[string, int64]        // key: taskID(string), value: id
-	idTaskCounts *mapUtil.ConcurrentHashMap[int64, *atomic.Int32] // key: id, value: *atomic.Int32
-	cores        int64
-	running      atomic.Bool    // 控制服务运行状态
-	wg           sync.WaitGroup // 用于等待所有worker退出
+	mu           sync.Mutex                // 保护 workers、retiring，只在扩缩容、关闭和统计时使用
+	workers      []*worker                 // 分配了哈希槽的 worker
+	retiring     []*worker                 // 缩容后等待已分配的 id 执行完的 worker
+	slots        atomic.Pointer[hashSlots] // 为没有未完成任务的 id 选择 worker，扩缩容时整体替换
+	routes       [routeShards]routeShard   // 按 id 分片的路由，提交和完成任务时只锁 id 所在的分片
+	queueSize    int
+	taskIdMap    *mapUtil.ConcurrentHashMap[string, *customTask] // key: taskID(string), value: 未完成的任务
+	running      atomic.Bool                                     // 控制服务运行状态
+	wg           sync.WaitGroup                                  // 用于等待所有worker退出
 	poolName     string
+	rejectPolicy RejectPolicy
+	blockTimeout time.Duration
+	metrics      poolMetrics
+	ctx          context.Context // 根 ctx，传给通过 SubmitCtx 提交的任务，关闭超时时取消
+	cancel       context.CancelFunc
+	scaleStop    chan struct{} // 关闭自动扩缩容，未开启时为nil
 }
 
 type worker struct {
-	idPool *IdPool          // 反向引用 IdPool
-	queue  chan *customTask // 任务通道
-	done   chan struct{}    // 关闭信号
+	idPool  *IdPool          // 反向引用 IdPool
+	queue   chan *customTask // 任务通道
+	done    chan struct{}    // 关闭信号
+	once    sync.Once        // 保证关闭信号只发送一次
+	sendMu  sync.RWMutex     // 发送任务时持有读锁，worker 退出时持有写锁，保证退出后没有任务留在队列中
+	exited  bool             // worker 是否已退出，退出后提交的任务返回 ErrPoolClosed
+	keys    atomic.Int32     // 分配到该 worker 的 id 数量
+	retired atomic.Bool      // 是否已被缩容移除
+}
+
+const routeShards = 64 // 路由分片数量，需为2的幂
+
+// routeShard 一部分 id 的路由
+type routeShard struct {
+	mu     sync.Mutex
+	routes map[int64]keyRoute // key: id, value: id 所在的 worker 和未完成的任务数
 }
 
-type customTask struct {
-	taskID string
-	task   func()
+// keyRoute id 所在的 worker，id 有未完成的任务时保持不变，保证相同 id 的任务按顺序执行
+type keyRoute struct {
+	w       *worker
+	pending int32 // 未完成的任务数量
 }
 
 type IdPoolOpt struct {
-	PoolSize  int64
-	QueueSize int
-	PoolName  string
+	PoolSize          int64
+	QueueSize         int
+	PoolName          string
+	RejectPolicy      RejectPolicy  // 队列已满时的拒绝策略，默认 RejectDiscard
+	BlockTimeout      time.Duration // RejectBlock 策略的最长等待时间，<=0 表示一直等待
+	SlowTaskThreshold time.Duration // 慢任务阈值，任务执行时间超过该值时记录日志，<=0 表示不检查
+	AutoScale         *AutoScaleOpt // 根据队列长度自动扩缩容，nil 表示不开启
 }
 
 func NewIdPool(opt *IdPoolOpt) *IdPool {
@@ -50,20 +98,26 @@ func NewIdPool(opt *IdPoolOpt) *IdPool {
 	}
 
 	idPool := &IdPool{
-		cores:        opt.PoolSize,
 		workers:      make([]*worker, opt.PoolSize),
-		taskIdMap:    mapUtil.NewConcurrentHashMap[string, int64](),
-		idTaskCounts: mapUtil.NewConcurrentHashMap[int64, *atomic.Int32](),
+		queueSize:    opt.QueueSize,
+		taskIdMap:    mapUtil.NewConcurrentHashMap[string, *customTask](),
+		poolName:     opt.PoolName,
+		rejectPolicy: opt.RejectPolicy,
+		blockTimeout: opt.BlockTimeout,
 	}
+	idPool.ctx, idPool.cancel = context.WithCancel(context.Background())
+	idPool.metrics.slow.Store(int64(opt.SlowTaskThreshold))
 	idPool.running.Store(true)
+	for i := range idPool.routes {
+		idPool.routes[i].routes = make(map[int64]keyRoute)
+	}
 	// 初始化 workers
-	for i := int64(0); i < opt.PoolSize; i++ {
-		idPool.workers[i] = newWorker(idPool, opt.QueueSize)
-		idPool.wg.Add(1) // 为每个worker增加计数
-		go func(w *worker) {
-			defer idPool.wg.Done() // worker退出时减少计数
-			w.run()
-		}(idPool.workers[i])
+	for i := range idPool.workers {
+		idPool.workers[i] = idPool.startWorker()
+	}
+	idPool.slots.Store(newHashSlots(idPool.workers))
+	if opt.AutoScale != nil {
+		idPool.startAutoScale(opt.AutoScale)
 	}
 	return idPool
 }
@@ -72,59 +126,269 @@ func (i *IdPool) Submit(task func()) {
 	i.SubmitWithId(int32(randomUtil.RandomInt(0, 100000)), task)
 }
 
-// SubmitWithId 添加任务
+// TrySubmit 随机分配 worker 添加任务，队列已满时按拒绝策略处理
+func (i *IdPool) TrySubmit(task func()) error {
+	return i.TrySubmitWithId(int32(randomUtil.RandomInt(0, 100000)), task)
+}
+
+// SubmitWithId 添加任务，相同 id 的任务按提交顺序执行，提交失败时记录日志
 func (i *IdPool) SubmitWithId(id any, task func()) {
+	if err := i.TrySubmitWithId(id, task); err != nil {
+		logger.Log.Warn(fmt.Sprintf("%s submit task error: %v", i.poolName, err))
+	}
+}
+
+// TrySubmitWithId 添加任务，队列已满时按拒绝策略处理
+// 线程池已关闭时返回 ErrPoolClosed，任务被拒绝或丢弃时返回 ErrQueueFull，返回错误的任务不会执行
+func (i *IdPool) TrySubmitWithId(id any, task func()) error {
+	return i.TrySubmitWithIdCtx(context.Background(), id, task)
+}
+
+// TrySubmitWithIdCtx 与 TrySubmitWithId 相同，RejectBlock 策略下 ctx 结束时停止等待
+func (i *IdPool) TrySubmitWithIdCtx(ctx context.Context, id any, task func()) error {
+	return i.submit(ctx, id, newCustomTask(idUtil.RandomUUID(), id, task))
+}
+
+// TrySubmitOnDiscard 随机分配 worker 添加任务，见 TrySubmitWithIdOnDiscard
+func (i *IdPool) TrySubmitOnDiscard(task func(), onDiscard func()) error {
+	return i.TrySubmitWithIdOnDiscard(int32(randomUtil.RandomInt(0, 100000)), task, onDiscard)
+}
+
+// TrySubmitWithIdOnDiscard 与 TrySubmitWithId 相同，任务提交成功后被 RejectDiscardOldest 策略丢弃时调用 onDiscard
+// onDiscard 在触发丢弃的提交协程中执行，此时不持有线程池的锁，可以再次提交任务；提交返回错误时不会调用 onDiscard
+func (i *IdPool) TrySubmitWithIdOnDiscard(id any, task func(), onDiscard func()) error {
+	t := newCustomTask(idUtil.RandomUUID(), id, task)
+	t.onDiscard = onDiscard
+	return i.submit(context.Background(), id, t)
+}
+
+// SubmitCtx 随机分配 worker 添加可取消的任务，见 SubmitWithIdCtx
+func (i *IdPool) SubmitCtx(ctx context.Context, task func(ctx context.Context)) error {
+	return i.SubmitWithIdCtx(ctx, int32(randomUtil.RandomInt(0, 100000)), task)
+}
+
+// SubmitWithIdCtx 添加可取消的任务，相同 id 的任务按提交顺序执行
+// 任务开始前 ctx 已结束（如超过截止时间）时跳过该任务，任务收到的 ctx 在关闭线程池超时时也会被取消
+// ctx 已结束时返回 ctx 的错误，其他错误与 TrySubmitWithIdCtx 相同
+func (i *IdPool) SubmitWithIdCtx(ctx context.Context, id any, task func(ctx context.Context)) error {
+	if task == nil {
+		logger.Log.Error(fmt.Sprintf("%v", "task cannot be nil"))
+		panic("task cannot be nil")
+	}
+	if err := ctx.Err(); err != nil {
+		i.metrics.submitted.Add(1)
+		i.metrics.skipped.Add(1)
+		return err
+	}
+	return i.submit(ctx, id, newCtxTask(ctx, idUtil.RandomUUID(), id, task))
+}
+
+// submit 将任务发送到 id 对应的 worker，ctx 用于 RejectBlock 策略的等待
+func (i *IdPool) submit(ctx context.Context, id any, t *customTask) error {
+	i.metrics.submitted.Add(1)
+	t.key = convertor.ToInt64(id)
 	if !i.running.Load() {
-		return
+		i.metrics.rejected.Add(1)
+		return ErrPoolClosed
 	}
-	idInt64 := convertor.ToInt64(id)
-	// 生成唯一任务ID
-	taskID := idUtil.RandomUUID()
-	// 更新任务计数
-	v, _ := i.idTaskCounts.PutIfAbsent(idInt64, &atomic.Int32{})
-	v.Add(1)
+	// 关闭后才到达 worker 的任务由 send 返回 ErrPoolClosed
+	w := i.acquireRoute(t.key)
 	// 记录任务映射
-	i.taskIdMap.Put(taskID, idInt64)
-	// 选择 worker（哈希取模）
-	w := i.workers[idInt64%i.cores]
-	// 发送任务
+	i.taskIdMap.Put(t.taskID, t)
+	callerRuns, evicted, err := i.send(ctx, w, t)
+	// 在读锁外通知被丢弃的任务，回调中再次提交任务时不会与 worker 退出互相等待
+	for _, e := range evicted {
+		if e.onDiscard != nil {
+			e.onDiscard()
+		}
+	}
+	if callerRuns {
+		// 在读锁外执行，避免任务中再次提交时与 worker 退出互相等待
+		w.processTask(t)
+	}
+	return err
+}
+
+// shard 返回 id 所在的路由分片
+func (i *IdPool) shard(key int64) *routeShard {
+	return &i.routes[uint64(key)&(routeShards-1)]
+}
+
+// acquireRoute 增加 id 未完成的任务数并返回其所在的 worker
+// id 有未完成的任务时沿用原 worker，否则按哈希槽选择
+func (i *IdPool) acquireRoute(key int64) *worker {
+	sh := i.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	route, ok := sh.routes[key]
+	if !ok {
+		route.w = i.pickWorker(key)
+	}
+	route.pending++
+	sh.routes[key] = route
+	return route.w
+}
+
+// pickWorker 按哈希槽选择 worker 并增加其 id 数量
+// Resize 先替换哈希槽再标记移出的 worker，选中已移出的 worker 时按新的哈希槽重新选择
+func (i *IdPool) pickWorker(key int64) *worker {
+	for {
+		w := i.slots.Load().get(key)
+		w.keys.Add(1)
+		if !w.retired.Load() {
+			return w
+		}
+		i.releaseKey(w)
+	}
+}
+
+// releaseKey 减少 worker 的 id 数量，已移出的 worker 不再有 id 时停止
+func (i *IdPool) releaseKey(w *worker) {
+	if w.keys.Add(-1) != 0 || !w.retired.Load() {
+		return
+	}
+	w.stop()
+	i.mu.Lock()
+	i.retiring = slices.DeleteFunc(i.retiring, func(v *worker) bool { return v == w })
+	i.mu.Unlock()
+}
+
+// send 将任务发送到 worker 的队列，队列已满时按拒绝策略处理，返回是否需要在调用方执行和被丢弃的已提交任务
+func (i *IdPool) send(ctx context.Context, w *worker, t *customTask) (callerRuns bool, evicted []*customTask, err error) {
+	w.sendMu.RLock()
+	defer w.sendMu.RUnlock()
+	if w.exited {
+		i.rejectTask(t)
+		return false, nil, ErrPoolClosed
+	}
 	select {
-	case w.queue <- &customTask{taskID: taskID, task: task}:
+	case w.queue <- t:
+		return false, nil, nil
 	default:
+	}
+
+	switch i.rejectPolicy {
+	case RejectBlock:
+		if i.blockTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, i.blockTimeout)
+			defer cancel()
+		}
+		select {
+		case w.queue <- t:
+			return false, nil, nil
+		case <-w.done:
+			i.rejectTask(t)
+			return false, nil, ErrPoolClosed
+		case <-ctx.Done():
+			i.rejectTask(t)
+			return false, nil, fmt.Errorf("%w: %w", ErrQueueFull, ctx.Err())
+		}
+	case RejectCallerRuns:
+		return true, nil, nil
+	case RejectDiscardOldest:
+		for {
+			select {
+			case w.queue <- t:
+				return false, evicted, nil
+			case oldest := <-w.queue:
+				i.rejectTask(oldest)
+				evicted = append(evicted, oldest)
+				logger.Log.Warn(fmt.Sprintf("%s queue is full, discard oldest task", i.poolName))
+			}
+		}
+	case RejectReturnError:
+		i.rejectTask(t)
+		return false, nil, ErrQueueFull
+	default:
+		i.rejectTask(t)
 		logger.Log.Warn(fmt.Sprintf("%s queue is full", i.poolName))
+		return false, nil, ErrQueueFull
 	}
 }
 
 // GetTaskCount 获取任务计数
 func (i *IdPool) GetTaskCount(id any) int32 {
 	idInt64 := convertor.ToInt64(id)
-	v := i.idTaskCounts.GetOrDefault(idInt64, &atomic.Int32{})
-	return v.Load()
+	sh := i.shard(idInt64)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if route, ok := sh.routes[idInt64]; ok {
+		return route.pending
+	}
+	return 0
 }
 
 // MaxQueue 最大worker队列长度
 func (i *IdPool) MaxQueue() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
 	num := 0
 	for _, v := range i.workers {
 		if len(v.queue) > num {
 			num = len(v.queue)
 		}
 	}
+	for _, v := range i.retiring {
+		num = max(num, len(v.queue))
+	}
 	return num
 }
 
-// Shutdown 关闭服务
+// Stats 获取统计信息，QueueDepths 依次为分配了哈希槽的 worker 和缩容后尚未退出的 worker
+func (i *IdPool) Stats() PoolStats {
+	stats := i.metrics.stats()
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	stats.QueueDepths = make([]int, 0, len(i.workers)+len(i.retiring))
+	for _, w := range i.workers {
+		stats.QueueDepths = append(stats.QueueDepths, len(w.queue))
+		stats.Queued += len(w.queue)
+	}
+	for _, w := range i.retiring {
+		stats.QueueDepths = append(stats.QueueDepths, len(w.queue))
+		stats.Queued += len(w.queue)
+	}
+	return stats
+}
+
+// SetSlowTaskThreshold 设置慢任务阈值，<=0 表示不检查
+func (i *IdPool) SetSlowTaskThreshold(threshold time.Duration) {
+	i.metrics.slow.Store(int64(threshold))
+}
+
+// Shutdown 关闭服务，超时时取消任务的 ctx 并记录仍未完成的任务
 func (i *IdPool) Shutdown(timeout time.Duration) (isTimeout bool) {
+	report := i.ShutdownWithReport(timeout)
+	if report.TimedOut {
+		logger.Log.Warn(fmt.Sprintf("%s shutdown timeout, %s", i.poolName, report))
+	}
+	return report.TimedOut
+}
+
+// ShutdownWithReport 关闭服务，等待已提交的任务执行完成
+// 超时时取消传给任务的根 ctx，排队中的 ctx 任务将被跳过，并返回仍在排队和执行的任务
+func (i *IdPool) ShutdownWithReport(timeout time.Duration) ShutdownReport {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
+	defer i.cancel()
 
 	// 停止接收新任务
+	i.mu.Lock()
 	i.running.Store(false)
+	if i.scaleStop != nil {
+		close(i.scaleStop)
+		i.scaleStop = nil
+	}
 
 	// 通知所有 worker 停止
 	for _, w := range i.workers {
-		close(w.done) // 发送关闭信号
+		w.stop() // 发送关闭信号
+	}
+	for _, w := range i.retiring {
+		w.stop()
 	}
+	i.mu.Unlock()
 
 	// 创建一个 channel 用于等待 WaitGroup
 	waitCh := make(chan struct{})
@@ -136,9 +400,9 @@ func (i *IdPool) Shutdown(timeout time.Duration) (isTimeout bool) {
 	// 等待所有 worker 退出或上下文取消
 	select {
 	case <-waitCh:
-		return false
+		return ShutdownReport{}
 	case <-ctx.Done():
-		return true
+		return shutdownReport(i.taskIdMap)
 	}
 }
 
@@ -150,6 +414,22 @@ func newWorker(i *IdPool, queueSize int) *worker {
 	}
 }
 
+// startWorker 创建并启动 worker，扩容时调用需持有锁
+func (i *IdPool) startWorker() *worker {
+	w := newWorker(i, i.queueSize)
+	i.wg.Add(1) // 为每个worker增加计数
+	go func() {
+		defer i.wg.Done() // worker退出时减少计数
+		w.run()
+	}()
+	return w
+}
+
+// stop 发送关闭信号，worker 执行完队列中的任务后退出
+func (w *worker) stop() {
+	w.once.Do(func() { close(w.done) })
+}
+
 // worker 运行循环
 func (w *worker) run() {
 	for {
@@ -159,6 +439,11 @@ func (w *worker) run() {
 		case <-w.done:
 			// 处理剩余任务
 			w.drainQueue()
+			// 等待正在发送的任务完成后标记退出，再处理这期间发送的任务
+			w.sendMu.Lock()
+			w.exited = true
+			w.sendMu.Unlock()
+			w.drainQueue()
 			return
 		}
 	}
@@ -180,21 +465,35 @@ func (w *worker) drainQueue() {
 }
 
 func (w *worker) processTask(task *customTask) {
-	defer func() {
-		err := recover()
-		if err != nil {
-			logger.Log.Error(fmt.Sprintf("err=%v", err))
-		}
+	defer w.idPool.finishTask(task)
+	// 执行任务
+	w.idPool.metrics.run(w.idPool.poolName, w.idPool.ctx, task)
+}
 
-		// 清理任务映射并减少计数
-		id := w.idPool.taskIdMap.Get(task.taskID)
-		w.idPool.taskIdMap.Remove(task.taskID)
-		v := w.idPool.idTaskCounts.Get(id)
-		if v != nil {
-			v.Add(-1)
-		}
-	}()
+// rejectTask 清理未执行的任务并计入拒绝数量
+func (i *IdPool) rejectTask(task *customTask) {
+	i.finishTask(task)
+	i.metrics.rejected.Add(1)
+}
 
-	// 执行任务
-	task.task()
+// finishTask 清理任务映射并减少计数，任务执行完成或被丢弃时调用
+func (i *IdPool) finishTask(task *customTask) {
+	i.taskIdMap.Remove(task.taskID)
+	sh := i.shard(task.key)
+	sh.mu.Lock()
+	route, ok := sh.routes[task.key]
+	if !ok {
+		sh.mu.Unlock()
+		return
+	}
+	route.pending--
+	if route.pending > 0 {
+		sh.routes[task.key] = route
+		sh.mu.Unlock()
+		return
+	}
+	// id 的任务全部完成，之后提交的任务重新按哈希槽选择 worker
+	delete(sh.routes, task.key)
+	sh.mu.Unlock()
+	i.releaseKey(route.w)
 }
@@ -0,0 +1,126 @@
+package poolUtil
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Tomatosky/jo-util/logger"
+)
+
+// AutoScaleOpt IdPool 自动扩缩容的配置
+type AutoScaleOpt struct {
+	MinSize   int64         // 最小 worker 数量，默认为 PoolSize
+	MaxSize   int64         // 最大 worker 数量，必填且不小于 MinSize
+	Interval  time.Duration // 检查队列长度的间隔，默认1s
+	UpDepth   int           // 平均每个 worker 的队列长度达到该值时扩容，默认为 QueueSize 的一半且至少为1
+	DownDepth int           // 平均队列长度不超过该值且有空闲 worker 时缩容，默认0，需小于 UpDepth
+	Step      int64         // 每次增减的 worker 数量，默认1
+}
+
+// Size 当前分配了哈希槽的 worker 数量
+func (i *IdPool) Size() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return len(i.workers)
+}
+
+// Resize 调整 worker 数量，按一致性哈希槽只有少部分 id 会迁移到其他 worker，超过哈希槽数量（至少4096）的 worker 不会分配到任务
+// 迁移的 id 在原 worker 上还有未完成的任务时，新任务仍提交到原 worker，执行完后才切换，保证相同 id 的任务按顺序执行
+// 缩容移出的 worker 在分配给它的 id 全部执行完后退出，线程池已关闭时返回 ErrPoolClosed
+func (i *IdPool) Resize(size int64) error {
+	if size <= 0 {
+		logger.Log.Error(fmt.Sprintf("%v", "pool size must be greater than 0"))
+		panic("pool size must be greater than 0")
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !i.running.Load() {
+		return ErrPoolClosed
+	}
+	for int64(len(i.workers)) < size {
+		i.workers = append(i.workers, i.startWorker())
+	}
+	var removed []*worker
+	if int64(len(i.workers)) > size {
+		removed = slices.Clone(i.workers[size:])
+		i.workers = i.workers[:size]
+	}
+	// 先发布新的哈希槽再标记移出的 worker，提交方看到 retired 时一定能选到新的 worker
+	i.slots.Store(i.slots.Load().rebalance(i.workers))
+	for _, w := range removed {
+		w.retired.Store(true)
+		if w.keys.Load() == 0 {
+			w.stop()
+		} else {
+			// 最后一个 id 完成时由 releaseKey 停止并移出 retiring
+			i.retiring = append(i.retiring, w)
+		}
+	}
+	return nil
+}
+
+// startAutoScale 启动自动扩缩容协程
+func (i *IdPool) startAutoScale(opt *AutoScaleOpt) {
+	cfg := *opt
+	if cfg.MinSize <= 0 {
+		cfg.MinSize = int64(len(i.workers))
+	}
+	if cfg.MaxSize < cfg.MinSize {
+		logger.Log.Error(fmt.Sprintf("%v", "max size must not be less than min size"))
+		panic("max size must not be less than min size")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	if cfg.UpDepth <= 0 {
+		cfg.UpDepth = max(i.queueSize/2, 1)
+	}
+	if cfg.DownDepth >= cfg.UpDepth {
+		logger.Log.Error(fmt.Sprintf("%v", "down depth must be less than up depth"))
+		panic("down depth must be less than up depth")
+	}
+	if cfg.Step <= 0 {
+		cfg.Step = 1
+	}
+
+	i.scaleStop = make(chan struct{})
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				i.autoScale(&cfg)
+			}
+		}
+	}(i.scaleStop)
+}
+
+// autoScale 根据平均队列长度和忙碌的 worker 数量调整 worker 数量
+func (i *IdPool) autoScale(cfg *AutoScaleOpt) {
+	i.mu.Lock()
+	size := int64(len(i.workers))
+	queued := 0
+	for _, w := range i.workers {
+		queued += len(w.queue)
+	}
+	i.mu.Unlock()
+
+	target := size
+	busy := int64(i.metrics.busy.Load())
+	switch {
+	case queued >= cfg.UpDepth*int(size):
+		target = min(size+cfg.Step, cfg.MaxSize)
+	case queued <= cfg.DownDepth*int(size) && busy < size:
+		target = max(size-cfg.Step, cfg.MinSize)
+	}
+	if target == size {
+		return
+	}
+	if err := i.Resize(target); err == nil {
+		logger.Log.Info(fmt.Sprintf("%s auto scale from %d to %d workers, queued=%d busy=%d", i.poolName, size, target, queued, busy))
+	}
+}
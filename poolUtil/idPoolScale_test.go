@@ -0,0 +1,266 @@
+package poolUtil
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHashSlots(t *testing.T) {
+	workers := make([]*worker, 6)
+	for i := range workers {
+		workers[i] = &worker{}
+	}
+	slots := newHashSlots(workers[:4])
+	const keys = 10000
+	for k := int64(0); k < keys; k++ {
+		if slots.get(k) != workers[k%4] {
+			t.Fatalf("Key %d: initial routing should match modulo", k)
+		}
+	}
+	if slots.get(-1) == nil {
+		t.Error("Negative key should be routed")
+	}
+
+	// 扩容时只有迁移到新 worker 的 key 改变，约 1/5
+	before := make([]*worker, keys)
+	for k := range before {
+		before[k] = slots.get(int64(k))
+	}
+	slots = slots.rebalance(workers[:5])
+	moved := 0
+	counts := make(map[*worker]int)
+	for k := range before {
+		to := slots.get(int64(k))
+		counts[to]++
+		if to != before[k] {
+			moved++
+			if to != workers[4] {
+				t.Fatalf("Key %d moved between existing workers", k)
+			}
+		}
+	}
+	if moved < keys*15/100 || moved > keys*25/100 {
+		t.Errorf("Expected about %d keys moved, got %d", keys/5, moved)
+	}
+	for _, n := range counts {
+		if n < keys*15/100 || n > keys*25/100 {
+			t.Errorf("Unbalanced worker with %d keys", n)
+		}
+	}
+
+	// 缩容时只有被移除的 worker 的 key 改变
+	for k := range before {
+		before[k] = slots.get(int64(k))
+	}
+	remain := []*worker{workers[0], workers[2], workers[4]}
+	slots = slots.rebalance(remain)
+	for k := range before {
+		to := slots.get(int64(k))
+		if before[k] != workers[1] && before[k] != workers[3] && to != before[k] {
+			t.Fatalf("Key %d on remaining worker should not move", k)
+		}
+	}
+}
+
+func TestIdPoolResize(t *testing.T) {
+	t.Run("OrderAcrossResize", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 2, QueueSize: 64, RejectPolicy: RejectBlock})
+
+		const keys, perKey = 32, 300
+		var mu sync.Mutex
+		results := make(map[int][]int)
+		var wg sync.WaitGroup
+		for k := 0; k < keys; k++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < perKey; i++ {
+					pool.SubmitWithId(k, func() {
+						mu.Lock()
+						results[k] = append(results[k], i)
+						mu.Unlock()
+					})
+				}
+			}()
+		}
+		for _, size := range []int64{8, 3, 6, 1, 4} {
+			time.Sleep(2 * time.Millisecond)
+			if err := pool.Resize(size); err != nil {
+				t.Fatal(err)
+			}
+		}
+		wg.Wait()
+		if pool.Shutdown(5 * time.Second) {
+			t.Fatal("Shutdown timeout")
+		}
+
+		for k := 0; k < keys; k++ {
+			if len(results[k]) != perKey {
+				t.Fatalf("Key %d: expected %d tasks, got %d", k, perKey, len(results[k]))
+			}
+			for i, v := range results[k] {
+				if v != i {
+					t.Fatalf("Key %d: task %d ran at position %d", k, v, i)
+				}
+			}
+		}
+		if pool.Size() != 4 || pool.routeCount() != 0 {
+			t.Errorf("Expected 4 workers and no routes, got %d and %d", pool.Size(), pool.routeCount())
+		}
+	})
+
+	t.Run("DrainThenSwitch", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 1, QueueSize: 10})
+		defer pool.Shutdown(time.Second)
+
+		// 找一个扩容后会迁移的 id
+		old := pool.workers[0]
+		release := make(chan struct{})
+		_ = pool.Resize(4)
+		var key int64
+		for pool.slots.Load().get(key) == old {
+			key++
+		}
+		_ = pool.Resize(1)
+
+		var order []int
+		var mu sync.Mutex
+		record := func(n int) func() {
+			return func() {
+				mu.Lock()
+				order = append(order, n)
+				mu.Unlock()
+			}
+		}
+		pool.SubmitWithId(key, func() { <-release })
+		pool.SubmitWithId(key, record(1))
+		_ = pool.Resize(4)
+		// id 还有未完成的任务，仍提交到原 worker
+		pool.SubmitWithId(key, record(2))
+		if pool.routeOf(key).w != old || pool.GetTaskCount(key) != 3 {
+			t.Fatal("Key should stay on the old worker until drained")
+		}
+		close(release)
+		time.Sleep(20 * time.Millisecond)
+
+		// 执行完后切换到新的 worker
+		if pool.GetTaskCount(key) != 0 || pool.slots.Load().get(key) == old {
+			t.Fatal("Key should switch to the new worker after draining")
+		}
+		pool.SubmitWithId(key, record(3))
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		if len(order) != 3 || order[0] != 1 || order[1] != 2 || order[2] != 3 {
+			t.Errorf("Unexpected order %v", order)
+		}
+		if pool.GetTaskCount(key) != 0 {
+			t.Errorf("Expected no pending tasks, got %d", pool.GetTaskCount(key))
+		}
+	})
+
+	t.Run("ShrinkRetiresWorker", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 4, QueueSize: 10})
+		defer pool.Shutdown(time.Second)
+
+		last := pool.workers[3]
+		var key int64
+		for pool.slots.Load().get(key) != last {
+			key++
+		}
+		release := make(chan struct{})
+		pool.SubmitWithId(key, func() { <-release })
+		var ran atomic.Bool
+		pool.SubmitWithId(key, func() { ran.Store(true) })
+
+		if err := pool.Resize(2); err != nil {
+			t.Fatal(err)
+		}
+		if pool.Size() != 2 || len(pool.Stats().QueueDepths) != 3 {
+			t.Fatal("Removed worker should keep running until its keys drain")
+		}
+		close(release)
+		select {
+		case <-last.done:
+		case <-time.After(time.Second):
+			t.Fatal("Retired worker should stop after draining")
+		}
+		if !ran.Load() {
+			t.Error("Queued task on retired worker should run")
+		}
+		if len(pool.Stats().QueueDepths) != 2 {
+			t.Error("Retired worker should be removed")
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 1, QueueSize: 1})
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected panic for size 0")
+				}
+			}()
+			_ = pool.Resize(0)
+		}()
+		pool.Shutdown(time.Second)
+		if err := pool.Resize(2); !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("Expected ErrPoolClosed, got %v", err)
+		}
+	})
+}
+
+func TestIdPoolAutoScale(t *testing.T) {
+	pool := NewIdPool(&IdPoolOpt{
+		PoolSize:  1,
+		QueueSize: 100,
+		AutoScale: &AutoScaleOpt{MaxSize: 4, Interval: 5 * time.Millisecond, UpDepth: 10},
+	})
+	defer pool.Shutdown(time.Second)
+
+	release := make(chan struct{})
+	pool.SubmitWithId(0, func() { <-release })
+	for i := 0; i < 50; i++ {
+		pool.SubmitWithId(0, func() {})
+	}
+	waitFor := func(size int) bool {
+		deadline := time.Now().Add(time.Second)
+		for pool.Size() != size && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		return pool.Size() == size
+	}
+	if !waitFor(4) {
+		t.Fatalf("Expected scale up to 4, got %d", pool.Size())
+	}
+	close(release)
+	if !waitFor(1) {
+		t.Fatalf("Expected scale down to 1, got %d", pool.Size())
+	}
+	if pool.GetTaskCount(0) != 0 {
+		t.Error("All tasks should be finished")
+	}
+}
+
+// routeCount 所有分片中的路由数量
+func (i *IdPool) routeCount() int {
+	n := 0
+	for k := range i.routes {
+		sh := &i.routes[k]
+		sh.mu.Lock()
+		n += len(sh.routes)
+		sh.mu.Unlock()
+	}
+	return n
+}
+
+// routeOf 返回 id 的路由，没有未完成的任务时为零值
+func (i *IdPool) routeOf(key int64) keyRoute {
+	sh := i.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.routes[key]
+}
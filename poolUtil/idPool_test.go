@@ -1,6 +1,8 @@
 package poolUtil
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -244,3 +246,199 @@ func TestIdPool(t *testing.T) {
 		pool.Shutdown(time.Second)
 	})
 }
+
+// newBusyIdPool 创建单个 worker 的线程池，worker 被阻塞且队列已满，返回释放 worker 的函数
+func newBusyIdPool(t *testing.T, opt *IdPoolOpt) (*IdPool, func()) {
+	t.Helper()
+	opt.PoolSize = 1
+	opt.QueueSize = 1
+	pool := NewIdPool(opt)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	pool.SubmitWithId(0, func() {
+		close(started)
+		<-release
+	})
+	<-started
+	if err := pool.TrySubmitWithId(0, func() {}); err != nil {
+		t.Fatalf("Expected queue to accept one task, got %v", err)
+	}
+	var once sync.Once
+	return pool, func() { once.Do(func() { close(release) }) }
+}
+
+func TestIdPoolRejectPolicy(t *testing.T) {
+	t.Run("Discard", func(t *testing.T) {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{})
+		var ran atomic.Bool
+		if err := pool.TrySubmitWithId(0, func() { ran.Store(true) }); !errors.Is(err, ErrQueueFull) {
+			t.Errorf("Expected ErrQueueFull, got %v", err)
+		}
+		release()
+		pool.Shutdown(time.Second)
+		if ran.Load() {
+			t.Error("Discarded task should not run")
+		}
+		if got := pool.GetTaskCount(0); got != 0 {
+			t.Errorf("Expected task count 0, got %d", got)
+		}
+	})
+
+	t.Run("ReturnError", func(t *testing.T) {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectReturnError})
+		if err := pool.TrySubmitWithId(0, func() {}); !errors.Is(err, ErrQueueFull) {
+			t.Errorf("Expected ErrQueueFull, got %v", err)
+		}
+		if got := pool.GetTaskCount(0); got != 2 {
+			t.Errorf("Rejected task should not be counted, got %d", got)
+		}
+		release()
+		pool.Shutdown(time.Second)
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectBlock})
+		var ran atomic.Bool
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			release()
+		}()
+		start := time.Now()
+		if err := pool.TrySubmitWithId(0, func() { ran.Store(true) }); err != nil {
+			t.Errorf("Block policy should wait for space, got %v", err)
+		}
+		if time.Since(start) < 40*time.Millisecond {
+			t.Error("Expected submit to block until the queue has space")
+		}
+		pool.Shutdown(time.Second)
+		if !ran.Load() {
+			t.Error("Blocked task should run")
+		}
+	})
+
+	t.Run("BlockTimeout", func(t *testing.T) {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectBlock, BlockTimeout: 30 * time.Millisecond})
+		defer pool.Shutdown(time.Second)
+		defer release()
+		err := pool.TrySubmitWithId(0, func() {})
+		if !errors.Is(err, ErrQueueFull) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected ErrQueueFull with deadline exceeded, got %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := pool.TrySubmitWithIdCtx(ctx, 0, func() {}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context canceled, got %v", err)
+		}
+	})
+
+	t.Run("CallerRuns", func(t *testing.T) {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectCallerRuns})
+		defer pool.Shutdown(time.Second)
+		defer release()
+		ran := false
+		if err := pool.TrySubmitWithId(0, func() { ran = true }); err != nil {
+			t.Errorf("CallerRuns policy should not return error, got %v", err)
+		}
+		if !ran {
+			t.Error("Task should run in the caller goroutine")
+		}
+		// 任务 panic 时不影响调用方
+		if err := pool.TrySubmitWithId(0, func() { panic("caller runs panic") }); err != nil {
+			t.Errorf("Unexpected error %v", err)
+		}
+	})
+
+	t.Run("DiscardOldest", func(t *testing.T) {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectDiscardOldest})
+		var ran atomic.Int32
+		for i := 1; i <= 3; i++ {
+			if err := pool.TrySubmitWithId(0, func() { ran.Store(int32(i)) }); err != nil {
+				t.Errorf("DiscardOldest policy should not return error, got %v", err)
+			}
+		}
+		if got := pool.GetTaskCount(0); got != 2 {
+			t.Errorf("Expected running task and newest task, got count %d", got)
+		}
+		release()
+		pool.Shutdown(time.Second)
+		if ran.Load() != 3 {
+			t.Errorf("Expected only the newest task to run, got %d", ran.Load())
+		}
+	})
+
+	t.Run("DiscardOldestNotify", func(t *testing.T) {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectDiscardOldest})
+		var ran, discarded atomic.Int32
+		for i := 1; i <= 3; i++ {
+			err := pool.TrySubmitWithIdOnDiscard(0, func() { ran.Add(1) }, func() { discarded.Add(int32(i)) })
+			if err != nil {
+				t.Errorf("DiscardOldest policy should not return error, got %v", err)
+			}
+		}
+		// 第一次提交挤掉 newBusyIdPool 中没有回调的任务，之后的提交依次挤掉前一个任务
+		if got := discarded.Load(); got != 1+2 {
+			t.Errorf("Expected tasks 1 and 2 to be discarded, got sum %d", got)
+		}
+		release()
+		pool.Shutdown(time.Second)
+		if ran.Load() != 1 {
+			t.Errorf("Expected only the newest task to run, got %d", ran.Load())
+		}
+	})
+
+	t.Run("AfterShutdown", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 1, QueueSize: 1})
+		pool.Shutdown(time.Second)
+		if err := pool.TrySubmitWithId(0, func() {}); !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("Expected ErrPoolClosed, got %v", err)
+		}
+	})
+}
+
+func TestIdPoolSubmitDuringShutdown(t *testing.T) {
+	for round := 0; round < 20; round++ {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 4, QueueSize: 4, RejectPolicy: RejectBlock})
+		var ran, failed atomic.Int32
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					if err := pool.TrySubmitWithId(i, func() { ran.Add(1) }); err != nil {
+						failed.Add(1)
+					}
+				}
+			}()
+		}
+		time.Sleep(time.Duration(round%5) * 100 * time.Microsecond)
+		if pool.Shutdown(time.Second) {
+			t.Fatal("Shutdown timeout")
+		}
+		wg.Wait()
+		// 每个任务要么执行，要么返回错误，不会留在已退出的 worker 中
+		if got := ran.Load() + failed.Load(); got != 400 {
+			t.Fatalf("Round %d: expected 400 tasks accounted, got %d", round, got)
+		}
+		if size := pool.taskIdMap.Size(); size != 0 || pool.routeCount() != 0 {
+			t.Fatalf("Round %d: stranded tasks: taskIdMap=%d routes=%d", round, size, pool.routeCount())
+		}
+	}
+}
+
+// BenchmarkIdPoolSubmitWithId 多个协程向大量 id 提交任务，衡量提交和完成路径上的锁竞争
+// 队列足够容纳所有任务，避免拒绝策略影响结果
+func BenchmarkIdPoolSubmitWithId(b *testing.B) {
+	const poolSize = 16
+	pool := NewIdPool(&IdPoolOpt{PoolSize: poolSize, QueueSize: b.N/poolSize + poolSize})
+	defer pool.Shutdown(time.Minute)
+	var next atomic.Int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			pool.SubmitWithId(next.Add(1)%4096, func() {})
+		}
+	})
+}
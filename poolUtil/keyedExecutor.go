@@ -0,0 +1,213 @@
+package poolUtil
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Tomatosky/jo-util/logger"
+	"github.com/Tomatosky/jo-util/queueUtil"
+)
+
+const defaultMailboxBatch = 64 // 默认每次调度连续执行的任务数
+
+// KeyedExecutorOpt KeyedExecutor 的配置
+type KeyedExecutorOpt struct {
+	Pool     IPool // 共享的线程池，必填
+	MaxBatch int   // 同一个 key 每次调度最多连续执行的任务数，超过后让出线程，默认64
+}
+
+// KeyedExecutor 按 key 串行执行任务，相同 key 的任务按提交顺序执行，不同 key 的任务并行执行
+// 每个有待执行任务的 key 拥有一个邮箱，邮箱被调度到共享线程池中执行，不绑定固定的 worker
+// 与 IdPool 不同，一个 key 的任务执行缓慢不会阻塞其他 key，邮箱清空后立即回收
+type KeyedExecutor[K comparable] struct {
+	pool      IPool
+	maxBatch  int
+	mu        sync.Mutex
+	mailboxes map[K]*mailbox
+	pending   int // 未执行完的任务数量
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+type mailbox struct {
+	tasks     *queueUtil.Queue[func()]
+	size      int
+	scheduled chan struct{} // 首次调度有结果（提交返回、开始执行或被丢弃）时关闭
+	once      sync.Once
+	err       error // 首次调度失败的错误，scheduled 关闭后可读
+}
+
+func newMailbox() *mailbox {
+	return &mailbox{tasks: queueUtil.NewQueue[func()](), scheduled: make(chan struct{})}
+}
+
+// settle 记录首次调度的结果，只有第一次调用生效
+func (mb *mailbox) settle(err error) {
+	mb.once.Do(func() {
+		mb.err = err
+		close(mb.scheduled)
+	})
+}
+
+// NewKeyedExecutor 创建按 key 串行执行的执行器
+func NewKeyedExecutor[K comparable](opt *KeyedExecutorOpt) *KeyedExecutor[K] {
+	if opt == nil || opt.Pool == nil {
+		logger.Log.Error(fmt.Sprintf("%v", "pool cannot be nil"))
+		panic("pool cannot be nil")
+	}
+	maxBatch := opt.MaxBatch
+	if maxBatch <= 0 {
+		maxBatch = defaultMailboxBatch
+	}
+	return &KeyedExecutor[K]{
+		pool:      opt.Pool,
+		maxBatch:  maxBatch,
+		mailboxes: make(map[K]*mailbox),
+	}
+}
+
+// Submit 提交任务，执行器已关闭时返回 ErrPoolClosed
+// 邮箱首次提交到线程池失败或被丢弃时，返回该错误并丢弃该 key 待执行的任务，同时加入该邮箱的提交方都会收到该错误
+// 之后的调度（让出线程后重新提交）失败或被 RejectDiscardOldest 策略丢弃时，邮箱中的任务被丢弃并记录日志
+func (e *KeyedExecutor[K]) Submit(key K, task func()) error {
+	if task == nil {
+		logger.Log.Error(fmt.Sprintf("%v", "task cannot be nil"))
+		panic("task cannot be nil")
+	}
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return ErrPoolClosed
+	}
+	mb, ok := e.mailboxes[key]
+	if !ok {
+		// 邮箱不存在说明该 key 没有待执行的任务，需要调度
+		mb = newMailbox()
+		e.mailboxes[key] = mb
+	}
+	mb.tasks.Enqueue(task)
+	mb.size++
+	e.pending++
+	e.wg.Add(1)
+	e.mu.Unlock()
+
+	if !ok {
+		mb.settle(e.schedule(key, mb))
+	}
+	// 等待首次调度的结果，调度失败时该邮箱中的任务都已被丢弃，加入邮箱的提交方也需返回错误
+	<-mb.scheduled
+	return mb.err
+}
+
+// Len 未执行完的任务数量
+func (e *KeyedExecutor[K]) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.pending
+}
+
+// ActiveKeys 有待执行任务的 key 的数量
+func (e *KeyedExecutor[K]) ActiveKeys() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.mailboxes)
+}
+
+// PendingOf 指定 key 未执行完的任务数量，包括正在执行的任务
+func (e *KeyedExecutor[K]) PendingOf(key K) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if mb, ok := e.mailboxes[key]; ok {
+		return mb.size
+	}
+	return 0
+}
+
+// Shutdown 停止接收新任务并等待已提交的任务执行完成，不会关闭共享的线程池
+func (e *KeyedExecutor[K]) Shutdown(timeout time.Duration) (isTimeout bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	e.mu.Lock()
+	e.closed = true
+	e.mu.Unlock()
+
+	waitCh := make(chan struct{})
+	go func() {
+		e.wg.Wait()
+		close(waitCh)
+	}()
+	select {
+	case <-waitCh:
+		return false
+	case <-ctx.Done():
+		return true
+	}
+}
+
+// schedule 将邮箱提交到线程池，被拒绝或丢弃时回收邮箱并丢弃其中的所有任务，避免邮箱残留而不再调度
+func (e *KeyedExecutor[K]) schedule(key K, mb *mailbox) error {
+	err := trySubmit(e.pool, func() { e.drain(key, mb) }, func() {
+		mb.settle(ErrQueueFull)
+		e.drop(key, mb, ErrQueueFull)
+	})
+	if err != nil {
+		e.drop(key, mb, err)
+	}
+	return err
+}
+
+// drop 回收邮箱并丢弃其中待执行的任务，只在邮箱没有被调度执行时调用
+func (e *KeyedExecutor[K]) drop(key K, mb *mailbox, err error) {
+	e.mu.Lock()
+	dropped := mb.size
+	mb.size = 0
+	mb.tasks = queueUtil.NewQueue[func()]()
+	if e.mailboxes[key] == mb {
+		delete(e.mailboxes, key)
+	}
+	e.pending -= dropped
+	e.mu.Unlock()
+	e.wg.Add(-dropped)
+	logger.Log.Error(fmt.Sprintf("keyed executor schedule key %v error: %v, %d tasks dropped", key, err, dropped))
+}
+
+// drain 依次执行邮箱中的任务，邮箱清空时回收，达到 MaxBatch 时重新调度以让出线程
+func (e *KeyedExecutor[K]) drain(key K, mb *mailbox) {
+	// 开始执行说明调度已成功，RejectCallerRuns 策略下在提交的协程中执行时，任务中再次提交同一个 key 不需要等待
+	mb.settle(nil)
+	for i := 0; i < e.maxBatch; i++ {
+		e.mu.Lock()
+		task, ok := mb.tasks.Dequeue()
+		e.mu.Unlock()
+		if !ok {
+			return
+		}
+		e.run(task)
+
+		e.mu.Lock()
+		mb.size--
+		e.pending--
+		if mb.size == 0 {
+			delete(e.mailboxes, key)
+			e.mu.Unlock()
+			e.wg.Done()
+			return
+		}
+		e.mu.Unlock()
+		e.wg.Done()
+	}
+	// 在新协程中重新提交，避免所有 worker 都在等待空闲 worker 而死锁
+	go func() { _ = e.schedule(key, mb) }()
+}
+
+func (e *KeyedExecutor[K]) run(task func()) {
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Log.Error(fmt.Sprintf("err=%v", err))
+		}
+	}()
+	task()
+}
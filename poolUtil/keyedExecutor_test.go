@@ -0,0 +1,205 @@
+package poolUtil
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeyedExecutor(t *testing.T) {
+	t.Run("OrderPerKey", func(t *testing.T) {
+		pool := NewAntsPool(8)
+		defer pool.Shutdown(time.Second)
+		e := NewKeyedExecutor[int](&KeyedExecutorOpt{Pool: pool, MaxBatch: 4})
+
+		const keys, perKey = 20, 200
+		var mu sync.Mutex
+		results := make(map[int][]int)
+		var wg sync.WaitGroup
+		for k := 0; k < keys; k++ {
+			wg.Add(1)
+			go func(k int) {
+				defer wg.Done()
+				for i := 0; i < perKey; i++ {
+					if err := e.Submit(k, func() {
+						mu.Lock()
+						results[k] = append(results[k], i)
+						mu.Unlock()
+					}); err != nil {
+						t.Error(err)
+					}
+				}
+			}(k)
+		}
+		wg.Wait()
+		if e.Shutdown(5 * time.Second) {
+			t.Fatal("Shutdown timeout")
+		}
+
+		for k := 0; k < keys; k++ {
+			if len(results[k]) != perKey {
+				t.Fatalf("Key %d: expected %d tasks, got %d", k, perKey, len(results[k]))
+			}
+			for i, v := range results[k] {
+				if v != i {
+					t.Fatalf("Key %d: task %d ran at position %d", k, v, i)
+				}
+			}
+		}
+		if e.ActiveKeys() != 0 || e.Len() != 0 {
+			t.Errorf("Expected idle mailboxes to be reclaimed, got %d keys, %d tasks", e.ActiveKeys(), e.Len())
+		}
+	})
+
+	t.Run("SlowKeyDoesNotBlockOthers", func(t *testing.T) {
+		// 只有两个线程时，慢 key 也不应阻塞其他 key
+		pool := NewAntsPool(2)
+		defer pool.Shutdown(time.Second)
+		e := NewKeyedExecutor[string](&KeyedExecutorOpt{Pool: pool})
+
+		release := make(chan struct{})
+		_ = e.Submit("slow", func() { <-release })
+		_ = e.Submit("slow", func() {})
+
+		var fast atomic.Int32
+		for i := 0; i < 100; i++ {
+			_ = e.Submit("fast", func() { fast.Add(1) })
+			_ = e.Submit("other", func() { fast.Add(1) })
+		}
+		deadline := time.Now().Add(time.Second)
+		for fast.Load() < 200 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if fast.Load() != 200 {
+			t.Errorf("Expected other keys to finish, got %d", fast.Load())
+		}
+		if e.PendingOf("slow") != 2 || e.ActiveKeys() != 1 {
+			t.Errorf("Expected slow key pending 2, got %d with %d active keys", e.PendingOf("slow"), e.ActiveKeys())
+		}
+		close(release)
+		if e.Shutdown(time.Second) {
+			t.Error("Shutdown timeout")
+		}
+		if e.PendingOf("slow") != 0 {
+			t.Error("Slow key should be reclaimed")
+		}
+	})
+
+	t.Run("PanicAndShutdown", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 2, QueueSize: 16})
+		defer pool.Shutdown(time.Second)
+		e := NewKeyedExecutor[int](&KeyedExecutorOpt{Pool: pool})
+
+		var ran atomic.Bool
+		_ = e.Submit(1, func() { panic("keyed panic") })
+		_ = e.Submit(1, func() { ran.Store(true) })
+		if e.Shutdown(time.Second) {
+			t.Fatal("Shutdown timeout")
+		}
+		if !ran.Load() {
+			t.Error("Task after panic should still run")
+		}
+		if err := e.Submit(1, func() {}); !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("Expected ErrPoolClosed, got %v", err)
+		}
+	})
+
+	t.Run("PoolClosed", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 1, QueueSize: 1})
+		pool.Shutdown(time.Second)
+		e := NewKeyedExecutor[int](&KeyedExecutorOpt{Pool: pool})
+		if err := e.Submit(1, func() {}); !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("Expected ErrPoolClosed, got %v", err)
+		}
+		if e.Len() != 0 || e.ActiveKeys() != 0 || e.Shutdown(time.Second) {
+			t.Error("Dropped tasks should not be pending")
+		}
+	})
+	t.Run("PoolDiscards", func(t *testing.T) {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectDiscard})
+		defer pool.Shutdown(time.Second)
+		e := NewKeyedExecutor[int](&KeyedExecutorOpt{Pool: pool})
+
+		// 线程池丢弃邮箱时返回错误，邮箱被回收，不会残留未执行的任务
+		for i := 0; i < 3; i++ {
+			if err := e.Submit(1, func() {}); !errors.Is(err, ErrQueueFull) {
+				t.Fatalf("Expected ErrQueueFull, got %v", err)
+			}
+		}
+		if e.Len() != 0 || e.ActiveKeys() != 0 {
+			t.Fatalf("Discarded mailbox should be reclaimed, got %d tasks, %d keys", e.Len(), e.ActiveKeys())
+		}
+
+		release()
+		for pool.GetTaskCount(0) != 0 {
+			time.Sleep(time.Millisecond)
+		}
+		var ran atomic.Bool
+		if err := e.Submit(1, func() { ran.Store(true) }); err != nil {
+			t.Fatal(err)
+		}
+		if e.Shutdown(time.Second) {
+			t.Fatal("Shutdown timeout")
+		}
+		if !ran.Load() {
+			t.Error("Task should run after pool frees up")
+		}
+	})
+	t.Run("PoolEvictsMailbox", func(t *testing.T) {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectDiscardOldest})
+		defer pool.Shutdown(time.Second)
+		e := NewKeyedExecutor[int](&KeyedExecutorOpt{Pool: pool})
+
+		// key 2 的邮箱挤掉队列中 key 1 的邮箱，key 1 的邮箱被回收
+		var ran1, ran2 atomic.Bool
+		if err := e.Submit(1, func() { ran1.Store(true) }); err != nil {
+			t.Fatal(err)
+		}
+		if err := e.Submit(2, func() { ran2.Store(true) }); err != nil {
+			t.Fatal(err)
+		}
+		if e.Len() != 1 || e.PendingOf(1) != 0 {
+			t.Fatalf("Evicted mailbox should be reclaimed, got %d tasks, %d for key 1", e.Len(), e.PendingOf(1))
+		}
+
+		release()
+		for e.Len() != 0 {
+			time.Sleep(time.Millisecond)
+		}
+		var ranAgain atomic.Bool
+		if err := e.Submit(1, func() { ranAgain.Store(true) }); err != nil {
+			t.Fatal(err)
+		}
+		if e.Shutdown(time.Second) {
+			t.Fatal("Shutdown timeout")
+		}
+		if ran1.Load() || !ran2.Load() || !ranAgain.Load() {
+			t.Errorf("Unexpected runs: evicted=%v kept=%v resubmitted=%v", ran1.Load(), ran2.Load(), ranAgain.Load())
+		}
+	})
+
+	t.Run("ScheduleFailureReachesJoiners", func(t *testing.T) {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectBlock, BlockTimeout: 200 * time.Millisecond})
+		defer pool.Shutdown(time.Second)
+		defer release()
+		e := NewKeyedExecutor[int](&KeyedExecutorOpt{Pool: pool})
+
+		errs := make(chan error, 2)
+		go func() { errs <- e.Submit(1, func() {}) }()
+		for e.ActiveKeys() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		// 邮箱正在等待调度时加入的任务，调度失败后同样返回错误
+		go func() { errs <- e.Submit(1, func() {}) }()
+		for i := 0; i < 2; i++ {
+			if err := <-errs; !errors.Is(err, ErrQueueFull) {
+				t.Errorf("Expected ErrQueueFull, got %v", err)
+			}
+		}
+		if e.Len() != 0 || e.ActiveKeys() != 0 {
+			t.Errorf("Failed mailbox should be reclaimed, got %d tasks, %d keys", e.Len(), e.ActiveKeys())
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package poolUtil
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Tomatosky/jo-util/logger"
+	"github.com/Tomatosky/jo-util/mapUtil"
+)
+
+// TaskInfo 未完成任务的信息
+type TaskInfo struct {
+	TaskId   string    `json:"taskId" bson:"taskId"`     // 任务的唯一ID
+	Id       any       `json:"id" bson:"id"`             // 提交任务时指定的 id，未指定时为nil
+	Enqueued time.Time `json:"enqueued" bson:"enqueued"` // 提交时间
+	Started  time.Time `json:"started" bson:"started"`   // 开始执行的时间，排队中的任务为零值
+}
+
+// ShutdownReport 关闭线程池的结果
+type ShutdownReport struct {
+	TimedOut bool       `json:"timedOut" bson:"timedOut"` // 是否超时
+	Queued   []TaskInfo `json:"queued" bson:"queued"`     // 超时时仍在排队的任务，按提交时间排序
+	Running  []TaskInfo `json:"running" bson:"running"`   // 超时时仍在执行的任务，按提交时间排序
+}
+
+func (r ShutdownReport) String() string {
+	ids := func(tasks []TaskInfo) string {
+		s := make([]string, len(tasks))
+		for i, t := range tasks {
+			s[i] = fmt.Sprintf("%v", t.Id)
+		}
+		return strings.Join(s, ",")
+	}
+	return fmt.Sprintf("queued=%d [%s] running=%d [%s]", len(r.Queued), ids(r.Queued), len(r.Running), ids(r.Running))
+}
+
+type customTask struct {
+	taskID    string
+	id        any   // 提交时指定的 id，用于日志和关闭报告
+	key       int64 // IdPool 中选择 worker 的 id
+	task      func(ctx context.Context)
+	ctx       context.Context // 通过 SubmitCtx 提交时的 ctx，普通任务为nil
+	enqueued  time.Time       // 提交时间，用于统计等待耗时
+	started   atomic.Int64    // 开始执行的时间，0 表示尚未开始
+	onDiscard func()          // 提交成功后被丢弃时调用，为nil时不通知
+}
+
+func newCustomTask(taskID string, id any, task func()) *customTask {
+	return &customTask{
+		taskID:   taskID,
+		id:       id,
+		task:     func(context.Context) { task() },
+		enqueued: time.Now(),
+	}
+}
+
+func newCtxTask(ctx context.Context, taskID string, id any, task func(ctx context.Context)) *customTask {
+	return &customTask{
+		taskID:   taskID,
+		id:       id,
+		task:     task,
+		ctx:      ctx,
+		enqueued: time.Now(),
+	}
+}
+
+// label 日志中标识任务的内容，未指定 id 时使用任务的唯一ID
+func (t *customTask) label() string {
+	if t.id == nil {
+		return "taskId=" + t.taskID
+	}
+	return fmt.Sprintf("id=%v", t.id)
+}
+
+// run 执行任务，ctx 任务会收到与线程池根 ctx 合并后的 ctx，开始前 ctx 已结束时跳过
+func (m *poolMetrics) run(poolName string, root context.Context, t *customTask) {
+	ctx := root
+	if t.ctx != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = mergeContext(t.ctx, root)
+		defer cancel()
+		if err := ctx.Err(); err != nil {
+			m.skipped.Add(1)
+			logger.Log.Warn(fmt.Sprintf("%s skip task %s: %v", poolName, t.label(), context.Cause(ctx)))
+			return
+		}
+	}
+	t.started.Store(time.Now().UnixNano())
+	m.execute(poolName, t.label, t.enqueued, func() { t.task(ctx) })
+}
+
+// mergeContext 返回在 ctx 或 root 结束时结束的 ctx，保留 ctx 的值和截止时间
+func mergeContext(ctx, root context.Context) (context.Context, context.CancelFunc) {
+	merged, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(root, func() {
+		cancel(context.Cause(root))
+	})
+	// root 已结束时 AfterFunc 异步执行，需立即取消，保证任务开始前能检查到
+	if root.Err() != nil {
+		cancel(context.Cause(root))
+	}
+	return merged, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+// shutdownReport 收集未完成的任务
+func shutdownReport(tasks *mapUtil.ConcurrentHashMap[string, *customTask]) ShutdownReport {
+	report := ShutdownReport{TimedOut: true}
+	tasks.Range(func(_ string, t *customTask) bool {
+		info := TaskInfo{TaskId: t.taskID, Id: t.id, Enqueued: t.enqueued}
+		if started := t.started.Load(); started != 0 {
+			info.Started = time.Unix(0, started)
+			report.Running = append(report.Running, info)
+		} else {
+			report.Queued = append(report.Queued, info)
+		}
+		return true
+	})
+	byEnqueued := func(a, b TaskInfo) int { return a.Enqueued.Compare(b.Enqueued) }
+	slices.SortFunc(report.Queued, byEnqueued)
+	slices.SortFunc(report.Running, byEnqueued)
+	return report
+}
@@ -0,0 +1,141 @@
+package poolUtil
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type ctxKey struct{}
+
+func TestSubmitCtx(t *testing.T) {
+	t.Run("IdPoolSkipExpired", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 1, QueueSize: 10})
+		defer pool.Shutdown(time.Second)
+
+		expired, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := pool.SubmitCtx(expired, func(ctx context.Context) {}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+
+		release := make(chan struct{})
+		pool.SubmitWithId(1, func() { <-release })
+		// 排队期间超过截止时间的任务不会执行
+		deadline, cancel2 := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel2()
+		var ran atomic.Bool
+		if err := pool.SubmitWithIdCtx(deadline, 1, func(ctx context.Context) { ran.Store(true) }); err != nil {
+			t.Fatal(err)
+		}
+		// 未过期的任务收到的 ctx 保留原 ctx 的值
+		var value atomic.Value
+		valueCtx := context.WithValue(context.Background(), ctxKey{}, "v")
+		_ = pool.SubmitWithIdCtx(valueCtx, 1, func(ctx context.Context) { value.Store(ctx.Value(ctxKey{})) })
+
+		time.Sleep(30 * time.Millisecond)
+		close(release)
+		time.Sleep(20 * time.Millisecond)
+		if ran.Load() {
+			t.Error("Expired task should be skipped")
+		}
+		if value.Load() != "v" {
+			t.Errorf("Expected ctx value v, got %v", value.Load())
+		}
+		if stats := pool.Stats(); stats.Skipped != 2 || stats.Completed != 2 {
+			t.Errorf("Expected 2 skipped and 2 completed, got %d and %d", stats.Skipped, stats.Completed)
+		}
+		if pool.GetTaskCount(1) != 0 {
+			t.Error("Skipped task should be finished")
+		}
+	})
+
+	t.Run("IdPoolShutdownReport", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 1, QueueSize: 10})
+		started := make(chan struct{})
+		cancelled := make(chan struct{})
+		_ = pool.SubmitWithIdCtx(context.Background(), 7, func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+			close(cancelled)
+		})
+		<-started
+		var ran atomic.Bool
+		_ = pool.SubmitWithIdCtx(context.Background(), 7, func(ctx context.Context) { ran.Store(true) })
+
+		report := pool.ShutdownWithReport(20 * time.Millisecond)
+		if !report.TimedOut {
+			t.Fatal("Expected shutdown timeout")
+		}
+		if len(report.Running) != 1 || report.Running[0].Id != 7 || report.Running[0].Started.IsZero() {
+			t.Errorf("Unexpected running tasks: %+v", report.Running)
+		}
+		if len(report.Queued) != 1 || report.Queued[0].Id != 7 || !report.Queued[0].Started.IsZero() {
+			t.Errorf("Unexpected queued tasks: %+v", report.Queued)
+		}
+
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Fatal("Running task should see root ctx cancelled")
+		}
+		time.Sleep(20 * time.Millisecond)
+		if ran.Load() {
+			t.Error("Queued ctx task should be skipped after shutdown timeout")
+		}
+	})
+
+	t.Run("AntsPool", func(t *testing.T) {
+		pool := NewAntsPool(2)
+
+		expired, cancel := context.WithTimeout(context.Background(), -time.Second)
+		defer cancel()
+		if err := pool.SubmitCtx(expired, func(ctx context.Context) {}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+
+		started := make(chan struct{})
+		cancelled := make(chan struct{})
+		_ = pool.SubmitWithIdCtx(context.Background(), "job", func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+			close(cancelled)
+		})
+		<-started
+		pool.Submit(func() {})
+
+		report := pool.ShutdownWithReport(20 * time.Millisecond)
+		if !report.TimedOut || len(report.Running) != 1 || report.Running[0].Id != "job" || len(report.Queued) != 0 {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Fatal("Running task should see root ctx cancelled")
+		}
+		if stats := pool.Stats(); stats.Skipped != 1 {
+			t.Errorf("Expected 1 skipped task, got %d", stats.Skipped)
+		}
+	})
+
+	t.Run("ShutdownWithoutTimeout", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{PoolSize: 2, QueueSize: 10})
+		var count atomic.Int32
+		for i := 0; i < 10; i++ {
+			_ = pool.SubmitCtx(context.Background(), func(ctx context.Context) {
+				if ctx.Err() == nil {
+					count.Add(1)
+				}
+			})
+		}
+		report := pool.ShutdownWithReport(time.Second)
+		if report.TimedOut || len(report.Running) != 0 || len(report.Queued) != 0 {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+		if count.Load() != 10 {
+			t.Errorf("Expected 10 tasks with live ctx, got %d", count.Load())
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package poolUtil
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/Tomatosky/jo-util/logger"
+)
+
+// histogramBounds 耗时直方图各个桶的上限，最后一个桶统计超过 10s 的任务
+var histogramBounds = [...]time.Duration{
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// PoolStats 线程池的统计信息
+type PoolStats struct {
+	Submitted   int64             `json:"submitted" bson:"submitted"`     // 累计提交的任务数量，包括被拒绝的任务
+	Completed   int64             `json:"completed" bson:"completed"`     // 累计执行完成的任务数量，包括 panic 的任务
+	Rejected    int64             `json:"rejected" bson:"rejected"`       // 累计被拒绝或丢弃而未执行的任务数量
+	Panicked    int64             `json:"panicked" bson:"panicked"`       // 累计发生 panic 的任务数量
+	Skipped     int64             `json:"skipped" bson:"skipped"`         // 累计因 ctx 在开始执行前已结束而跳过的任务数量
+	Busy        int               `json:"busy" bson:"busy"`               // 正在执行任务的 worker 数量
+	Queued      int               `json:"queued" bson:"queued"`           // 等待执行的任务数量
+	QueueDepths []int             `json:"queueDepths" bson:"queueDepths"` // 每个 worker 的队列长度，AntsPool 没有 worker 队列时为nil
+	WaitTime    HistogramSnapshot `json:"waitTime" bson:"waitTime"`       // 任务从提交到开始执行的耗时
+	RunTime     HistogramSnapshot `json:"runTime" bson:"runTime"`         // 任务执行的耗时
+}
+
+// HistogramSnapshot 耗时直方图
+type HistogramSnapshot struct {
+	Bounds []time.Duration `json:"bounds" bson:"bounds"` // 各个桶的上限
+	Counts []int64         `json:"counts" bson:"counts"` // 各个桶的数量，比 Bounds 多一个，最后一个为超过最大上限的数量
+	Count  int64           `json:"count" bson:"count"`   // 总数量
+	Sum    time.Duration   `json:"sum" bson:"sum"`       // 总耗时
+	Max    time.Duration   `json:"max" bson:"max"`       // 最大耗时
+}
+
+// Mean 平均耗时
+func (h HistogramSnapshot) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile 返回分位数 q（0-1）所在桶的上限，落在最后一个桶时返回 Max
+func (h HistogramSnapshot) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	rank := int64(q * float64(h.Count))
+	rank = min(max(rank, 1), h.Count)
+	var seen int64
+	for i, c := range h.Counts {
+		seen += c
+		if seen >= rank && i < len(h.Bounds) {
+			return min(h.Bounds[i], h.Max)
+		}
+	}
+	return h.Max
+}
+
+// histogram 并发安全的耗时直方图
+type histogram struct {
+	counts [len(histogramBounds) + 1]atomic.Int64
+	count  atomic.Int64
+	sum    atomic.Int64
+	max    atomic.Int64
+}
+
+func (h *histogram) observe(d time.Duration) {
+	i := 0
+	for i < len(histogramBounds) && d > histogramBounds[i] {
+		i++
+	}
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+	for {
+		old := h.max.Load()
+		if int64(d) <= old || h.max.CompareAndSwap(old, int64(d)) {
+			return
+		}
+	}
+}
+
+func (h *histogram) snapshot() HistogramSnapshot {
+	s := HistogramSnapshot{
+		Bounds: slices.Clone(histogramBounds[:]),
+		Counts: make([]int64, len(h.counts)),
+		Count:  h.count.Load(),
+		Sum:    time.Duration(h.sum.Load()),
+		Max:    time.Duration(h.max.Load()),
+	}
+	for i := range h.counts {
+		s.Counts[i] = h.counts[i].Load()
+	}
+	return s
+}
+
+// poolMetrics 线程池共用的统计
+type poolMetrics struct {
+	submitted atomic.Int64
+	completed atomic.Int64
+	rejected  atomic.Int64
+	panicked  atomic.Int64
+	skipped   atomic.Int64
+	busy      atomic.Int32
+	waitTime  histogram
+	runTime   histogram
+	slow      atomic.Int64 // 慢任务阈值，<=0 表示不检查
+}
+
+// execute 执行任务并统计耗时，任务 panic 时记录日志，执行时间超过阈值时记录慢任务日志
+// label 返回日志中标识任务的内容，只在记录慢任务日志时调用
+func (m *poolMetrics) execute(poolName string, label func() string, enqueued time.Time, task func()) {
+	start := time.Now()
+	m.waitTime.observe(start.Sub(enqueued))
+	m.busy.Add(1)
+	defer func() {
+		cost := time.Since(start)
+		m.busy.Add(-1)
+		m.runTime.observe(cost)
+		m.completed.Add(1)
+		if err := recover(); err != nil {
+			m.panicked.Add(1)
+			logger.Log.Error(fmt.Sprintf("err=%v", err))
+		}
+		if slow := time.Duration(m.slow.Load()); slow > 0 && cost >= slow {
+			logger.Log.Warn(fmt.Sprintf("%s slow task %s cost=%v", poolName, label(), cost))
+		}
+	}()
+	task()
+}
+
+// stats 返回累计统计，队列信息由线程池填充
+func (m *poolMetrics) stats() PoolStats {
+	return PoolStats{
+		Submitted: m.submitted.Load(),
+		Completed: m.completed.Load(),
+		Rejected:  m.rejected.Load(),
+		Panicked:  m.panicked.Load(),
+		Skipped:   m.skipped.Load(),
+		Busy:      int(m.busy.Load()),
+		WaitTime:  m.waitTime.snapshot(),
+		RunTime:   m.runTime.snapshot(),
+	}
+}
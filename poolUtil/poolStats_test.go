@@ -0,0 +1,122 @@
+package poolUtil
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPoolStats(t *testing.T) {
+	t.Run("IdPool", func(t *testing.T) {
+		pool := NewIdPool(&IdPoolOpt{
+			PoolSize:          2,
+			QueueSize:         1,
+			PoolName:          "StatsPool",
+			RejectPolicy:      RejectReturnError,
+			SlowTaskThreshold: 10 * time.Millisecond,
+		})
+		release := make(chan struct{})
+		started := make(chan struct{})
+		_ = pool.TrySubmitWithId(0, func() {
+			close(started)
+			<-release
+		})
+		<-started
+		// id 0 所在的 worker 正忙，队列中放一个任务，再提交一个会被拒绝
+		_ = pool.TrySubmitWithId(0, func() { panic("stats panic") })
+		if err := pool.TrySubmitWithId(0, func() {}); !errors.Is(err, ErrQueueFull) {
+			t.Fatalf("Expected ErrQueueFull, got %v", err)
+		}
+
+		stats := pool.Stats()
+		if stats.Busy != 1 || stats.Queued != 1 || len(stats.QueueDepths) != 2 || slices.Max(stats.QueueDepths) != 1 {
+			t.Errorf("Unexpected queue stats: busy=%d queued=%d depths=%v", stats.Busy, stats.Queued, stats.QueueDepths)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		pool.Shutdown(time.Second)
+
+		stats = pool.Stats()
+		if stats.Submitted != 3 || stats.Completed != 2 || stats.Rejected != 1 || stats.Panicked != 1 || stats.Busy != 0 {
+			t.Errorf("Unexpected counters: %+v", stats)
+		}
+		if stats.RunTime.Count != 2 || stats.RunTime.Max < 20*time.Millisecond {
+			t.Errorf("Unexpected run time histogram: %+v", stats.RunTime)
+		}
+		if stats.WaitTime.Count != 2 || stats.WaitTime.Max < 20*time.Millisecond {
+			t.Errorf("Unexpected wait time histogram: %+v", stats.WaitTime)
+		}
+
+		if err := pool.TrySubmit(func() {}); !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("Expected ErrPoolClosed, got %v", err)
+		}
+		if stats = pool.Stats(); stats.Submitted != 4 || stats.Rejected != 2 {
+			t.Errorf("Closed pool should count rejected task: %+v", stats)
+		}
+	})
+
+	t.Run("AntsPool", func(t *testing.T) {
+		pool := NewAntsPool(4)
+		pool.SetSlowTaskThreshold(time.Millisecond)
+		for i := 0; i < 10; i++ {
+			pool.SubmitWithId(i, func() { time.Sleep(2 * time.Millisecond) })
+		}
+		pool.Submit(func() { panic("ants panic") })
+		pool.Shutdown(time.Second)
+
+		stats := pool.Stats()
+		if stats.Submitted != 11 || stats.Completed != 11 || stats.Panicked != 1 || stats.Rejected != 0 {
+			t.Errorf("Unexpected counters: %+v", stats)
+		}
+		if stats.QueueDepths != nil || stats.Busy != 0 {
+			t.Errorf("Unexpected queue stats: busy=%d depths=%v", stats.Busy, stats.QueueDepths)
+		}
+		if stats.RunTime.Count != 11 || stats.RunTime.Quantile(0.5) < 2*time.Millisecond {
+			t.Errorf("Unexpected run time histogram: %+v", stats.RunTime)
+		}
+
+		if err := pool.TrySubmit(func() {}); err == nil {
+			t.Error("Expected error after shutdown")
+		}
+		if stats = pool.Stats(); stats.Rejected != 1 {
+			t.Errorf("Expected 1 rejected task, got %d", stats.Rejected)
+		}
+	})
+
+	t.Run("Histogram", func(t *testing.T) {
+		var h histogram
+		if s := h.snapshot(); s.Mean() != 0 || s.Quantile(0.99) != 0 {
+			t.Error("Empty histogram should report zero")
+		}
+		for _, d := range []time.Duration{50 * time.Microsecond, 3 * time.Millisecond, 3 * time.Millisecond, 20 * time.Second} {
+			h.observe(d)
+		}
+		s := h.snapshot()
+		if s.Count != 4 || s.Max != 20*time.Second || len(s.Counts) != len(s.Bounds)+1 {
+			t.Fatalf("Unexpected snapshot: %+v", s)
+		}
+		if s.Counts[0] != 1 || s.Counts[3] != 2 || s.Counts[len(s.Counts)-1] != 1 {
+			t.Errorf("Unexpected buckets: %v", s.Counts)
+		}
+		if got := s.Quantile(0.5); got != 5*time.Millisecond {
+			t.Errorf("Expected p50 5ms, got %v", got)
+		}
+		if got := s.Quantile(1); got != 20*time.Second {
+			t.Errorf("Expected p100 20s, got %v", got)
+		}
+		if got := s.Mean(); got != (50*time.Microsecond+6*time.Millisecond+20*time.Second)/4 {
+			t.Errorf("Unexpected mean %v", got)
+		}
+	})
+}
+
+func TestTaskLabel(t *testing.T) {
+	if got := newCustomTask("uuid-1", nil, func() {}).label(); got != "taskId=uuid-1" {
+		t.Errorf("Task without id should be labeled by task id, got %s", got)
+	}
+	if got := newCustomTask("uuid-2", 7, func() {}).label(); got != "id=7" {
+		t.Errorf("Task with id should be labeled by id, got %s", got)
+	}
+}
@@ -0,0 +1,266 @@
+package poolUtil
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Tomatosky/jo-util/logger"
+)
+
+const (
+	defaultWheelTick = 10 * time.Millisecond // 默认时间刻度
+	defaultWheelSize = 512                   // 默认每层槽数
+)
+
+// TimingWheelOpt TimingWheel 的配置
+type TimingWheelOpt struct {
+	Pool      IPool         // 执行任务的线程池，必填
+	Tick      time.Duration // 时间刻度，定时器的精度，默认10ms
+	WheelSize int           // 每层时间轮的槽数，默认512，超出最底层范围的定时器放入更高层
+}
+
+// TimingWheel 分层时间轮，适合管理大量定时器，所有定时器共用一个驱动协程
+// 第 i 层每个槽的跨度为 Tick*WheelSize^i，高层的定时器临近到期时逐层降级，到期后提交到线程池执行
+// 线程池队列已满（ErrQueueFull）时任务在下一个刻度重试，线程池关闭等其他错误记录日志后丢弃
+type TimingWheel struct {
+	pool   IPool
+	tick   time.Duration
+	size   int64
+	mu     sync.Mutex
+	levels [][]*list.List // levels[i][j] 为第 i 层第 j 个槽中的定时器
+	ticks  int64          // 已经走过的刻度数
+	start  time.Time
+	now    func() time.Time
+	count  int      // 未到期的定时器数量
+	retry  []func() // 线程池队列已满未能提交的任务，下一个刻度重试
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// WheelTimer 时间轮中的定时器
+type WheelTimer struct {
+	wheel   *TimingWheel
+	task    func()
+	expire  int64 // 到期的刻度
+	period  int64 // 重复执行的间隔刻度，0 表示只执行一次
+	bucket  *list.List
+	element *list.Element
+}
+
+// NewTimingWheel 创建时间轮并启动驱动协程
+func NewTimingWheel(opt *TimingWheelOpt) *TimingWheel {
+	if opt == nil || opt.Pool == nil {
+		logger.Log.Error(fmt.Sprintf("%v", "pool cannot be nil"))
+		panic("pool cannot be nil")
+	}
+	tw := &TimingWheel{
+		pool:  opt.Pool,
+		tick:  opt.Tick,
+		size:  int64(opt.WheelSize),
+		start: time.Now(),
+		now:   time.Now,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	if tw.tick <= 0 {
+		tw.tick = defaultWheelTick
+	}
+	if tw.size <= 1 {
+		tw.size = defaultWheelSize
+	}
+	go tw.run()
+	return tw
+}
+
+// AfterFunc 在 d 之后执行 task，精度为一个刻度
+func (tw *TimingWheel) AfterFunc(d time.Duration, task func()) *WheelTimer {
+	return tw.schedule(d, 0, task)
+}
+
+// Every 每隔 interval 执行一次 task，第一次在 interval 之后执行
+// 驱动协程落后时会补齐错过的执行
+func (tw *TimingWheel) Every(interval time.Duration, task func()) *WheelTimer {
+	if interval <= 0 {
+		logger.Log.Error(fmt.Sprintf("%v", "interval must be greater than 0"))
+		panic("interval must be greater than 0")
+	}
+	return tw.schedule(interval, tw.toTicks(interval), task)
+}
+
+// Len 未到期的定时器数量
+func (tw *TimingWheel) Len() int {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.count
+}
+
+// Stop 停止时间轮，未到期的定时器不再执行，之后添加的定时器也不会执行
+func (tw *TimingWheel) Stop() {
+	tw.once.Do(func() {
+		close(tw.stop)
+		<-tw.done
+	})
+}
+
+// Stop 取消定时器，定时器已执行（只执行一次的定时器）或已取消时返回false
+func (t *WheelTimer) Stop() bool {
+	tw := t.wheel
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if t.element == nil {
+		return false
+	}
+	tw.remove(t)
+	t.period = 0
+	return true
+}
+
+func (tw *TimingWheel) schedule(d time.Duration, period int64, task func()) *WheelTimer {
+	if task == nil {
+		logger.Log.Error(fmt.Sprintf("%v", "task cannot be nil"))
+		panic("task cannot be nil")
+	}
+	t := &WheelTimer{wheel: tw, task: task, period: period}
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	select {
+	case <-tw.stop:
+		return t
+	default:
+	}
+	// 按实际经过的时间计算到期刻度，保证不会提前执行
+	t.expire = max(tw.toTicks(tw.now().Sub(tw.start)+d), tw.ticks+1)
+	tw.add(t)
+	return t
+}
+
+// toTicks 将时间转换为刻度数，向上取整且至少为1
+func (tw *TimingWheel) toTicks(d time.Duration) int64 {
+	return max(int64((d+tw.tick-1)/tw.tick), 1)
+}
+
+// add 将定时器放入能容纳其到期时间的最低层，调用时需持有锁
+func (tw *TimingWheel) add(t *WheelTimer) {
+	unit := int64(1) // 当前层每个槽的刻度数
+	for level := 0; ; level++ {
+		if level == len(tw.levels) {
+			tw.levels = append(tw.levels, make([]*list.List, tw.size))
+		}
+		// 当前层能容纳 [本层当前槽的起点, 起点 + unit*size) 内的定时器
+		last := unit > math.MaxInt64/tw.size
+		if last || t.expire < tw.ticks-tw.ticks%unit+unit*tw.size {
+			index := (t.expire / unit) % tw.size
+			bucket := tw.levels[level][index]
+			if bucket == nil {
+				bucket = list.New()
+				tw.levels[level][index] = bucket
+			}
+			t.bucket = bucket
+			t.element = bucket.PushBack(t)
+			tw.count++
+			return
+		}
+		unit *= tw.size
+	}
+}
+
+// remove 从槽中移除定时器，调用时需持有锁
+func (tw *TimingWheel) remove(t *WheelTimer) {
+	t.bucket.Remove(t.element)
+	t.bucket = nil
+	t.element = nil
+	tw.count--
+}
+
+// advance 前进一个刻度，返回到期的定时器，调用时需持有锁
+func (tw *TimingWheel) advance() []*WheelTimer {
+	tw.ticks++
+	var expired []*WheelTimer
+
+	// 高层到达槽的边界时，将该槽的定时器降级到低层
+	unit := int64(1)
+	for level := 1; level < len(tw.levels); level++ {
+		unit *= tw.size
+		if tw.ticks%unit != 0 {
+			break
+		}
+		bucket := tw.levels[level][(tw.ticks/unit)%tw.size]
+		for bucket != nil && bucket.Len() > 0 {
+			t := bucket.Front().Value.(*WheelTimer)
+			tw.remove(t)
+			if t.expire <= tw.ticks {
+				expired = append(expired, t)
+			} else {
+				tw.add(t)
+			}
+		}
+	}
+
+	bucket := tw.levels[0][tw.ticks%tw.size]
+	for bucket != nil && bucket.Len() > 0 {
+		t := bucket.Front().Value.(*WheelTimer)
+		tw.remove(t)
+		expired = append(expired, t)
+	}
+
+	// 重复执行的定时器重新放入时间轮
+	for _, t := range expired {
+		if t.period > 0 {
+			t.expire += t.period
+			tw.add(t)
+		}
+	}
+	return expired
+}
+
+// advanceTo 前进到指定刻度，将上次未能提交的任务和到期的定时器提交到线程池
+// 线程池队列已满时留到下一个刻度重试，不阻塞驱动协程
+func (tw *TimingWheel) advanceTo(target int64) {
+	tw.mu.Lock()
+	tasks := tw.retry
+	tw.retry = nil
+	for tw.ticks < target {
+		for _, t := range tw.advance() {
+			tasks = append(tasks, t.task)
+		}
+	}
+	tw.mu.Unlock()
+
+	var retry []func()
+	for _, task := range tasks {
+		err := trySubmit(tw.pool, task, nil)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrQueueFull):
+			retry = append(retry, task)
+		default:
+			logger.Log.Error(fmt.Sprintf("timing wheel submit task error: %v", err))
+		}
+	}
+	if len(retry) > 0 {
+		logger.Log.Warn(fmt.Sprintf("timing wheel pool is full, %d tasks will retry on next tick", len(retry)))
+		tw.mu.Lock()
+		tw.retry = append(tw.retry, retry...)
+		tw.mu.Unlock()
+	}
+}
+
+// run 驱动协程，每个刻度前进一次，落后时一次前进多个刻度
+func (tw *TimingWheel) run() {
+	defer close(tw.done)
+	ticker := time.NewTicker(tw.tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-tw.stop:
+			return
+		case <-ticker.C:
+			tw.advanceTo(int64(tw.now().Sub(tw.start) / tw.tick))
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package poolUtil
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// syncPool 在提交任务的协程中直接执行任务，便于确定性测试
+type syncPool struct{}
+
+func (syncPool) Submit(task func())                  { task() }
+func (syncPool) SubmitWithId(id any, task func())    { task() }
+func (syncPool) Shutdown(timeout time.Duration) bool { return false }
+
+// newManualWheel 创建时间静止的时间轮，测试中通过 advanceTo 手动驱动
+func newManualWheel(size int) *TimingWheel {
+	tw := NewTimingWheel(&TimingWheelOpt{Pool: syncPool{}, Tick: time.Hour, WheelSize: size})
+	tw.mu.Lock()
+	tw.now = func() time.Time { return tw.start }
+	tw.mu.Unlock()
+	return tw
+}
+
+func TestTimingWheelCascade(t *testing.T) {
+	tw := newManualWheel(4)
+	defer tw.Stop()
+
+	// 覆盖多层时间轮：4、16、64 个刻度为各层的边界
+	delays := []int64{1, 3, 4, 5, 15, 16, 17, 63, 64, 65, 200, 1000}
+	fired := make(map[int64]int64)
+	for _, d := range delays {
+		tw.AfterFunc(time.Duration(d)*time.Hour, func() {
+			fired[d] = tw.ticks
+		})
+	}
+	if tw.Len() != len(delays) {
+		t.Fatalf("Expected %d timers, got %d", len(delays), tw.Len())
+	}
+	for target := int64(1); target <= 1000; target++ {
+		tw.advanceTo(target)
+	}
+	for _, d := range delays {
+		if fired[d] != d {
+			t.Errorf("Timer with delay %d fired at tick %d", d, fired[d])
+		}
+	}
+	if tw.Len() != 0 {
+		t.Errorf("Expected no timers left, got %d", tw.Len())
+	}
+}
+
+func TestTimingWheelManual(t *testing.T) {
+	t.Run("Stop", func(t *testing.T) {
+		tw := newManualWheel(8)
+		defer tw.Stop()
+		var ran bool
+		timer := tw.AfterFunc(100*time.Hour, func() { ran = true })
+		tw.advanceTo(50)
+		if !timer.Stop() || timer.Stop() {
+			t.Error("Stop should succeed only once")
+		}
+		tw.advanceTo(200)
+		if ran || tw.Len() != 0 {
+			t.Error("Stopped timer should not run")
+		}
+
+		timer = tw.AfterFunc(time.Hour, func() {})
+		tw.advanceTo(201)
+		if timer.Stop() {
+			t.Error("Stop after firing should return false")
+		}
+	})
+
+	t.Run("Every", func(t *testing.T) {
+		tw := newManualWheel(8)
+		defer tw.Stop()
+		var fires []int64
+		timer := tw.Every(3*time.Hour, func() { fires = append(fires, tw.ticks) })
+		for target := int64(1); target <= 10; target++ {
+			tw.advanceTo(target)
+		}
+		if len(fires) != 3 || fires[0] != 3 || fires[1] != 6 || fires[2] != 9 {
+			t.Errorf("Unexpected fire ticks %v", fires)
+		}
+		// 一次前进多个刻度时补齐错过的执行
+		tw.advanceTo(30)
+		if len(fires) != 10 {
+			t.Errorf("Expected 10 fires, got %d", len(fires))
+		}
+		if !timer.Stop() {
+			t.Error("Stop on periodic timer should succeed")
+		}
+		n := len(fires)
+		tw.advanceTo(60)
+		if len(fires) != n {
+			t.Error("Stopped periodic timer should not run")
+		}
+	})
+
+	t.Run("SubMinimumDelay", func(t *testing.T) {
+		tw := newManualWheel(8)
+		defer tw.Stop()
+		var ran bool
+		tw.AfterFunc(0, func() { ran = true })
+		tw.advanceTo(1)
+		if !ran {
+			t.Error("Timer with zero delay should fire on next tick")
+		}
+	})
+
+	t.Run("PoolFull", func(t *testing.T) {
+		pool, release := newBusyIdPool(t, &IdPoolOpt{RejectPolicy: RejectDiscard})
+		defer pool.Shutdown(time.Second)
+		tw := NewTimingWheel(&TimingWheelOpt{Pool: pool, Tick: time.Hour, WheelSize: 8})
+		defer tw.Stop()
+		tw.mu.Lock()
+		tw.now = func() time.Time { return tw.start }
+		tw.mu.Unlock()
+
+		// 队列已满时任务留到下一个刻度重试
+		ran := make(chan struct{})
+		tw.AfterFunc(time.Hour, func() { close(ran) })
+		tw.advanceTo(1)
+		tw.advanceTo(1)
+		tw.mu.Lock()
+		pending := len(tw.retry)
+		tw.mu.Unlock()
+		if pending != 1 {
+			t.Fatalf("Expected 1 task to retry, got %d", pending)
+		}
+
+		release()
+		for pool.GetTaskCount(0) != 0 {
+			time.Sleep(time.Millisecond)
+		}
+		tw.advanceTo(1)
+		select {
+		case <-ran:
+		case <-time.After(time.Second):
+			t.Fatal("Rejected task should run after the pool frees up")
+		}
+
+		// 线程池关闭时丢弃任务，不再重试
+		pool.Shutdown(time.Second)
+		tw.AfterFunc(time.Hour, func() { t.Error("Task should not run on closed pool") })
+		tw.advanceTo(2)
+		tw.mu.Lock()
+		defer tw.mu.Unlock()
+		if len(tw.retry) != 0 {
+			t.Errorf("Expected no retry on closed pool, got %d", len(tw.retry))
+		}
+	})
+
+	t.Run("AfterStop", func(t *testing.T) {
+		tw := newManualWheel(8)
+		tw.Stop()
+		tw.Stop()
+		timer := tw.AfterFunc(time.Hour, func() {})
+		if timer.Stop() || tw.Len() != 0 {
+			t.Error("Timer added after Stop should not be scheduled")
+		}
+	})
+}
+
+func TestTimingWheel(t *testing.T) {
+	pool := NewAntsPool(16)
+	defer pool.Shutdown(time.Second)
+	tw := NewTimingWheel(&TimingWheelOpt{Pool: pool, Tick: time.Millisecond, WheelSize: 8})
+	defer tw.Stop()
+
+	const n = 10000
+	var wg sync.WaitGroup
+	var early atomic.Int32
+	wg.Add(n)
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		delay := time.Duration(i%100) * time.Millisecond
+		tw.AfterFunc(delay, func() {
+			if time.Since(start) < delay {
+				early.Add(1)
+			}
+			wg.Done()
+		})
+	}
+
+	var count atomic.Int32
+	timer := tw.Every(5*time.Millisecond, func() { count.Add(1) })
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timers did not fire in time")
+	}
+	if early.Load() != 0 {
+		t.Errorf("%d timers fired early", early.Load())
+	}
+	timer.Stop()
+	if count.Load() < 5 {
+		t.Errorf("Expected periodic timer to fire several times, got %d", count.Load())
+	}
+}
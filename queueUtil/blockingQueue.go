@@ -0,0 +1,194 @@
+package queueUtil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrQueueClosed 队列已关闭
+var ErrQueueClosed = errors.New("queue is closed")
+
+// BlockingQueue 并发安全的阻塞队列，支持有界和无界两种模式
+// 关闭后不能再入队，出队操作会先取完剩余元素再返回 ErrQueueClosed
+type BlockingQueue[T any] struct {
+	mu       sync.Mutex
+	items    *Queue[T]
+	capacity int           // 容量，<=0 表示无界
+	closed   bool          // 是否已关闭
+	notEmpty chan struct{} // 有新元素时关闭并替换，用于唤醒等待出队的协程
+	notFull  chan struct{} // 有空位时关闭并替换，用于唤醒等待入队的协程
+	takers   bool          // 是否有协程等待出队
+	putters  bool          // 是否有协程等待入队
+}
+
+// NewBlockingQueue 创建阻塞队列
+// capacity: 容量，<=0 表示无界队列，Put 永不阻塞
+func NewBlockingQueue[T any](capacity int) *BlockingQueue[T] {
+	return &BlockingQueue[T]{
+		items:    NewQueue[T](),
+		capacity: capacity,
+		notEmpty: make(chan struct{}),
+		notFull:  make(chan struct{}),
+	}
+}
+
+// Put 入队，队列已满时阻塞直到有空位、ctx 结束或队列关闭
+func (q *BlockingQueue[T]) Put(ctx context.Context, item T) error {
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return ErrQueueClosed
+		}
+		if !q.isFull() {
+			q.enqueue(item)
+			q.mu.Unlock()
+			return nil
+		}
+		wait := q.notFull
+		q.putters = true
+		q.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Offer 在 timeout 内入队，成功返回true，timeout<=0 时不等待
+func (q *BlockingQueue[T]) Offer(item T, timeout time.Duration) bool {
+	if timeout <= 0 {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if q.closed || q.isFull() {
+			return false
+		}
+		q.enqueue(item)
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return q.Put(ctx, item) == nil
+}
+
+// Take 出队，队列为空时阻塞直到有元素、ctx 结束或队列关闭
+func (q *BlockingQueue[T]) Take(ctx context.Context) (T, error) {
+	for {
+		q.mu.Lock()
+		if item, ok := q.dequeue(); ok {
+			q.mu.Unlock()
+			return item, nil
+		}
+		if q.closed {
+			q.mu.Unlock()
+			var zero T
+			return zero, ErrQueueClosed
+		}
+		wait := q.notEmpty
+		q.takers = true
+		q.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Poll 在 timeout 内出队，成功返回true，timeout<=0 时不等待
+func (q *BlockingQueue[T]) Poll(timeout time.Duration) (T, bool) {
+	if timeout <= 0 {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return q.dequeue()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	item, err := q.Take(ctx)
+	return item, err == nil
+}
+
+// DrainTo 不阻塞地取出最多 n 个元素，n<=0 时取出全部元素
+func (q *BlockingQueue[T]) DrainTo(n int) []T {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if n <= 0 || n > q.items.Len() {
+		n = q.items.Len()
+	}
+	result := make([]T, 0, n)
+	for len(result) < n {
+		item, _ := q.items.Dequeue()
+		result = append(result, item)
+	}
+	if n > 0 {
+		q.signal(&q.notFull, &q.putters)
+	}
+	return result
+}
+
+// Close 关闭队列并唤醒所有等待的协程，可重复调用
+func (q *BlockingQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.signal(&q.notEmpty, &q.takers)
+	q.signal(&q.notFull, &q.putters)
+}
+
+// IsClosed 是否已关闭
+func (q *BlockingQueue[T]) IsClosed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
+// Len 当前元素数量
+func (q *BlockingQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.Len()
+}
+
+// Cap 容量，无界队列返回0
+func (q *BlockingQueue[T]) Cap() int {
+	return max(q.capacity, 0)
+}
+
+// isFull 是否已满，调用时需持有锁
+func (q *BlockingQueue[T]) isFull() bool {
+	return q.capacity > 0 && q.items.Len() >= q.capacity
+}
+
+// enqueue 入队并唤醒等待出队的协程，调用时需持有锁
+func (q *BlockingQueue[T]) enqueue(item T) {
+	q.items.Enqueue(item)
+	q.signal(&q.notEmpty, &q.takers)
+}
+
+// dequeue 出队并唤醒等待入队的协程，调用时需持有锁
+func (q *BlockingQueue[T]) dequeue() (T, bool) {
+	item, ok := q.items.Dequeue()
+	if ok && q.capacity > 0 {
+		q.signal(&q.notFull, &q.putters)
+	}
+	return item, ok
+}
+
+// signal 唤醒等待在 ch 上的所有协程，没有等待者时不做任何操作，调用时需持有锁
+func (q *BlockingQueue[T]) signal(ch *chan struct{}, waiting *bool) {
+	if !*waiting {
+		return
+	}
+	close(*ch)
+	*ch = make(chan struct{})
+	*waiting = false
+}
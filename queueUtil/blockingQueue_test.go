@@ -0,0 +1,226 @@
+package queueUtil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestBlockingQueueBasic 测试基本的入队出队
+func TestBlockingQueueBasic(t *testing.T) {
+	q := NewBlockingQueue[int](2)
+	ctx := context.Background()
+
+	if q.Cap() != 2 || q.Len() != 0 {
+		t.Errorf("Unexpected cap %d or len %d", q.Cap(), q.Len())
+	}
+	if err := q.Put(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if !q.Offer(2, 0) {
+		t.Error("Offer should succeed")
+	}
+	if q.Offer(3, 0) {
+		t.Error("Offer to full queue should fail")
+	}
+	if q.Offer(3, 20*time.Millisecond) {
+		t.Error("Offer with timeout to full queue should fail")
+	}
+
+	v, err := q.Take(ctx)
+	if err != nil || v != 1 {
+		t.Errorf("Take() = %v, %v", v, err)
+	}
+	v, ok := q.Poll(0)
+	if !ok || v != 2 {
+		t.Errorf("Poll() = %v, %v", v, ok)
+	}
+	if _, ok := q.Poll(20 * time.Millisecond); ok {
+		t.Error("Poll from empty queue should fail")
+	}
+}
+
+// TestBlockingQueueBlocking 测试阻塞与唤醒
+func TestBlockingQueueBlocking(t *testing.T) {
+	q := NewBlockingQueue[int](1)
+	ctx := context.Background()
+
+	done := make(chan int)
+	go func() {
+		v, _ := q.Take(ctx)
+		done <- v
+	}()
+	time.Sleep(20 * time.Millisecond)
+	_ = q.Put(ctx, 42)
+	select {
+	case v := <-done:
+		if v != 42 {
+			t.Errorf("Expected 42, got %d", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Take was not woken up")
+	}
+
+	// 队列满时 Put 阻塞，出队后被唤醒
+	_ = q.Put(ctx, 1)
+	putDone := make(chan error)
+	go func() {
+		putDone <- q.Put(ctx, 2)
+	}()
+	select {
+	case <-putDone:
+		t.Fatal("Put should block when queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if v, _ := q.Take(ctx); v != 1 {
+		t.Errorf("Expected 1, got %d", v)
+	}
+	if err := <-putDone; err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := q.Take(ctx); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+}
+
+// TestBlockingQueueContext 测试 ctx 取消
+func TestBlockingQueueContext(t *testing.T) {
+	q := NewBlockingQueue[int](1)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := q.Take(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+
+	_ = q.Put(context.Background(), 1)
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	cancel2()
+	if err := q.Put(ctx2, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Canceled, got %v", err)
+	}
+}
+
+// TestBlockingQueueUnbounded 测试无界队列
+func TestBlockingQueueUnbounded(t *testing.T) {
+	q := NewBlockingQueue[int](0)
+	for i := 0; i < 1000; i++ {
+		if !q.Offer(i, 0) {
+			t.Fatal("Offer to unbounded queue should succeed")
+		}
+	}
+	if q.Len() != 1000 || q.Cap() != 0 {
+		t.Errorf("Unexpected len %d or cap %d", q.Len(), q.Cap())
+	}
+}
+
+// TestBlockingQueueDrainTo 测试批量取出
+func TestBlockingQueueDrainTo(t *testing.T) {
+	q := NewBlockingQueue[int](0)
+	for i := 0; i < 5; i++ {
+		q.Offer(i, 0)
+	}
+	got := q.DrainTo(3)
+	if len(got) != 3 || got[0] != 0 || got[2] != 2 {
+		t.Errorf("DrainTo(3) = %v", got)
+	}
+	got = q.DrainTo(0)
+	if len(got) != 2 || got[1] != 4 {
+		t.Errorf("DrainTo(0) = %v", got)
+	}
+	if got := q.DrainTo(10); len(got) != 0 {
+		t.Errorf("DrainTo on empty queue = %v", got)
+	}
+}
+
+// TestBlockingQueueClose 测试关闭
+func TestBlockingQueueClose(t *testing.T) {
+	q := NewBlockingQueue[int](1)
+	ctx := context.Background()
+	_ = q.Put(ctx, 1)
+
+	// 阻塞的 Put 在关闭后返回
+	putErr := make(chan error)
+	go func() {
+		putErr <- q.Put(ctx, 2)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	q.Close()
+	q.Close()
+	if err := <-putErr; !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed, got %v", err)
+	}
+	if !q.IsClosed() || q.Offer(3, 0) {
+		t.Error("Offer after close should fail")
+	}
+
+	// 关闭后先取完剩余元素
+	if v, err := q.Take(ctx); err != nil || v != 1 {
+		t.Errorf("Take() = %v, %v", v, err)
+	}
+	if _, err := q.Take(ctx); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed, got %v", err)
+	}
+
+	// 阻塞的 Take 在关闭后返回
+	q2 := NewBlockingQueue[int](0)
+	takeErr := make(chan error)
+	go func() {
+		_, err := q2.Take(ctx)
+		takeErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	q2.Close()
+	if err := <-takeErr; !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed, got %v", err)
+	}
+}
+
+// TestBlockingQueueProducerConsumer 测试多生产者多消费者
+func TestBlockingQueueProducerConsumer(t *testing.T) {
+	q := NewBlockingQueue[int](8)
+	ctx := context.Background()
+	const producers, perProducer = 4, 1000
+
+	var producerWg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		producerWg.Add(1)
+		go func() {
+			defer producerWg.Done()
+			for i := 0; i < perProducer; i++ {
+				if err := q.Put(ctx, 1); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+
+	var mu sync.Mutex
+	sum := 0
+	var consumerWg sync.WaitGroup
+	for c := 0; c < 3; c++ {
+		consumerWg.Add(1)
+		go func() {
+			defer consumerWg.Done()
+			for {
+				v, err := q.Take(ctx)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				sum += v
+				mu.Unlock()
+			}
+		}()
+	}
+
+	producerWg.Wait()
+	q.Close()
+	consumerWg.Wait()
+	if sum != producers*perProducer {
+		t.Errorf("Expected %d, got %d", producers*perProducer, sum)
+	}
+}
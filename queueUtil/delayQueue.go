@@ -0,0 +1,150 @@
+package queueUtil
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Delayed 延迟队列中的元素
+type Delayed[T any] struct {
+	Value    T
+	Deadline time.Time // 到期时间，到期后才能出队
+}
+
+// DelayQueue 并发安全的延迟队列，元素只有在到期后才能出队，按到期时间先后出队
+// 可用于定时器、失败重试等场景
+type DelayQueue[T any] struct {
+	mu      sync.Mutex
+	pq      *PriorityQueue[Delayed[T]]
+	changed chan struct{} // 队首变化时关闭并替换，用于唤醒等待的协程
+	waiting bool          // 是否有协程在等待
+}
+
+// NewDelayQueue 创建延迟队列
+func NewDelayQueue[T any]() *DelayQueue[T] {
+	return &DelayQueue[T]{
+		pq: NewPriorityQueue(func(a, b Delayed[T]) bool {
+			return a.Deadline.Before(b.Deadline)
+		}),
+		changed: make(chan struct{}),
+	}
+}
+
+// Put 添加元素，delay 后到期，返回的句柄可用于 Remove
+func (q *DelayQueue[T]) Put(item T, delay time.Duration) *Handle[Delayed[T]] {
+	return q.PutAt(item, time.Now().Add(delay))
+}
+
+// PutAt 添加元素，在 deadline 到期
+func (q *DelayQueue[T]) PutAt(item T, deadline time.Time) *Handle[Delayed[T]] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	h := q.pq.Push(Delayed[T]{Value: item, Deadline: deadline})
+	h.owner = q
+	// 新元素成为队首时，等待的协程需要重新计算等待时间
+	if q.pq.items[0] == h {
+		q.signal()
+	}
+	return h
+}
+
+// Remove 移除未出队的元素，可用于取消定时任务
+func (q *DelayQueue[T]) Remove(h *Handle[Delayed[T]]) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.pq.contains(h, q) {
+		return false
+	}
+	q.pq.removeAt(h.index)
+	return true
+}
+
+// Take 取出到期的元素，没有到期元素时阻塞直到有元素到期或 ctx 结束
+func (q *DelayQueue[T]) Take(ctx context.Context) (T, error) {
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		q.mu.Lock()
+		var delay time.Duration
+		if head, ok := q.pq.Peek(); ok {
+			delay = time.Until(head.Deadline)
+			if delay <= 0 {
+				q.pq.Pop()
+				q.mu.Unlock()
+				return head.Value, nil
+			}
+		}
+		wait := q.changed
+		q.waiting = true
+		q.mu.Unlock()
+
+		var expired <-chan time.Time
+		if delay > 0 {
+			if timer == nil {
+				timer = time.NewTimer(delay)
+			} else {
+				timer.Reset(delay)
+			}
+			expired = timer.C
+		}
+		select {
+		case <-wait:
+		case <-expired:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Poll 不阻塞地取出到期的元素，没有到期元素时返回false
+func (q *DelayQueue[T]) Poll() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	head, ok := q.pq.Peek()
+	if !ok || head.Deadline.After(time.Now()) {
+		var zero T
+		return zero, false
+	}
+	q.pq.Pop()
+	return head.Value, true
+}
+
+// PeekDeadline 返回最早的到期时间
+func (q *DelayQueue[T]) PeekDeadline() (time.Time, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	head, ok := q.pq.Peek()
+	return head.Deadline, ok
+}
+
+// Len 元素数量，包括未到期的元素
+func (q *DelayQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pq.Len()
+}
+
+// Clear 清空队列
+func (q *DelayQueue[T]) Clear() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pq.Clear()
+	q.signal()
+}
+
+// signal 唤醒所有等待的协程，调用时需持有锁
+func (q *DelayQueue[T]) signal() {
+	if !q.waiting {
+		return
+	}
+	close(q.changed)
+	q.changed = make(chan struct{})
+	q.waiting = false
+}
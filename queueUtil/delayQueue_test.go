@@ -0,0 +1,111 @@
+package queueUtil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestDelayQueueOrder 测试按到期时间出队
+func TestDelayQueueOrder(t *testing.T) {
+	q := NewDelayQueue[string]()
+	q.Put("c", 60*time.Millisecond)
+	q.Put("a", 20*time.Millisecond)
+	q.Put("b", 40*time.Millisecond)
+
+	if _, ok := q.Poll(); ok {
+		t.Error("Poll should fail before deadline")
+	}
+	if q.Len() != 3 {
+		t.Errorf("Expected len 3, got %d", q.Len())
+	}
+
+	start := time.Now()
+	ctx := context.Background()
+	for _, want := range []string{"a", "b", "c"} {
+		v, err := q.Take(ctx)
+		if err != nil || v != want {
+			t.Fatalf("Take() = %v, %v, want %v", v, err, want)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Take returned too early: %v", elapsed)
+	}
+}
+
+// TestDelayQueueEarlierPut 测试等待期间加入更早到期的元素
+func TestDelayQueueEarlierPut(t *testing.T) {
+	q := NewDelayQueue[int]()
+	q.Put(1, time.Hour)
+
+	done := make(chan int)
+	go func() {
+		v, _ := q.Take(context.Background())
+		done <- v
+	}()
+	time.Sleep(20 * time.Millisecond)
+	q.Put(2, 10*time.Millisecond)
+
+	select {
+	case v := <-done:
+		if v != 2 {
+			t.Errorf("Expected 2, got %d", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Take was not woken up by earlier element")
+	}
+}
+
+// TestDelayQueueRemove 测试取消元素
+func TestDelayQueueRemove(t *testing.T) {
+	q := NewDelayQueue[int]()
+	h := q.Put(1, 10*time.Millisecond)
+	q.Put(2, 30*time.Millisecond)
+
+	if h.Value().Value != 1 {
+		t.Errorf("Unexpected handle value %v", h.Value())
+	}
+	if !q.Remove(h) || q.Remove(h) {
+		t.Error("Remove should succeed only once")
+	}
+	if d, ok := q.PeekDeadline(); !ok || time.Until(d) < 10*time.Millisecond {
+		t.Errorf("Unexpected deadline %v", d)
+	}
+
+	v, err := q.Take(context.Background())
+	if err != nil || v != 2 {
+		t.Errorf("Take() = %v, %v", v, err)
+	}
+
+	// 已到期的元素可立即取出
+	q.PutAt(3, time.Now().Add(-time.Second))
+	if v, ok := q.Poll(); !ok || v != 3 {
+		t.Errorf("Poll() = %v, %v", v, ok)
+	}
+}
+
+// TestDelayQueueContext 测试 ctx 取消
+func TestDelayQueueContext(t *testing.T) {
+	q := NewDelayQueue[int]()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := q.Take(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+
+	q.Put(1, time.Hour)
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel2()
+	if _, err := q.Take(ctx2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+
+	q.Clear()
+	if q.Len() != 0 {
+		t.Error("Queue should be empty after Clear")
+	}
+	if _, ok := q.PeekDeadline(); ok {
+		t.Error("PeekDeadline on empty queue should fail")
+	}
+}
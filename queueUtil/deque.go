@@ -0,0 +1,292 @@
+package queueUtil
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+
+	"github.com/Tomatosky/jo-util/logger"
+)
+
+const dequeMinCapacity = 16 // 最小容量
+
+// Deque 基于环形缓冲区的双端队列，非并发安全
+// 容量为2的幂，满时翻倍扩容，元素数量不足容量的1/4时减半缩容，不会缩容到初始容量以下
+type Deque[T any] struct {
+	buf    []T
+	head   int // 队首下标
+	size   int
+	minCap int // 初始容量，缩容和 Clear 的下限
+}
+
+// NewDeque 创建双端队列
+// capacity: 可选的初始容量，向上取整为2的幂
+func NewDeque[T any](capacity ...int) *Deque[T] {
+	c := dequeMinCapacity
+	if len(capacity) > 0 {
+		for c < capacity[0] {
+			c <<= 1
+		}
+	}
+	return &Deque[T]{buf: make([]T, c), minCap: c}
+}
+
+// PushBack 添加元素到队尾
+func (d *Deque[T]) PushBack(item T) {
+	d.grow()
+	d.buf[d.index(d.size)] = item
+	d.size++
+}
+
+// PushFront 添加元素到队首
+func (d *Deque[T]) PushFront(item T) {
+	d.grow()
+	d.head = d.index(len(d.buf) - 1)
+	d.buf[d.head] = item
+	d.size++
+}
+
+// PopFront 取出队首元素
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	item := d.buf[d.head]
+	d.buf[d.head] = zero // 避免持有已出队元素的引用
+	d.head = d.index(1)
+	d.size--
+	d.shrink()
+	return item, true
+}
+
+// PopBack 取出队尾元素
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	i := d.index(d.size - 1)
+	item := d.buf[i]
+	d.buf[i] = zero
+	d.size--
+	d.shrink()
+	return item, true
+}
+
+// PeekFront 查看队首元素
+func (d *Deque[T]) PeekFront() (T, bool) {
+	if d.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.buf[d.head], true
+}
+
+// PeekBack 查看队尾元素
+func (d *Deque[T]) PeekBack() (T, bool) {
+	if d.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.buf[d.index(d.size-1)], true
+}
+
+// At 获取第i个元素（0为队首），支持负数索引，-1表示队尾
+func (d *Deque[T]) At(i int) T {
+	if i < 0 {
+		i += d.size
+	}
+	if i < 0 || i >= d.size {
+		logger.Log.Error(fmt.Sprintf("%v", "index out of range"))
+		panic("index out of range")
+	}
+	return d.buf[d.index(i)]
+}
+
+// Len 元素数量
+func (d *Deque[T]) Len() int {
+	return d.size
+}
+
+// Cap 当前容量
+func (d *Deque[T]) Cap() int {
+	return len(d.buf)
+}
+
+// IsEmpty 是否为空
+func (d *Deque[T]) IsEmpty() bool {
+	return d.size == 0
+}
+
+// Clear 清空队列并恢复为初始容量
+func (d *Deque[T]) Clear() {
+	d.buf = make([]T, d.minCap)
+	d.head = 0
+	d.size = 0
+}
+
+// All 返回从队首到队尾的迭代器，遍历过程中不应修改队列
+func (d *Deque[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < d.size; i++ {
+			if !yield(d.buf[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward 返回从队尾到队首的迭代器，遍历过程中不应修改队列
+func (d *Deque[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := d.size - 1; i >= 0; i-- {
+			if !yield(d.buf[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice 按从队首到队尾的顺序返回所有元素
+func (d *Deque[T]) ToSlice() []T {
+	result := make([]T, d.size)
+	d.copyTo(result)
+	return result
+}
+
+// index 第i个元素在缓冲区中的下标
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) & (len(d.buf) - 1)
+}
+
+// copyTo 按顺序复制所有元素到 dst
+func (d *Deque[T]) copyTo(dst []T) {
+	if d.head+d.size <= len(d.buf) {
+		copy(dst, d.buf[d.head:d.head+d.size])
+		return
+	}
+	n := copy(dst, d.buf[d.head:])
+	copy(dst[n:], d.buf[:d.size-n])
+}
+
+// resize 调整容量，元素重新从下标0开始存放
+func (d *Deque[T]) resize(capacity int) {
+	buf := make([]T, capacity)
+	d.copyTo(buf)
+	d.buf = buf
+	d.head = 0
+}
+
+// grow 已满时翻倍扩容
+func (d *Deque[T]) grow() {
+	if d.size == len(d.buf) {
+		d.resize(len(d.buf) << 1)
+	}
+}
+
+// shrink 元素数量不足容量的1/4时减半缩容，释放原有的缓冲区，不低于初始容量
+func (d *Deque[T]) shrink() {
+	if len(d.buf) > d.minCap && d.size <= len(d.buf)/4 {
+		d.resize(len(d.buf) >> 1)
+	}
+}
+
+// SafeDeque 并发安全的双端队列
+type SafeDeque[T any] struct {
+	deque *Deque[T]
+	mutex sync.Mutex
+}
+
+// NewSafeDeque 创建并发安全的双端队列
+// capacity: 可选的初始容量
+func NewSafeDeque[T any](capacity ...int) *SafeDeque[T] {
+	return &SafeDeque[T]{deque: NewDeque[T](capacity...)}
+}
+
+// PushBack 添加元素到队尾
+func (d *SafeDeque[T]) PushBack(item T) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.deque.PushBack(item)
+}
+
+// PushFront 添加元素到队首
+func (d *SafeDeque[T]) PushFront(item T) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.deque.PushFront(item)
+}
+
+// PopFront 取出队首元素
+func (d *SafeDeque[T]) PopFront() (T, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.deque.PopFront()
+}
+
+// PopBack 取出队尾元素
+func (d *SafeDeque[T]) PopBack() (T, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.deque.PopBack()
+}
+
+// PeekFront 查看队首元素
+func (d *SafeDeque[T]) PeekFront() (T, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.deque.PeekFront()
+}
+
+// PeekBack 查看队尾元素
+func (d *SafeDeque[T]) PeekBack() (T, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.deque.PeekBack()
+}
+
+// At 获取第i个元素（0为队首），支持负数索引，-1表示队尾
+func (d *SafeDeque[T]) At(i int) T {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.deque.At(i)
+}
+
+// Len 元素数量
+func (d *SafeDeque[T]) Len() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.deque.Len()
+}
+
+// IsEmpty 是否为空
+func (d *SafeDeque[T]) IsEmpty() bool {
+	return d.Len() == 0
+}
+
+// Clear 清空队列
+func (d *SafeDeque[T]) Clear() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.deque.Clear()
+}
+
+// All 返回从队首到队尾的迭代器，遍历的是调用时的快照
+func (d *SafeDeque[T]) All() iter.Seq[T] {
+	snapshot := d.ToSlice()
+	return func(yield func(T) bool) {
+		for _, item := range snapshot {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice 按从队首到队尾的顺序返回所有元素
+func (d *SafeDeque[T]) ToSlice() []T {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.deque.ToSlice()
+}
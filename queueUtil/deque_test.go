@@ -0,0 +1,197 @@
+package queueUtil
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestDequePushPop 测试两端的入队出队
+func TestDequePushPop(t *testing.T) {
+	d := NewDeque[int]()
+	if _, ok := d.PopFront(); ok {
+		t.Error("PopFront from empty deque should fail")
+	}
+	if _, ok := d.PeekBack(); ok {
+		t.Error("PeekBack from empty deque should fail")
+	}
+
+	d.PushBack(2)
+	d.PushBack(3)
+	d.PushFront(1)
+	d.PushFront(0)
+	if !reflect.DeepEqual(d.ToSlice(), []int{0, 1, 2, 3}) {
+		t.Errorf("Unexpected elements %v", d.ToSlice())
+	}
+	if v, _ := d.PeekFront(); v != 0 {
+		t.Errorf("PeekFront() = %d", v)
+	}
+	if v, _ := d.PeekBack(); v != 3 {
+		t.Errorf("PeekBack() = %d", v)
+	}
+	if d.At(1) != 1 || d.At(-1) != 3 {
+		t.Error("Unexpected At result")
+	}
+	if v, _ := d.PopBack(); v != 3 {
+		t.Errorf("PopBack() = %d", v)
+	}
+	if v, _ := d.PopFront(); v != 0 {
+		t.Errorf("PopFront() = %d", v)
+	}
+	if d.Len() != 2 || d.IsEmpty() {
+		t.Errorf("Expected len 2, got %d", d.Len())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("At out of range should panic")
+		}
+	}()
+	d.At(2)
+}
+
+// TestDequeGrowAndShrink 测试扩容和缩容
+func TestDequeGrowAndShrink(t *testing.T) {
+	d := NewDeque[int]()
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			d.PushBack(i)
+		} else {
+			d.PushFront(i)
+		}
+	}
+	if d.Cap() != 128 {
+		t.Errorf("Expected cap 128, got %d", d.Cap())
+	}
+	// 队首为最后插入的奇数，队尾为最后插入的偶数
+	if d.At(0) != 99 || d.At(-1) != 98 || d.At(49) != 1 || d.At(50) != 0 {
+		t.Errorf("Unexpected order %v", d.ToSlice())
+	}
+
+	for i := 0; i < 95; i++ {
+		d.PopFront()
+	}
+	if d.Len() != 5 || d.Cap() != dequeMinCapacity {
+		t.Errorf("Expected len 5 and min cap, got %d %d", d.Len(), d.Cap())
+	}
+	if !reflect.DeepEqual(d.ToSlice(), []int{90, 92, 94, 96, 98}) {
+		t.Errorf("Unexpected elements %v", d.ToSlice())
+	}
+
+	d2 := NewDeque[int](100)
+	if d2.Cap() != 128 {
+		t.Errorf("Expected initial cap 128, got %d", d2.Cap())
+	}
+	// 预设容量的队列不会缩容到初始容量以下
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 200; i++ {
+			d2.PushBack(i)
+		}
+		for d2.Len() > 0 {
+			d2.PopFront()
+		}
+		if d2.Cap() != 128 {
+			t.Fatalf("Expected cap to stay at initial 128, got %d", d2.Cap())
+		}
+	}
+	d2.PushBack(1)
+	d2.Clear()
+	if d2.Cap() != 128 || d2.Len() != 0 {
+		t.Errorf("Expected initial cap after Clear, got %d", d2.Cap())
+	}
+}
+
+// TestDequeWrapAround 测试环形缓冲区回绕
+func TestDequeWrapAround(t *testing.T) {
+	d := NewDeque[int]()
+	var want []int
+	for round := 0; round < 50; round++ {
+		d.PushBack(round)
+		want = append(want, round)
+		if round%3 == 0 {
+			d.PopFront()
+			want = want[1:]
+		}
+		if !reflect.DeepEqual(d.ToSlice(), want) {
+			t.Fatalf("Round %d: got %v, want %v", round, d.ToSlice(), want)
+		}
+	}
+}
+
+// TestDequeIterator 测试迭代器
+func TestDequeIterator(t *testing.T) {
+	d := NewDeque[string]()
+	for _, s := range []string{"b", "c"} {
+		d.PushBack(s)
+	}
+	d.PushFront("a")
+
+	var forward, backward []string
+	for s := range d.All() {
+		forward = append(forward, s)
+	}
+	for s := range d.Backward() {
+		backward = append(backward, s)
+		if len(backward) == 2 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(forward, []string{"a", "b", "c"}) {
+		t.Errorf("All() = %v", forward)
+	}
+	if !reflect.DeepEqual(backward, []string{"c", "b"}) {
+		t.Errorf("Backward() = %v", backward)
+	}
+}
+
+// TestSafeDeque 测试并发安全的双端队列
+func TestSafeDeque(t *testing.T) {
+	d := NewSafeDeque[int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func(v int) {
+			defer wg.Done()
+			d.PushBack(v)
+			d.PushFront(v)
+		}(i)
+		go func() {
+			defer wg.Done()
+			for range d.All() {
+			}
+		}()
+	}
+	wg.Wait()
+	if d.Len() != 200 {
+		t.Fatalf("Expected len 200, got %d", d.Len())
+	}
+
+	count := 0
+	for !d.IsEmpty() {
+		if count%2 == 0 {
+			d.PopFront()
+		} else {
+			d.PopBack()
+		}
+		count++
+	}
+	if count != 200 {
+		t.Errorf("Expected 200 pops, got %d", count)
+	}
+
+	d.PushBack(1)
+	d.PushFront(0)
+	if v, _ := d.PeekFront(); v != 0 {
+		t.Errorf("PeekFront() = %d", v)
+	}
+	if v, _ := d.PeekBack(); v != 1 {
+		t.Errorf("PeekBack() = %d", v)
+	}
+	if d.At(-1) != 1 || !reflect.DeepEqual(d.ToSlice(), []int{0, 1}) {
+		t.Error("Unexpected elements")
+	}
+	d.Clear()
+	if !d.IsEmpty() {
+		t.Error("Deque should be empty after Clear")
+	}
+}
@@ -0,0 +1,568 @@
+package queueUtil
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tomatosky/jo-util/logger"
+)
+
+// SyncPolicy 磁盘队列的刷盘策略
+type SyncPolicy int
+
+const (
+	SyncAlways   SyncPolicy = iota // 每次入队出队后立即刷盘，最安全也最慢
+	SyncInterval                   // 后台按固定间隔刷盘，崩溃时可能丢失最近一个间隔内的数据
+	SyncNone                       // 不主动刷盘，由操作系统决定，仅在 Sync 和 Close 时刷盘
+)
+
+const (
+	defaultSegmentSize  = 64 << 20    // 默认段文件大小
+	defaultSyncInterval = time.Second // 默认刷盘间隔
+	recordHeaderSize    = 8           // 记录头：4字节长度 + 4字节CRC32校验和
+	offsetRecordSize    = 20          // 消费位置：8字节段号 + 8字节段内偏移 + 4字节校验和
+	segmentExt          = ".seg"
+	offsetFileName      = "consumer.offset"
+)
+
+var (
+	// ErrQueueEmpty 队列为空
+	ErrQueueEmpty = errors.New("queue is empty")
+	// ErrCorrupted 磁盘上的记录已损坏
+	ErrCorrupted = errors.New("disk queue record is corrupted")
+)
+
+// DiskQueueOpt DiskQueue 的配置
+type DiskQueueOpt struct {
+	Dir          string        // 数据目录，不存在时自动创建，同一目录只能被一个队列使用
+	SegmentSize  int64         // 段文件大小，超过后写入新的段，默认64MB
+	Sync         SyncPolicy    // 刷盘策略，默认 SyncAlways
+	SyncInterval time.Duration // SyncInterval 策略的刷盘间隔，默认1秒
+}
+
+// DiskQueue 并发安全的持久化先进先出队列，元素以JSON编码追加写入段文件
+// 每条记录带有CRC32校验和，重新打开时从上次的消费位置继续，并截断崩溃时未写完的记录，出队时跳过校验失败的记录
+// 消费完的段文件会被删除；除 SyncAlways 外，崩溃后可能重复消费少量已出队的元素
+type DiskQueue[T any] struct {
+	mu          sync.Mutex
+	dir         string
+	segmentSize int64
+	sync        SyncPolicy
+	count       int // 未消费的记录数量
+
+	writeSeg  uint64 // 写入中的段号
+	writeFile *os.File
+	writePos  int64
+
+	readSeg  uint64 // 读取中的段号
+	readFile *os.File
+	readPos  int64
+	readSize int64 // 读取中的段的大小，仅在 readSeg < writeSeg 时有效
+
+	offsetFile *os.File
+	corrupted  int  // 出队时跳过的损坏记录数量
+	dirty      bool // 是否有未刷盘的数据
+	closed     bool
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// NewDiskQueue 打开或创建磁盘队列，目录中已有数据时从上次的消费位置恢复
+func NewDiskQueue[T any](opt *DiskQueueOpt) (*DiskQueue[T], error) {
+	if opt == nil || opt.Dir == "" {
+		return nil, errors.New("disk queue dir cannot be empty")
+	}
+	if err := os.MkdirAll(opt.Dir, 0755); err != nil {
+		return nil, err
+	}
+	q := &DiskQueue[T]{
+		dir:         opt.Dir,
+		segmentSize: opt.SegmentSize,
+		sync:        opt.Sync,
+	}
+	if q.segmentSize <= 0 {
+		q.segmentSize = defaultSegmentSize
+	}
+	if err := q.recover(); err != nil {
+		q.closeFiles()
+		return nil, err
+	}
+	if q.sync == SyncInterval {
+		interval := opt.SyncInterval
+		if interval <= 0 {
+			interval = defaultSyncInterval
+		}
+		q.stop = make(chan struct{})
+		q.done = make(chan struct{})
+		go q.runSyncer(interval)
+	}
+	return q, nil
+}
+
+// Enqueue 入队
+func (q *DiskQueue[T]) Enqueue(item T) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > math.MaxUint32 {
+		return fmt.Errorf("record too large: %d bytes", len(data))
+	}
+	record := make([]byte, recordHeaderSize+len(data))
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	binary.BigEndian.PutUint32(record[4:], crc32.ChecksumIEEE(data))
+	copy(record[recordHeaderSize:], data)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if q.writePos > 0 && q.writePos+int64(len(record)) > q.segmentSize {
+		if err := q.rollSegment(); err != nil {
+			return err
+		}
+	}
+	if _, err := q.writeFile.Write(record); err != nil {
+		// 丢弃写了一半的记录
+		_ = q.writeFile.Truncate(q.writePos)
+		return err
+	}
+	q.writePos += int64(len(record))
+	q.count++
+	return q.afterWrite(q.writeFile)
+}
+
+// Dequeue 出队，队列为空时返回 ErrQueueEmpty
+// 解码成功后才保存消费位置，解码失败时返回错误且元素仍在队首，可以调用 Skip 丢弃；CRC 校验失败的记录会被自动跳过
+func (q *DiskQueue[T]) Dequeue() (T, error) {
+	var item T
+	if err := q.pop(func(data []byte) error { return json.Unmarshal(data, &item) }); err != nil {
+		var zero T
+		return zero, err
+	}
+	return item, nil
+}
+
+// Skip 不解码直接丢弃队首的元素，用于跳过无法解码的元素，队列为空时返回 ErrQueueEmpty
+func (q *DiskQueue[T]) Skip() error {
+	return q.pop(nil)
+}
+
+// Corrupted 打开队列后出队时跳过的损坏记录数量
+func (q *DiskQueue[T]) Corrupted() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.corrupted
+}
+
+// Len 未消费的元素数量
+func (q *DiskQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.count
+}
+
+// IsEmpty 是否为空
+func (q *DiskQueue[T]) IsEmpty() bool {
+	return q.Len() == 0
+}
+
+// Sync 立即将数据和消费位置刷盘
+func (q *DiskQueue[T]) Sync() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	return q.syncFiles()
+}
+
+// Close 刷盘并关闭队列，可重复调用
+func (q *DiskQueue[T]) Close() error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	q.mu.Unlock()
+
+	if q.stop != nil {
+		close(q.stop)
+		<-q.done
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	err := q.syncFiles()
+	q.closeFiles()
+	return err
+}
+
+// recover 加载消费位置，删除已消费的段，并截断未写完的记录
+func (q *DiskQueue[T]) recover() error {
+	segs, err := q.listSegments()
+	if err != nil {
+		return err
+	}
+	q.offsetFile, err = os.OpenFile(filepath.Join(q.dir, offsetFileName), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	readSeg, readPos, ok := q.loadOffset()
+	if len(segs) == 0 {
+		if !ok {
+			readSeg = 0
+		}
+		f, err := os.OpenFile(q.segmentPath(readSeg), os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		_ = f.Close()
+		segs = []uint64{readSeg}
+		readPos = 0
+	} else if !ok || readSeg < segs[0] || readSeg > segs[len(segs)-1] {
+		readSeg, readPos = segs[0], 0
+	}
+
+	q.count = 0
+	for _, seg := range segs {
+		if seg < readSeg {
+			if err := os.Remove(q.segmentPath(seg)); err != nil {
+				return err
+			}
+			continue
+		}
+		from := int64(0)
+		if seg == readSeg {
+			from = readPos
+		}
+		n, end, err := q.scanSegment(seg, from, seg == segs[len(segs)-1])
+		if err != nil {
+			return err
+		}
+		q.count += n
+		if seg == readSeg {
+			q.readSize = end
+			readPos = min(readPos, end)
+		}
+		q.writeSeg, q.writePos = seg, end
+	}
+
+	q.readSeg, q.readPos = readSeg, readPos
+	if q.writeFile, err = os.OpenFile(q.segmentPath(q.writeSeg), os.O_WRONLY|os.O_APPEND, 0644); err != nil {
+		return err
+	}
+	if q.readFile, err = os.Open(q.segmentPath(q.readSeg)); err != nil {
+		return err
+	}
+	return q.saveOffset(q.readSeg, q.readPos)
+}
+
+// scanSegment 校验段中的记录，返回 from 之后的记录数量和段中数据的末尾
+// 最后一段从第一条无效的记录处截断崩溃时未写完的数据；已写满的段不截断，校验失败的记录和无法解析的尾部数据各按一条记录计数，出队时跳过
+func (q *DiskQueue[T]) scanSegment(seg uint64, from int64, last bool) (int, int64, error) {
+	path := q.segmentPath(seg)
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	size := info.Size()
+
+	r := bufio.NewReader(f)
+	header := make([]byte, recordHeaderSize)
+	count := 0
+	pos := int64(0)
+	for pos+recordHeaderSize <= size {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return 0, 0, err
+		}
+		length := int64(binary.BigEndian.Uint32(header))
+		if pos+recordHeaderSize+length > size {
+			break
+		}
+		h := crc32.NewIEEE()
+		if _, err := io.CopyN(h, r, length); err != nil {
+			return 0, 0, err
+		}
+		if h.Sum32() != binary.BigEndian.Uint32(header[4:]) {
+			if last {
+				break
+			}
+			logger.Log.Error(fmt.Sprintf("disk queue segment %s has a corrupted record at offset %d", path, pos))
+		}
+		if pos >= from {
+			count++
+		}
+		pos += recordHeaderSize + length
+	}
+	if pos < size && !last {
+		logger.Log.Error(fmt.Sprintf("disk queue segment %s has %d bytes of corrupted data at offset %d", path, size-pos, pos))
+		if pos >= from {
+			count++
+		}
+		return count, size, nil
+	}
+	if pos < size {
+		logger.Log.Warn(fmt.Sprintf("disk queue segment %s truncated from %d to %d bytes", path, size, pos))
+		if err := f.Truncate(pos); err != nil {
+			return 0, 0, err
+		}
+	}
+	return count, pos, nil
+}
+
+// pop 读取队首的记录交给 decode 处理，处理成功后才保存消费位置，decode 为nil时直接丢弃
+func (q *DiskQueue[T]) pop(decode func(data []byte) error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	for q.count > 0 {
+		if err := q.advanceReader(); err != nil {
+			return err
+		}
+		if q.readSeg == q.writeSeg && q.readPos >= q.writePos {
+			// 跳过的损坏数据中包含多条记录，剩余数量已不准确
+			q.count = 0
+			break
+		}
+		data, next, err := q.readRecord()
+		if errors.Is(err, ErrCorrupted) {
+			logger.Log.Error(fmt.Sprintf("disk queue skip %d bytes: %v", next-q.readPos, err))
+			if err := q.consume(next); err != nil {
+				return err
+			}
+			q.corrupted++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if decode != nil {
+			if err := decode(data); err != nil {
+				return fmt.Errorf("disk queue decode segment %d offset %d: %w", q.readSeg, q.readPos, err)
+			}
+		}
+		return q.consume(next)
+	}
+	return ErrQueueEmpty
+}
+
+// readRecord 读取当前位置的记录和下一条记录的位置，调用时需持有锁
+// 记录损坏时返回 ErrCorrupted，长度损坏无法定位下一条记录时跳到段的末尾
+func (q *DiskQueue[T]) readRecord() ([]byte, int64, error) {
+	end := q.readSize
+	if q.readSeg == q.writeSeg {
+		end = q.writePos
+	}
+	if q.readPos+recordHeaderSize > end {
+		return nil, end, fmt.Errorf("%w: segment %d offset %d has an incomplete header", ErrCorrupted, q.readSeg, q.readPos)
+	}
+	header := make([]byte, recordHeaderSize)
+	if _, err := q.readFile.ReadAt(header, q.readPos); err != nil {
+		return nil, 0, err
+	}
+	next := q.readPos + recordHeaderSize + int64(binary.BigEndian.Uint32(header))
+	if next > end {
+		return nil, end, fmt.Errorf("%w: segment %d offset %d exceeds the segment", ErrCorrupted, q.readSeg, q.readPos)
+	}
+	data := make([]byte, next-q.readPos-recordHeaderSize)
+	if _, err := q.readFile.ReadAt(data, q.readPos+recordHeaderSize); err != nil {
+		return nil, 0, err
+	}
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, next, fmt.Errorf("%w: segment %d offset %d", ErrCorrupted, q.readSeg, q.readPos)
+	}
+	return data, next, nil
+}
+
+// consume 先持久化消费位置再更新内存状态，保存失败时记录仍可再次出队，调用时需持有锁
+func (q *DiskQueue[T]) consume(next int64) error {
+	if err := q.saveOffset(q.readSeg, next); err != nil {
+		return err
+	}
+	q.readPos = next
+	q.count--
+	if err := q.advanceReader(); err != nil {
+		logger.Log.Error(fmt.Sprintf("disk queue advance reader error: %v", err))
+	}
+	return nil
+}
+
+// listSegments 按段号升序列出所有段
+func (q *DiskQueue[T]) listSegments() ([]uint64, error) {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, err
+	}
+	segs := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		seg, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		segs = append(segs, seg)
+	}
+	slices.Sort(segs)
+	return segs, nil
+}
+
+func (q *DiskQueue[T]) segmentPath(seg uint64) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%020d%s", seg, segmentExt))
+}
+
+// rollSegment 关闭当前写入的段并创建新段，调用时需持有锁
+func (q *DiskQueue[T]) rollSegment() error {
+	if err := q.writeFile.Sync(); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(q.segmentPath(q.writeSeg+1), os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	_ = q.writeFile.Close()
+	if q.readSeg == q.writeSeg {
+		q.readSize = q.writePos
+	}
+	q.writeFile = f
+	q.writeSeg++
+	q.writePos = 0
+	// 旧段可能已经消费完，及时删除
+	return q.advanceReader()
+}
+
+// advanceReader 当前段读完后切换到下一段并删除已消费的段，调用时需持有锁
+func (q *DiskQueue[T]) advanceReader() error {
+	for q.readSeg < q.writeSeg && q.readPos >= q.readSize {
+		next := q.readSeg + 1
+		f, err := os.Open(q.segmentPath(next))
+		if err != nil {
+			return err
+		}
+		size := int64(0)
+		if next < q.writeSeg {
+			info, err := f.Stat()
+			if err != nil {
+				_ = f.Close()
+				return err
+			}
+			size = info.Size()
+		}
+		if err := q.saveOffset(next, 0); err != nil {
+			_ = f.Close()
+			return err
+		}
+		_ = q.readFile.Close()
+		if err := os.Remove(q.segmentPath(q.readSeg)); err != nil {
+			logger.Log.Error(fmt.Sprintf("disk queue remove segment error: %v", err))
+		}
+		q.readFile = f
+		q.readSeg = next
+		q.readPos = 0
+		q.readSize = size
+	}
+	return nil
+}
+
+// loadOffset 读取消费位置，文件不存在或已损坏时返回false
+func (q *DiskQueue[T]) loadOffset() (uint64, int64, bool) {
+	buf := make([]byte, offsetRecordSize)
+	if _, err := q.offsetFile.ReadAt(buf, 0); err != nil {
+		return 0, 0, false
+	}
+	if crc32.ChecksumIEEE(buf[:16]) != binary.BigEndian.Uint32(buf[16:]) {
+		logger.Log.Warn(fmt.Sprintf("disk queue offset file in %s is corrupted, consuming from the first segment", q.dir))
+		return 0, 0, false
+	}
+	return binary.BigEndian.Uint64(buf), int64(binary.BigEndian.Uint64(buf[8:])), true
+}
+
+// saveOffset 保存消费位置，调用时需持有锁
+func (q *DiskQueue[T]) saveOffset(seg uint64, pos int64) error {
+	buf := make([]byte, offsetRecordSize)
+	binary.BigEndian.PutUint64(buf, seg)
+	binary.BigEndian.PutUint64(buf[8:], uint64(pos))
+	binary.BigEndian.PutUint32(buf[16:], crc32.ChecksumIEEE(buf[:16]))
+	if _, err := q.offsetFile.WriteAt(buf, 0); err != nil {
+		return err
+	}
+	return q.afterWrite(q.offsetFile)
+}
+
+// afterWrite 按刷盘策略处理写入，调用时需持有锁
+func (q *DiskQueue[T]) afterWrite(f *os.File) error {
+	switch q.sync {
+	case SyncAlways:
+		return f.Sync()
+	case SyncInterval:
+		q.dirty = true
+	}
+	return nil
+}
+
+// syncFiles 刷盘，调用时需持有锁
+func (q *DiskQueue[T]) syncFiles() error {
+	if err := q.writeFile.Sync(); err != nil {
+		return err
+	}
+	if err := q.offsetFile.Sync(); err != nil {
+		return err
+	}
+	q.dirty = false
+	return nil
+}
+
+func (q *DiskQueue[T]) closeFiles() {
+	for _, f := range []*os.File{q.writeFile, q.readFile, q.offsetFile} {
+		if f != nil {
+			_ = f.Close()
+		}
+	}
+}
+
+// runSyncer 后台定时刷盘
+func (q *DiskQueue[T]) runSyncer(interval time.Duration) {
+	defer close(q.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-ticker.C:
+			q.mu.Lock()
+			if !q.closed && q.dirty {
+				if err := q.syncFiles(); err != nil {
+					logger.Log.Error(fmt.Sprintf("disk queue sync error: %v", err))
+				}
+			}
+			q.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package queueUtil
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testEvent struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func openDiskQueue(t *testing.T, opt *DiskQueueOpt) *DiskQueue[testEvent] {
+	t.Helper()
+	q, err := NewDiskQueue[testEvent](opt)
+	if err != nil {
+		t.Fatalf("NewDiskQueue error: %v", err)
+	}
+	return q
+}
+
+func countSegments(t *testing.T, dir string) int {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(files)
+}
+
+// TestDiskQueueBasic 测试入队出队顺序
+func TestDiskQueueBasic(t *testing.T) {
+	q := openDiskQueue(t, &DiskQueueOpt{Dir: t.TempDir()})
+	defer q.Close()
+
+	if _, err := q.Dequeue(); !errors.Is(err, ErrQueueEmpty) {
+		t.Errorf("Expected ErrQueueEmpty, got %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := q.Enqueue(testEvent{Id: i, Name: "event"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if q.Len() != 10 || q.IsEmpty() {
+		t.Errorf("Expected len 10, got %d", q.Len())
+	}
+	for i := 0; i < 10; i++ {
+		e, err := q.Dequeue()
+		if err != nil || e.Id != i || e.Name != "event" {
+			t.Fatalf("Dequeue() = %v, %v, want id %d", e, err, i)
+		}
+	}
+	if !q.IsEmpty() {
+		t.Error("Queue should be empty")
+	}
+
+	if _, err := NewDiskQueue[int](nil); err == nil {
+		t.Error("Expected error for empty dir")
+	}
+}
+
+// TestDiskQueueReopen 测试关闭后重新打开从消费位置继续
+func TestDiskQueueReopen(t *testing.T) {
+	dir := t.TempDir()
+	q := openDiskQueue(t, &DiskQueueOpt{Dir: dir, Sync: SyncNone})
+	for i := 0; i < 5; i++ {
+		_ = q.Enqueue(testEvent{Id: i})
+	}
+	_, _ = q.Dequeue()
+	_, _ = q.Dequeue()
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Enqueue(testEvent{}); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed, got %v", err)
+	}
+	if err := q.Close(); err != nil {
+		t.Errorf("Close should be idempotent, got %v", err)
+	}
+
+	q = openDiskQueue(t, &DiskQueueOpt{Dir: dir})
+	defer q.Close()
+	if q.Len() != 3 {
+		t.Fatalf("Expected len 3 after reopen, got %d", q.Len())
+	}
+	_ = q.Enqueue(testEvent{Id: 5})
+	for want := 2; want <= 5; want++ {
+		e, err := q.Dequeue()
+		if err != nil || e.Id != want {
+			t.Fatalf("Dequeue() = %v, %v, want id %d", e, err, want)
+		}
+	}
+}
+
+// TestDiskQueueSegments 测试段切换和删除已消费的段
+func TestDiskQueueSegments(t *testing.T) {
+	dir := t.TempDir()
+	q := openDiskQueue(t, &DiskQueueOpt{Dir: dir, SegmentSize: 100})
+	defer q.Close()
+
+	for i := 0; i < 20; i++ {
+		if err := q.Enqueue(testEvent{Id: i, Name: "segment"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	total := countSegments(t, dir)
+	if total < 5 {
+		t.Fatalf("Expected multiple segments, got %d", total)
+	}
+	for i := 0; i < 10; i++ {
+		if e, err := q.Dequeue(); err != nil || e.Id != i {
+			t.Fatalf("Dequeue() = %v, %v, want id %d", e, err, i)
+		}
+	}
+	if n := countSegments(t, dir); n >= total {
+		t.Errorf("Expected consumed segments to be removed, %d of %d left", n, total)
+	}
+
+	// 重新打开后跨段继续消费
+	_ = q.Close()
+	q = openDiskQueue(t, &DiskQueueOpt{Dir: dir, SegmentSize: 100})
+	for i := 10; i < 20; i++ {
+		if e, err := q.Dequeue(); err != nil || e.Id != i {
+			t.Fatalf("Dequeue() = %v, %v, want id %d", e, err, i)
+		}
+	}
+	if n := countSegments(t, dir); n != 1 {
+		t.Errorf("Expected only the write segment left, got %d", n)
+	}
+}
+
+// TestDiskQueueCrashRecovery 测试崩溃后截断未写完的记录
+func TestDiskQueueCrashRecovery(t *testing.T) {
+	dir := t.TempDir()
+	// 不调用 Close 模拟崩溃
+	q := openDiskQueue(t, &DiskQueueOpt{Dir: dir, Sync: SyncNone})
+	for i := 0; i < 3; i++ {
+		_ = q.Enqueue(testEvent{Id: i})
+	}
+	_, _ = q.Dequeue()
+
+	// 追加一条写了一半的记录
+	path := q.segmentPath(q.writeSeg)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.Write([]byte{0, 0, 0, 50, 1, 2, 3, 4, '{', '"'})
+	_ = f.Close()
+	before, _ := os.Stat(path)
+
+	q2 := openDiskQueue(t, &DiskQueueOpt{Dir: dir})
+	defer q2.Close()
+	if q2.Len() != 2 {
+		t.Fatalf("Expected len 2 after recovery, got %d", q2.Len())
+	}
+	after, _ := os.Stat(path)
+	if after.Size() != before.Size()-10 {
+		t.Errorf("Expected torn record to be truncated, size %d -> %d", before.Size(), after.Size())
+	}
+	_ = q2.Enqueue(testEvent{Id: 3})
+	for want := 1; want <= 3; want++ {
+		if e, err := q2.Dequeue(); err != nil || e.Id != want {
+			t.Fatalf("Dequeue() = %v, %v, want id %d", e, err, want)
+		}
+	}
+}
+
+// TestDiskQueueCorruptedOffset 测试消费位置损坏时从头消费
+func TestDiskQueueCorruptedOffset(t *testing.T) {
+	dir := t.TempDir()
+	q := openDiskQueue(t, &DiskQueueOpt{Dir: dir})
+	_ = q.Enqueue(testEvent{Id: 1})
+	_ = q.Enqueue(testEvent{Id: 2})
+	_, _ = q.Dequeue()
+	_ = q.Close()
+
+	if err := os.WriteFile(filepath.Join(dir, offsetFileName), []byte("broken offset file!!"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	q = openDiskQueue(t, &DiskQueueOpt{Dir: dir})
+	defer q.Close()
+	if q.Len() != 2 {
+		t.Errorf("Expected redelivery of all records, got len %d", q.Len())
+	}
+	if e, _ := q.Dequeue(); e.Id != 1 {
+		t.Errorf("Expected id 1, got %d", e.Id)
+	}
+}
+
+// corruptSegment 修改段中第 index 条记录，header 为true时改写长度，否则改写数据
+func corruptSegment(t *testing.T, path string, index int, header bool) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pos := 0
+	for i := 0; i < index; i++ {
+		pos += recordHeaderSize + int(binary.BigEndian.Uint32(data[pos:]))
+	}
+	if header {
+		binary.BigEndian.PutUint32(data[pos:], math.MaxUint32)
+	} else {
+		data[pos+recordHeaderSize] ^= 0xff
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestDiskQueueCorruptedRecord 测试跳过损坏的记录，之后的记录仍可读取
+func TestDiskQueueCorruptedRecord(t *testing.T) {
+	t.Run("Runtime", func(t *testing.T) {
+		q := openDiskQueue(t, &DiskQueueOpt{Dir: t.TempDir()})
+		defer q.Close()
+		for i := 0; i < 3; i++ {
+			_ = q.Enqueue(testEvent{Id: i})
+		}
+		corruptSegment(t, q.segmentPath(q.writeSeg), 1, false)
+		for _, want := range []int{0, 2} {
+			if e, err := q.Dequeue(); err != nil || e.Id != want {
+				t.Fatalf("Dequeue() = %v, %v, want id %d", e, err, want)
+			}
+		}
+		if q.Corrupted() != 1 || q.Len() != 0 {
+			t.Errorf("Expected 1 corrupted record and empty queue, got %d and len %d", q.Corrupted(), q.Len())
+		}
+		if _, err := q.Dequeue(); !errors.Is(err, ErrQueueEmpty) {
+			t.Errorf("Expected ErrQueueEmpty, got %v", err)
+		}
+	})
+
+	t.Run("SealedSegment", func(t *testing.T) {
+		dir := t.TempDir()
+		q := openDiskQueue(t, &DiskQueueOpt{Dir: dir, SegmentSize: 100})
+		for i := 0; i < 12; i++ {
+			_ = q.Enqueue(testEvent{Id: i, Name: "segment"})
+		}
+		first := q.readSeg
+		_ = q.Close()
+
+		// 第一段中间的记录校验失败，第二段的长度损坏
+		path := filepath.Join(dir, filepath.Base(q.segmentPath(first)))
+		before, _ := os.Stat(path)
+		corruptSegment(t, path, 1, false)
+		corruptSegment(t, q.segmentPath(first+1), 0, true)
+
+		q = openDiskQueue(t, &DiskQueueOpt{Dir: dir, SegmentSize: 100})
+		defer q.Close()
+		if after, _ := os.Stat(path); after.Size() != before.Size() {
+			t.Errorf("Sealed segment should not be truncated, size %d -> %d", before.Size(), after.Size())
+		}
+		var got []int
+		for !q.IsEmpty() {
+			e, err := q.Dequeue()
+			if errors.Is(err, ErrQueueEmpty) {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, e.Id)
+		}
+		// 每段三条记录，第二段整体被跳过
+		want := []int{0, 2, 6, 7, 8, 9, 10, 11}
+		if !slices.Equal(got, want) {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+		if q.Corrupted() != 2 {
+			t.Errorf("Expected 2 corrupted records, got %d", q.Corrupted())
+		}
+	})
+
+	t.Run("DecodeError", func(t *testing.T) {
+		dir := t.TempDir()
+		raw, err := NewDiskQueue[any](&DiskQueueOpt{Dir: dir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = raw.Enqueue("not an event")
+		_ = raw.Enqueue(testEvent{Id: 2})
+		_ = raw.Close()
+
+		q := openDiskQueue(t, &DiskQueueOpt{Dir: dir})
+		defer q.Close()
+		// 解码失败时不消费，元素仍在队首
+		for i := 0; i < 2; i++ {
+			if _, err := q.Dequeue(); err == nil {
+				t.Fatal("Expected decode error")
+			}
+		}
+		if q.Len() != 2 {
+			t.Fatalf("Expected len 2 after decode error, got %d", q.Len())
+		}
+		if err := q.Skip(); err != nil {
+			t.Fatal(err)
+		}
+		if e, err := q.Dequeue(); err != nil || e.Id != 2 {
+			t.Fatalf("Dequeue() = %v, %v, want id 2", e, err)
+		}
+		if err := q.Skip(); !errors.Is(err, ErrQueueEmpty) {
+			t.Errorf("Expected ErrQueueEmpty, got %v", err)
+		}
+	})
+}
+
+// TestDiskQueueConcurrent 测试并发入队出队和定时刷盘
+func TestDiskQueueConcurrent(t *testing.T) {
+	q := openDiskQueue(t, &DiskQueueOpt{
+		Dir:          t.TempDir(),
+		SegmentSize:  1024,
+		Sync:         SyncInterval,
+		SyncInterval: 10 * time.Millisecond,
+	})
+	defer q.Close()
+
+	const producers, perProducer = 4, 100
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				if err := q.Enqueue(testEvent{Id: p*perProducer + i}); err != nil {
+					t.Error(err)
+				}
+			}
+		}(p)
+	}
+
+	seen := make(map[int]bool)
+	for len(seen) < producers*perProducer {
+		e, err := q.Dequeue()
+		if errors.Is(err, ErrQueueEmpty) {
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[e.Id] {
+			t.Fatalf("Duplicate id %d", e.Id)
+		}
+		seen[e.Id] = true
+	}
+	wg.Wait()
+	time.Sleep(30 * time.Millisecond)
+	if err := q.Sync(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,253 @@
+package queueUtil
+
+import "sync"
+
+// Handle 优先队列中元素的句柄，用于更新或移除元素
+type Handle[T any] struct {
+	value T
+	index int // 在堆中的下标，不在队列中时为-1
+	owner any // 所属队列
+}
+
+// Value 返回句柄对应的元素，在 SafePriorityQueue 中与 Update 并发调用时需自行同步
+func (h *Handle[T]) Value() T {
+	return h.value
+}
+
+// PriorityQueue 基于二叉堆的优先队列，非并发安全
+// less(a, b) 返回true时 a 先出队
+type PriorityQueue[T any] struct {
+	items []*Handle[T]
+	less  func(a, b T) bool
+}
+
+// NewPriorityQueue 创建优先队列
+// less: 比较函数，less(a, b) 为true时 a 的优先级更高
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{
+		items: make([]*Handle[T], 0),
+		less:  less,
+	}
+}
+
+// Push 入队，返回元素的句柄
+func (q *PriorityQueue[T]) Push(item T) *Handle[T] {
+	h := &Handle[T]{value: item, index: len(q.items), owner: q}
+	q.items = append(q.items, h)
+	q.up(h.index)
+	return h
+}
+
+// Pop 取出优先级最高的元素
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.removeAt(0).value, true
+}
+
+// Peek 查看优先级最高的元素，不出队
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.items[0].value, true
+}
+
+// Update 更新句柄对应的元素并调整位置，句柄不属于该队列或已出队时返回false
+func (q *PriorityQueue[T]) Update(h *Handle[T], item T) bool {
+	if !q.contains(h, q) {
+		return false
+	}
+	q.fix(h, item)
+	return true
+}
+
+// Remove 移除句柄对应的元素，句柄不属于该队列或已出队时返回false
+func (q *PriorityQueue[T]) Remove(h *Handle[T]) bool {
+	if !q.contains(h, q) {
+		return false
+	}
+	q.removeAt(h.index)
+	return true
+}
+
+// Len 元素数量
+func (q *PriorityQueue[T]) Len() int {
+	return len(q.items)
+}
+
+// IsEmpty 是否为空
+func (q *PriorityQueue[T]) IsEmpty() bool {
+	return len(q.items) == 0
+}
+
+// Clear 清空队列，已有的句柄全部失效
+func (q *PriorityQueue[T]) Clear() {
+	for _, h := range q.items {
+		h.index = -1
+	}
+	q.items = make([]*Handle[T], 0)
+}
+
+// ToSlice 返回所有元素，不保证顺序
+func (q *PriorityQueue[T]) ToSlice() []T {
+	result := make([]T, len(q.items))
+	for i, h := range q.items {
+		result[i] = h.value
+	}
+	return result
+}
+
+// contains 句柄是否属于 owner 且仍在队列中
+func (q *PriorityQueue[T]) contains(h *Handle[T], owner any) bool {
+	return h != nil && h.owner == owner && h.index >= 0 && h.index < len(q.items) && q.items[h.index] == h
+}
+
+// fix 更新元素后调整位置
+func (q *PriorityQueue[T]) fix(h *Handle[T], item T) {
+	h.value = item
+	if !q.down(h.index) {
+		q.up(h.index)
+	}
+}
+
+// removeAt 移除指定下标的元素
+func (q *PriorityQueue[T]) removeAt(i int) *Handle[T] {
+	h := q.items[i]
+	last := len(q.items) - 1
+	if i != last {
+		q.swap(i, last)
+	}
+	q.items[last] = nil
+	q.items = q.items[:last]
+	if i != last {
+		if !q.down(i) {
+			q.up(i)
+		}
+	}
+	h.index = -1
+	return h
+}
+
+func (q *PriorityQueue[T]) swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
+
+func (q *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.items[i].value, q.items[parent].value) {
+			break
+		}
+		q.swap(i, parent)
+		i = parent
+	}
+}
+
+// down 向下调整，返回是否发生了移动
+func (q *PriorityQueue[T]) down(i int) bool {
+	start := i
+	n := len(q.items)
+	for {
+		child := 2*i + 1
+		if child >= n {
+			break
+		}
+		if right := child + 1; right < n && q.less(q.items[right].value, q.items[child].value) {
+			child = right
+		}
+		if !q.less(q.items[child].value, q.items[i].value) {
+			break
+		}
+		q.swap(i, child)
+		i = child
+	}
+	return i > start
+}
+
+// SafePriorityQueue 并发安全的优先队列
+type SafePriorityQueue[T any] struct {
+	pq    *PriorityQueue[T]
+	mutex sync.Mutex
+}
+
+// NewSafePriorityQueue 创建并发安全的优先队列
+func NewSafePriorityQueue[T any](less func(a, b T) bool) *SafePriorityQueue[T] {
+	return &SafePriorityQueue[T]{pq: NewPriorityQueue(less)}
+}
+
+// Push 入队，返回元素的句柄
+func (q *SafePriorityQueue[T]) Push(item T) *Handle[T] {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	h := q.pq.Push(item)
+	h.owner = q
+	return h
+}
+
+// Pop 取出优先级最高的元素
+func (q *SafePriorityQueue[T]) Pop() (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.pq.Pop()
+}
+
+// Peek 查看优先级最高的元素，不出队
+func (q *SafePriorityQueue[T]) Peek() (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.pq.Peek()
+}
+
+// Update 更新句柄对应的元素并调整位置，句柄不属于该队列或已出队时返回false
+func (q *SafePriorityQueue[T]) Update(h *Handle[T], item T) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if !q.pq.contains(h, q) {
+		return false
+	}
+	q.pq.fix(h, item)
+	return true
+}
+
+// Remove 移除句柄对应的元素，句柄不属于该队列或已出队时返回false
+func (q *SafePriorityQueue[T]) Remove(h *Handle[T]) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if !q.pq.contains(h, q) {
+		return false
+	}
+	q.pq.removeAt(h.index)
+	return true
+}
+
+// Len 元素数量
+func (q *SafePriorityQueue[T]) Len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.pq.Len()
+}
+
+// IsEmpty 是否为空
+func (q *SafePriorityQueue[T]) IsEmpty() bool {
+	return q.Len() == 0
+}
+
+// Clear 清空队列，已有的句柄全部失效
+func (q *SafePriorityQueue[T]) Clear() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.pq.Clear()
+}
+
+// ToSlice 返回所有元素，不保证顺序
+func (q *SafePriorityQueue[T]) ToSlice() []T {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.pq.ToSlice()
+}
@@ -0,0 +1,168 @@
+package queueUtil
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+// TestPriorityQueueOrder 测试出队顺序
+func TestPriorityQueueOrder(t *testing.T) {
+	q := NewPriorityQueue(intLess)
+	if _, ok := q.Pop(); ok {
+		t.Error("Pop from empty queue should fail")
+	}
+
+	r := rand.New(rand.NewSource(1))
+	values := make([]int, 200)
+	for i := range values {
+		values[i] = r.Intn(100)
+		q.Push(values[i])
+	}
+	sort.Ints(values)
+
+	if v, ok := q.Peek(); !ok || v != values[0] {
+		t.Errorf("Peek() = %v, want %v", v, values[0])
+	}
+	if q.Len() != 200 || len(q.ToSlice()) != 200 {
+		t.Errorf("Unexpected len %d", q.Len())
+	}
+	for i, want := range values {
+		v, ok := q.Pop()
+		if !ok || v != want {
+			t.Fatalf("Pop() #%d = %v, want %v", i, v, want)
+		}
+	}
+	if !q.IsEmpty() {
+		t.Error("Queue should be empty")
+	}
+}
+
+// TestPriorityQueueHandle 测试按句柄更新和移除
+func TestPriorityQueueHandle(t *testing.T) {
+	type task struct {
+		name     string
+		priority int
+	}
+	q := NewPriorityQueue(func(a, b task) bool { return a.priority < b.priority })
+	a := q.Push(task{"a", 5})
+	b := q.Push(task{"b", 3})
+	c := q.Push(task{"c", 7})
+
+	if a.Value().name != "a" {
+		t.Errorf("Unexpected handle value %v", a.Value())
+	}
+
+	// 提高 c 的优先级
+	if !q.Update(c, task{"c", 1}) {
+		t.Fatal("Update should succeed")
+	}
+	if v, _ := q.Peek(); v.name != "c" {
+		t.Errorf("Expected c at head, got %v", v)
+	}
+	// 降低 c 的优先级
+	q.Update(c, task{"c", 10})
+	if v, _ := q.Peek(); v.name != "b" {
+		t.Errorf("Expected b at head, got %v", v)
+	}
+
+	if !q.Remove(b) || q.Remove(b) {
+		t.Error("Remove should succeed only once")
+	}
+	if q.Update(b, task{"b", 0}) {
+		t.Error("Update of removed handle should fail")
+	}
+
+	other := NewPriorityQueue(func(a, b task) bool { return a.priority < b.priority })
+	if other.Remove(a) || other.Update(a, task{}) {
+		t.Error("Handle of another queue should be rejected")
+	}
+
+	if v, _ := q.Pop(); v.name != "a" {
+		t.Errorf("Expected a, got %v", v)
+	}
+	if v, _ := q.Pop(); v.name != "c" {
+		t.Errorf("Expected c, got %v", v)
+	}
+	if q.Remove(a) {
+		t.Error("Remove of popped handle should fail")
+	}
+
+	h := q.Push(task{"d", 1})
+	q.Clear()
+	if q.Len() != 0 || q.Remove(h) {
+		t.Error("Handles should be invalid after Clear")
+	}
+}
+
+// TestPriorityQueueRemoveRandom 测试随机移除后堆仍然有序
+func TestPriorityQueueRemoveRandom(t *testing.T) {
+	q := NewPriorityQueue(intLess)
+	r := rand.New(rand.NewSource(2))
+	handles := make([]*Handle[int], 100)
+	for i := range handles {
+		handles[i] = q.Push(r.Intn(1000))
+	}
+	var kept []int
+	for i, h := range handles {
+		if i%3 == 0 {
+			q.Remove(h)
+		} else {
+			kept = append(kept, h.Value())
+		}
+	}
+	sort.Ints(kept)
+	for _, want := range kept {
+		if v, _ := q.Pop(); v != want {
+			t.Fatalf("Pop() = %v, want %v", v, want)
+		}
+	}
+}
+
+// TestSafePriorityQueue 测试并发安全的优先队列
+func TestSafePriorityQueue(t *testing.T) {
+	q := NewSafePriorityQueue(intLess)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(v int) {
+			defer wg.Done()
+			h := q.Push(v)
+			if v%2 == 0 {
+				q.Remove(h)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if q.Len() != 50 || q.IsEmpty() {
+		t.Fatalf("Expected 50 elements, got %d", q.Len())
+	}
+	h := q.Push(1000)
+	if !q.Update(h, -1) {
+		t.Fatal("Update should succeed")
+	}
+	if v, _ := q.Peek(); v != -1 {
+		t.Errorf("Expected -1 at head, got %d", v)
+	}
+	if NewPriorityQueue(intLess).Remove(h) {
+		t.Error("Handle of another queue should be rejected")
+	}
+	q.Pop()
+	prev := -1
+	for !q.IsEmpty() {
+		v, _ := q.Pop()
+		if v < prev || v%2 == 0 {
+			t.Fatalf("Unexpected element %d after %d", v, prev)
+		}
+		prev = v
+	}
+	q.Push(1)
+	q.Clear()
+	if len(q.ToSlice()) != 0 {
+		t.Error("Queue should be empty after Clear")
+	}
+}
@@ -0,0 +1,189 @@
+package queueUtil
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/Tomatosky/jo-util/logger"
+)
+
+const cacheLineSize = 64
+
+// RingBuffer 有界无锁多生产者多消费者环形队列，基于 Dmitry Vyukov 的序号算法
+// 每个槽位带有序号，生产者和消费者通过 CAS 抢占位置，不使用互斥锁
+type RingBuffer[T any] struct {
+	_          [cacheLineSize]byte
+	enqueuePos atomic.Uint64
+	_          [cacheLineSize - 8]byte
+	dequeuePos atomic.Uint64
+	_          [cacheLineSize - 8]byte
+	mask       uint64
+	cells      []ringCell[T]
+}
+
+type ringCell[T any] struct {
+	seq   atomic.Uint64 // 等于位置时可写入，等于位置+1时可读取
+	value T
+}
+
+// NewRingBuffer 创建无锁环形队列
+// capacity: 容量，向上取整为2的幂
+func NewRingBuffer[T any](capacity int) *RingBuffer[T] {
+	if capacity <= 0 {
+		logger.Log.Error(fmt.Sprintf("%v", "capacity must be greater than 0"))
+		panic("capacity must be greater than 0")
+	}
+	size := 1
+	for size < capacity {
+		size <<= 1
+	}
+	rb := &RingBuffer[T]{
+		mask:  uint64(size - 1),
+		cells: make([]ringCell[T], size),
+	}
+	for i := range rb.cells {
+		rb.cells[i].seq.Store(uint64(i))
+	}
+	return rb
+}
+
+// TryEnqueue 入队，队列已满时立即返回false
+func (rb *RingBuffer[T]) TryEnqueue(item T) bool {
+	pos := rb.enqueuePos.Load()
+	for {
+		cell := &rb.cells[pos&rb.mask]
+		seq := cell.seq.Load()
+		switch diff := int64(seq - pos); {
+		case diff == 0:
+			if rb.enqueuePos.CompareAndSwap(pos, pos+1) {
+				cell.value = item
+				cell.seq.Store(pos + 1)
+				return true
+			}
+			pos = rb.enqueuePos.Load()
+		case diff < 0:
+			// 槽位上一轮的元素尚未被消费
+			return false
+		default:
+			// 其他生产者已抢占该位置
+			pos = rb.enqueuePos.Load()
+		}
+	}
+}
+
+// TryDequeue 出队，队列为空时立即返回false
+func (rb *RingBuffer[T]) TryDequeue() (T, bool) {
+	pos := rb.dequeuePos.Load()
+	for {
+		cell := &rb.cells[pos&rb.mask]
+		seq := cell.seq.Load()
+		switch diff := int64(seq - (pos + 1)); {
+		case diff == 0:
+			if rb.dequeuePos.CompareAndSwap(pos, pos+1) {
+				item := cell.value
+				var zero T
+				cell.value = zero
+				cell.seq.Store(pos + rb.mask + 1)
+				return item, true
+			}
+			pos = rb.dequeuePos.Load()
+		case diff < 0:
+			// 槽位尚未写入
+			var zero T
+			return zero, false
+		default:
+			// 其他消费者已抢占该位置
+			pos = rb.dequeuePos.Load()
+		}
+	}
+}
+
+// TryEnqueueBatch 批量入队，返回成功入队的数量，队列已满时返回0
+// 通过一次 CAS 抢占连续的位置，同一批的元素在队列中相邻，不会与其他生产者的元素交错，空间不足时只入队前面能放下的部分
+func (rb *RingBuffer[T]) TryEnqueueBatch(items []T) int {
+	if len(items) == 0 {
+		return 0
+	}
+	pos := rb.enqueuePos.Load()
+	for {
+		seq := rb.cells[pos&rb.mask].seq.Load()
+		if diff := int64(seq - pos); diff < 0 {
+			return 0
+		} else if diff > 0 {
+			pos = rb.enqueuePos.Load()
+			continue
+		}
+		// 统计从 pos 开始连续可写入的槽位
+		n := uint64(1)
+		for n < uint64(len(items)) && rb.cells[(pos+n)&rb.mask].seq.Load() == pos+n {
+			n++
+		}
+		if !rb.enqueuePos.CompareAndSwap(pos, pos+n) {
+			pos = rb.enqueuePos.Load()
+			continue
+		}
+		for i := uint64(0); i < n; i++ {
+			cell := &rb.cells[(pos+i)&rb.mask]
+			cell.value = items[i]
+			cell.seq.Store(pos + i + 1)
+		}
+		return int(n)
+	}
+}
+
+// TryDequeueBatch 批量出队最多 len(buf) 个元素写入 buf，返回出队的数量，队列为空时返回0
+// 通过一次 CAS 抢占连续的位置，出队的元素是队列中相邻的一段
+func (rb *RingBuffer[T]) TryDequeueBatch(buf []T) int {
+	if len(buf) == 0 {
+		return 0
+	}
+	pos := rb.dequeuePos.Load()
+	for {
+		seq := rb.cells[pos&rb.mask].seq.Load()
+		if diff := int64(seq - (pos + 1)); diff < 0 {
+			return 0
+		} else if diff > 0 {
+			pos = rb.dequeuePos.Load()
+			continue
+		}
+		// 统计从 pos 开始连续可读取的槽位
+		n := uint64(1)
+		for n < uint64(len(buf)) && rb.cells[(pos+n)&rb.mask].seq.Load() == pos+n+1 {
+			n++
+		}
+		if !rb.dequeuePos.CompareAndSwap(pos, pos+n) {
+			pos = rb.dequeuePos.Load()
+			continue
+		}
+		var zero T
+		for i := uint64(0); i < n; i++ {
+			cell := &rb.cells[(pos+i)&rb.mask]
+			buf[i] = cell.value
+			cell.value = zero
+			cell.seq.Store(pos + i + rb.mask + 1)
+		}
+		return int(n)
+	}
+}
+
+// Len 元素数量，并发修改时只是近似值
+func (rb *RingBuffer[T]) Len() int {
+	for {
+		tail := rb.enqueuePos.Load()
+		head := rb.dequeuePos.Load()
+		if tail == rb.enqueuePos.Load() {
+			n := int64(tail - head)
+			return int(min(max(n, 0), int64(rb.mask+1)))
+		}
+	}
+}
+
+// IsEmpty 是否为空，并发修改时只是近似值
+func (rb *RingBuffer[T]) IsEmpty() bool {
+	return rb.Len() == 0
+}
+
+// Cap 容量
+func (rb *RingBuffer[T]) Cap() int {
+	return int(rb.mask + 1)
+}
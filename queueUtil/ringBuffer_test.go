@@ -0,0 +1,252 @@
+package queueUtil
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestRingBufferBasic 测试入队出队
+func TestRingBufferBasic(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	if rb.Cap() != 4 {
+		t.Errorf("Expected cap 4, got %d", rb.Cap())
+	}
+	if _, ok := rb.TryDequeue(); ok {
+		t.Error("TryDequeue from empty buffer should fail")
+	}
+	for i := 0; i < 4; i++ {
+		if !rb.TryEnqueue(i) {
+			t.Fatalf("TryEnqueue(%d) failed", i)
+		}
+	}
+	if rb.TryEnqueue(4) {
+		t.Error("TryEnqueue to full buffer should fail")
+	}
+	if rb.Len() != 4 {
+		t.Errorf("Expected len 4, got %d", rb.Len())
+	}
+
+	// 多轮回绕
+	for round := 0; round < 10; round++ {
+		v, ok := rb.TryDequeue()
+		if !ok || v != round {
+			t.Fatalf("TryDequeue() = %d, %v, want %d", v, ok, round)
+		}
+		if !rb.TryEnqueue(round + 4) {
+			t.Fatalf("TryEnqueue(%d) failed", round+4)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Zero capacity should panic")
+		}
+	}()
+	NewRingBuffer[int](0)
+}
+
+// TestRingBufferBatch 测试批量入队出队
+func TestRingBufferBatch(t *testing.T) {
+	rb := NewRingBuffer[int](8)
+	if n := rb.TryEnqueueBatch([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}); n != 8 {
+		t.Errorf("Expected 8 enqueued, got %d", n)
+	}
+	buf := make([]int, 5)
+	if n := rb.TryDequeueBatch(buf); n != 5 || !reflect.DeepEqual(buf, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("TryDequeueBatch() = %d, %v", n, buf)
+	}
+	if n := rb.TryDequeueBatch(buf); n != 3 || !reflect.DeepEqual(buf[:n], []int{6, 7, 8}) {
+		t.Errorf("TryDequeueBatch() = %d, %v", n, buf[:n])
+	}
+	if !rb.IsEmpty() {
+		t.Error("Buffer should be empty")
+	}
+}
+
+// TestRingBufferBatchContiguous 测试并发批量操作抢占的是连续的位置
+func TestRingBufferBatchContiguous(t *testing.T) {
+	const producers, batches, size = 8, 100, 4
+	rb := NewRingBuffer[int](producers * batches * size)
+
+	// 每批的元素在队列中相邻，不与其他生产者交错
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for b := 0; b < batches; b++ {
+				batch := make([]int, size)
+				for i := range batch {
+					batch[i] = (p*batches+b)*size + i
+				}
+				if n := rb.TryEnqueueBatch(batch); n != size {
+					t.Errorf("Expected %d enqueued, got %d", size, n)
+				}
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	// 并发批量出队得到的是队列中相邻的一段
+	order := make([]int, 0, rb.Len())
+	for v, ok := rb.TryDequeue(); ok; v, ok = rb.TryDequeue() {
+		order = append(order, v)
+	}
+	for i := 0; i < len(order); i += size {
+		for j := 1; j < size; j++ {
+			if order[i+j] != order[i]+j {
+				t.Fatalf("Batch starting with %d is interleaved: %v", order[i], order[i:i+size])
+			}
+		}
+	}
+	for _, v := range order {
+		rb.TryEnqueue(v)
+	}
+	var cwg sync.WaitGroup
+	for c := 0; c < 4; c++ {
+		cwg.Add(1)
+		go func() {
+			defer cwg.Done()
+			buf := make([]int, size)
+			for n := rb.TryDequeueBatch(buf); n > 0; n = rb.TryDequeueBatch(buf) {
+				if n != size || buf[n-1] != buf[0]+n-1 {
+					t.Errorf("Expected a contiguous batch, got %v", buf[:n])
+				}
+			}
+		}()
+	}
+	cwg.Wait()
+	if !rb.IsEmpty() {
+		t.Error("Buffer should be empty")
+	}
+}
+
+// TestRingBufferConcurrent 多生产者多消费者压力测试，配合 -race 运行
+func TestRingBufferConcurrent(t *testing.T) {
+	const producers, consumers, perProducer = 4, 4, 5000
+	rb := NewRingBuffer[int](64)
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			batch := make([]int, 0, 8)
+			for i := 0; i < perProducer; i++ {
+				v := p*perProducer + i
+				if i%2 == 0 {
+					for !rb.TryEnqueue(v) {
+						runtime.Gosched()
+					}
+					continue
+				}
+				batch = append(batch, v)
+				if len(batch) == cap(batch) || i == perProducer-1 {
+					for pending := batch; len(pending) > 0; {
+						pending = pending[rb.TryEnqueueBatch(pending):]
+						runtime.Gosched()
+					}
+					batch = batch[:0]
+				}
+			}
+		}(p)
+	}
+
+	var received atomic.Int64
+	seen := make([]atomic.Bool, producers*perProducer)
+	var cwg sync.WaitGroup
+	for c := 0; c < consumers; c++ {
+		cwg.Add(1)
+		go func(c int) {
+			defer cwg.Done()
+			buf := make([]int, 4)
+			for received.Load() < producers*perProducer {
+				var n int
+				if c%2 == 0 {
+					n = rb.TryDequeueBatch(buf)
+				} else if v, ok := rb.TryDequeue(); ok {
+					buf[0] = v
+					n = 1
+				}
+				if n == 0 {
+					runtime.Gosched()
+					continue
+				}
+				for _, v := range buf[:n] {
+					if seen[v].Swap(true) {
+						t.Errorf("Duplicate value %d", v)
+					}
+				}
+				received.Add(int64(n))
+			}
+		}(c)
+	}
+	wg.Wait()
+	cwg.Wait()
+
+	if received.Load() != producers*perProducer {
+		t.Errorf("Expected %d values, got %d", producers*perProducer, received.Load())
+	}
+	for i := range seen {
+		if !seen[i].Load() {
+			t.Fatalf("Missing value %d", i)
+		}
+	}
+}
+
+// BenchmarkRingBuffer 性能测试:无锁环形队列并发入队出队
+func BenchmarkRingBuffer(b *testing.B) {
+	rb := NewRingBuffer[int](1024)
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%2 == 0 {
+				rb.TryEnqueue(i)
+			} else {
+				rb.TryDequeue()
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkRingBufferSafeFixedQueue 性能测试:同等场景下的 SafeFixedQueue
+func BenchmarkRingBufferSafeFixedQueue(b *testing.B) {
+	q := NewSafeFixedQueue[int](1024)
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%2 == 0 {
+				q.Enqueue(i)
+			} else {
+				q.Dequeue()
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkRingBufferChannel 性能测试:同等场景下的带缓冲通道
+func BenchmarkRingBufferChannel(b *testing.B) {
+	ch := make(chan int, 1024)
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%2 == 0 {
+				select {
+				case ch <- i:
+				default:
+				}
+			} else {
+				select {
+				case <-ch:
+				default:
+				}
+			}
+			i++
+		}
+	})
+}
@@ -1,14 +1,29 @@
 package queueUtil
 
-import "sync"
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Tomatosky/jo-util/logger"
+)
 
 type SafeFixedQueue[T any] struct {
-	items   []T
-	head    int
-	tail    int
-	size    int
-	maxSize int
-	mutex   sync.Mutex // 互斥锁
+	items    []T
+	head     int
+	tail     int
+	size     int
+	maxSize  int
+	enqueued uint64     // 累计入队数量
+	evicted  uint64     // 累计被淘汰的数量
+	rejected uint64     // 累计因队列已满被拒绝的数量
+	mutex    sync.Mutex // 互斥锁
+}
+
+// SafeFixedQueueStats 队列的累计统计
+type SafeFixedQueueStats struct {
+	Enqueued uint64 `json:"enqueued" bson:"enqueued"` // 累计入队数量，包括强制入队
+	Evicted  uint64 `json:"evicted" bson:"evicted"`   // 累计被强制入队或缩容淘汰的数量
+	Rejected uint64 `json:"rejected" bson:"rejected"` // 累计因队列已满被拒绝的数量
 }
 
 func NewSafeFixedQueue[T any](capacity int) *SafeFixedQueue[T] {
@@ -27,11 +42,13 @@ func (q *SafeFixedQueue[T]) Enqueue(item T) bool {
 	defer q.mutex.Unlock()
 
 	if q.size == q.maxSize {
+		q.rejected++
 		return false
 	}
 	q.items[q.tail] = item
 	q.tail = (q.tail + 1) % q.maxSize
 	q.size++
+	q.enqueued++
 	return true
 }
 
@@ -43,10 +60,12 @@ func (q *SafeFixedQueue[T]) EnqueueForce(item T) {
 	if q.size == q.maxSize {
 		q.head = (q.head + 1) % q.maxSize
 		q.size--
+		q.evicted++
 	}
 	q.items[q.tail] = item
 	q.tail = (q.tail + 1) % q.maxSize
 	q.size++
+	q.enqueued++
 }
 
 // Dequeue 安全出队
@@ -84,3 +103,109 @@ func (q *SafeFixedQueue[T]) IsEmpty() bool {
 	defer q.mutex.Unlock()
 	return q.size == 0
 }
+
+// Peek 安全查看队首元素（最早入队的元素）
+func (q *SafeFixedQueue[T]) Peek() (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.items[q.head], true
+}
+
+// PeekLast 安全查看队尾元素（最新入队的元素）
+func (q *SafeFixedQueue[T]) PeekLast() (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.items[(q.head+q.size-1)%q.maxSize], true
+}
+
+// At 安全获取第i个元素（0为队首），支持负数索引，-1表示队尾
+func (q *SafeFixedQueue[T]) At(i int) T {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if i < 0 {
+		i += q.size
+	}
+	if i < 0 || i >= q.size {
+		logger.Log.Error(fmt.Sprintf("%v", "index out of range"))
+		panic("index out of range")
+	}
+	return q.items[(q.head+i)%q.maxSize]
+}
+
+// Snapshot 安全获取所有元素的副本，按入队先后排列，不影响队列
+func (q *SafeFixedQueue[T]) Snapshot() []T {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.snapshot()
+}
+
+// Clear 安全清空队列，累计统计保持不变
+func (q *SafeFixedQueue[T]) Clear() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	clear(q.items)
+	q.head = 0
+	q.tail = 0
+	q.size = 0
+}
+
+// Resize 安全调整容量，元素超出新容量时保留最新的元素，被丢弃的元素计入淘汰数量
+func (q *SafeFixedQueue[T]) Resize(capacity int) {
+	if capacity <= 0 {
+		logger.Log.Error(fmt.Sprintf("%v", "capacity must be greater than 0"))
+		panic("capacity must be greater than 0")
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	items := q.snapshot()
+	if len(items) > capacity {
+		q.evicted += uint64(len(items) - capacity)
+		items = items[len(items)-capacity:]
+	}
+	q.items = make([]T, capacity)
+	copy(q.items, items)
+	q.head = 0
+	q.size = len(items)
+	q.tail = q.size % capacity
+	q.maxSize = capacity
+}
+
+// Cap 安全获取容量
+func (q *SafeFixedQueue[T]) Cap() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.maxSize
+}
+
+// Stats 安全获取累计统计
+func (q *SafeFixedQueue[T]) Stats() SafeFixedQueueStats {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return SafeFixedQueueStats{
+		Enqueued: q.enqueued,
+		Evicted:  q.evicted,
+		Rejected: q.rejected,
+	}
+}
+
+// snapshot 按入队先后复制所有元素，调用时需持有锁
+func (q *SafeFixedQueue[T]) snapshot() []T {
+	result := make([]T, q.size)
+	for i := range result {
+		result[i] = q.items[(q.head+i)%q.maxSize]
+	}
+	return result
+}
@@ -1,6 +1,7 @@
 package queueUtil
 
 import (
+	"reflect"
 	"sync"
 	"testing"
 )
@@ -768,6 +769,124 @@ func TestSafeFixedQueueFullEnqueue(t *testing.T) {
 	}
 }
 
+// TestSafeFixedQueuePeek 测试查看队首队尾和下标访问
+func TestSafeFixedQueuePeek(t *testing.T) {
+	q := NewSafeFixedQueue[int](3)
+	if _, ok := q.Peek(); ok {
+		t.Error("Peek on empty queue should fail")
+	}
+	if _, ok := q.PeekLast(); ok {
+		t.Error("PeekLast on empty queue should fail")
+	}
+
+	for i := 1; i <= 5; i++ {
+		q.EnqueueForce(i)
+	}
+	if v, _ := q.Peek(); v != 3 {
+		t.Errorf("Peek() = %d, want 3", v)
+	}
+	if v, _ := q.PeekLast(); v != 5 {
+		t.Errorf("PeekLast() = %d, want 5", v)
+	}
+	if q.At(0) != 3 || q.At(1) != 4 || q.At(-1) != 5 {
+		t.Error("Unexpected At result")
+	}
+	if q.Len() != 3 {
+		t.Errorf("Peek should not change length, got %d", q.Len())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("At out of range should panic")
+		}
+	}()
+	q.At(3)
+}
+
+// TestSafeFixedQueueSnapshot 测试快照和清空
+func TestSafeFixedQueueSnapshot(t *testing.T) {
+	q := NewSafeFixedQueue[int](4)
+	if len(q.Snapshot()) != 0 {
+		t.Error("Snapshot of empty queue should be empty")
+	}
+	for i := 1; i <= 6; i++ {
+		q.EnqueueForce(i)
+	}
+	snapshot := q.Snapshot()
+	if !reflect.DeepEqual(snapshot, []int{3, 4, 5, 6}) {
+		t.Errorf("Snapshot() = %v", snapshot)
+	}
+	snapshot[0] = 100
+	if v, _ := q.Peek(); v != 3 {
+		t.Error("Modifying snapshot should not affect queue")
+	}
+
+	q.Clear()
+	if !q.IsEmpty() || len(q.Snapshot()) != 0 {
+		t.Error("Queue should be empty after Clear")
+	}
+	q.Enqueue(7)
+	if !reflect.DeepEqual(q.Snapshot(), []int{7}) {
+		t.Errorf("Snapshot() after Clear = %v", q.Snapshot())
+	}
+}
+
+// TestSafeFixedQueueResize 测试调整容量
+func TestSafeFixedQueueResize(t *testing.T) {
+	q := NewSafeFixedQueue[int](5)
+	for i := 1; i <= 7; i++ {
+		q.EnqueueForce(i)
+	}
+
+	q.Resize(3)
+	if q.Cap() != 3 || !reflect.DeepEqual(q.Snapshot(), []int{5, 6, 7}) {
+		t.Errorf("After shrink: cap %d, items %v", q.Cap(), q.Snapshot())
+	}
+	if !q.IsFull() {
+		t.Error("Queue should be full after shrink")
+	}
+
+	q.Resize(6)
+	if q.Cap() != 6 || q.IsFull() {
+		t.Errorf("After grow: cap %d", q.Cap())
+	}
+	q.Enqueue(8)
+	q.Enqueue(9)
+	q.Enqueue(10)
+	if !reflect.DeepEqual(q.Snapshot(), []int{5, 6, 7, 8, 9, 10}) {
+		t.Errorf("After grow: items %v", q.Snapshot())
+	}
+	if v, _ := q.Dequeue(); v != 5 {
+		t.Errorf("Dequeue() = %d, want 5", v)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Resize to 0 should panic")
+		}
+	}()
+	q.Resize(0)
+}
+
+// TestSafeFixedQueueStats 测试累计统计
+func TestSafeFixedQueueStats(t *testing.T) {
+	q := NewSafeFixedQueue[int](2)
+	q.Enqueue(1)
+	q.Enqueue(2)
+	q.Enqueue(3)      // 拒绝
+	q.EnqueueForce(4) // 淘汰1
+	q.Dequeue()
+	q.Clear()
+	q.Enqueue(5)
+	q.Enqueue(6)
+	q.Resize(1) // 淘汰5
+
+	want := SafeFixedQueueStats{Enqueued: 5, Evicted: 2, Rejected: 1}
+	if stats := q.Stats(); stats != want {
+		t.Errorf("Stats() = %+v, want %+v", stats, want)
+	}
+}
+
 // BenchmarkSafeFixedQueueEnqueue 性能测试:入队
 func BenchmarkSafeFixedQueueEnqueue(b *testing.B) {
 	q := NewSafeFixedQueue[int](b.N)
@@ -0,0 +1,254 @@
+package setUtil
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+
+	"github.com/Tomatosky/jo-util/convertor"
+	"github.com/Tomatosky/jo-util/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var _ bson.ValueMarshaler = (*BloomFilter[int])(nil)
+var _ bson.ValueUnmarshaler = (*BloomFilter[int])(nil)
+var _ json.Marshaler = (*BloomFilter[int])(nil)
+var _ json.Unmarshaler = (*BloomFilter[int])(nil)
+var _ encoding.BinaryMarshaler = (*BloomFilter[int])(nil)
+var _ encoding.BinaryUnmarshaler = (*BloomFilter[int])(nil)
+
+// BloomFilter 布隆过滤器，非并发安全
+// Contains 返回 false 时元素一定不存在，返回 true 时元素可能存在
+type BloomFilter[T any] struct {
+	m    uint64   // 位数组长度
+	k    uint64   // 哈希函数个数
+	bits []uint64 // 位数组
+}
+
+// bloomFilterData 布隆过滤器的序列化结构
+type bloomFilterData struct {
+	M    uint64 `json:"m" bson:"m"`
+	K    uint64 `json:"k" bson:"k"`
+	Data []byte `json:"data" bson:"data"`
+}
+
+// NewBloomFilter 根据预期元素数量和目标误判率创建布隆过滤器
+// expectedItems: 预期元素数量
+// falsePositiveRate: 目标误判率，取值范围 (0, 1)
+func NewBloomFilter[T any](expectedItems uint64, falsePositiveRate float64) *BloomFilter[T] {
+	m, k := bloomParams(expectedItems, falsePositiveRate)
+	return NewBloomFilterWithSize[T](m, k)
+}
+
+// NewBloomFilterWithSize 根据位数组长度和哈希函数个数创建布隆过滤器
+func NewBloomFilterWithSize[T any](m, k uint64) *BloomFilter[T] {
+	if m == 0 || k == 0 {
+		logger.Log.Error(fmt.Sprintf("%v", "bloom filter size and hash count must be greater than 0"))
+		panic("bloom filter size and hash count must be greater than 0")
+	}
+	return &BloomFilter[T]{
+		m:    m,
+		k:    k,
+		bits: make([]uint64, (m+63)/64),
+	}
+}
+
+// Add 添加元素
+func (b *BloomFilter[T]) Add(element T) {
+	h1, h2 := bloomHash(element)
+	for i := uint64(0); i < b.k; i++ {
+		pos := bloomLocation(h1, h2, i, b.m)
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+// AddAll 批量添加元素
+func (b *BloomFilter[T]) AddAll(elements ...T) {
+	for _, e := range elements {
+		b.Add(e)
+	}
+}
+
+// Contains 检查元素是否可能存在
+func (b *BloomFilter[T]) Contains(element T) bool {
+	h1, h2 := bloomHash(element)
+	for i := uint64(0); i < b.k; i++ {
+		pos := bloomLocation(h1, h2, i, b.m)
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge 合并另一个布隆过滤器，两者的位数组长度和哈希函数个数必须相同
+func (b *BloomFilter[T]) Merge(other *BloomFilter[T]) error {
+	if b.m != other.m || b.k != other.k {
+		return fmt.Errorf("bloom filter mismatch: m=%d k=%d, other m=%d k=%d", b.m, b.k, other.m, other.k)
+	}
+	for i := range b.bits {
+		b.bits[i] |= other.bits[i]
+	}
+	return nil
+}
+
+// EstimatedCount 根据置位数量估算已添加的不同元素数量
+func (b *BloomFilter[T]) EstimatedCount() uint64 {
+	ones := 0
+	for _, w := range b.bits {
+		ones += bits.OnesCount64(w)
+	}
+	return estimateBloomCount(b.m, b.k, uint64(ones))
+}
+
+// FalsePositiveRate 根据当前估算数量计算误判率
+func (b *BloomFilter[T]) FalsePositiveRate() float64 {
+	return bloomFalsePositiveRate(b.m, b.k, b.EstimatedCount())
+}
+
+// Cap 位数组长度
+func (b *BloomFilter[T]) Cap() uint64 {
+	return b.m
+}
+
+// HashCount 哈希函数个数
+func (b *BloomFilter[T]) HashCount() uint64 {
+	return b.k
+}
+
+// Clear 清空过滤器
+func (b *BloomFilter[T]) Clear() {
+	b.bits = make([]uint64, (b.m+63)/64)
+}
+
+func (b *BloomFilter[T]) toData() *bloomFilterData {
+	data := make([]byte, len(b.bits)*8)
+	for i, w := range b.bits {
+		binary.BigEndian.PutUint64(data[i*8:], w)
+	}
+	return &bloomFilterData{M: b.m, K: b.k, Data: data}
+}
+
+func (b *BloomFilter[T]) fromData(d *bloomFilterData) error {
+	if d.M == 0 || d.K == 0 || uint64(len(d.Data)) != (d.M+63)/64*8 {
+		return errors.New("invalid bloom filter data")
+	}
+	b.m = d.M
+	b.k = d.K
+	b.bits = make([]uint64, len(d.Data)/8)
+	for i := range b.bits {
+		b.bits[i] = binary.BigEndian.Uint64(d.Data[i*8:])
+	}
+	return nil
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler 接口
+// 格式: m(8字节) + k(8字节) + 位数组
+func (b *BloomFilter[T]) MarshalBinary() ([]byte, error) {
+	d := b.toData()
+	buf := make([]byte, 16, 16+len(d.Data))
+	binary.BigEndian.PutUint64(buf, d.M)
+	binary.BigEndian.PutUint64(buf[8:], d.K)
+	return append(buf, d.Data...), nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler 接口
+func (b *BloomFilter[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 {
+		return errors.New("invalid bloom filter data")
+	}
+	return b.fromData(&bloomFilterData{
+		M:    binary.BigEndian.Uint64(data),
+		K:    binary.BigEndian.Uint64(data[8:]),
+		Data: data[16:],
+	})
+}
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (b *BloomFilter[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.toData())
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+func (b *BloomFilter[T]) UnmarshalJSON(data []byte) error {
+	var d bloomFilterData
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	return b.fromData(&d)
+}
+
+func (b *BloomFilter[T]) MarshalBSONValue() (byte, []byte, error) {
+	typ, data, err := bson.MarshalValue(b.toData())
+	return byte(typ), data, err
+}
+
+func (b *BloomFilter[T]) UnmarshalBSONValue(t byte, data []byte) error {
+	var d bloomFilterData
+	if err := bson.UnmarshalValue(bson.Type(t), data, &d); err != nil {
+		return err
+	}
+	return b.fromData(&d)
+}
+
+// bloomParams 计算最优的位数组长度和哈希函数个数
+func bloomParams(n uint64, p float64) (m, k uint64) {
+	if p <= 0 || p >= 1 {
+		logger.Log.Error(fmt.Sprintf("%v", "false positive rate must be between 0 and 1"))
+		panic("false positive rate must be between 0 and 1")
+	}
+	if n == 0 {
+		n = 1
+	}
+	m = uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k = uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return m, k
+}
+
+// bloomHash 计算元素的两个64位哈希值，用于双重哈希
+func bloomHash(element any) (uint64, uint64) {
+	var data []byte
+	switch v := element.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		var err error
+		data, err = convertor.ToBytes(element)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("%v", err))
+			panic(err)
+		}
+	}
+	h := fnv.New128a()
+	_, _ = h.Write(data)
+	sum := h.Sum(nil)
+	return binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:])
+}
+
+// bloomLocation 第i个哈希函数对应的位置：(h1 + i*h2) mod m
+func bloomLocation(h1, h2, i, m uint64) uint64 {
+	return (h1 + i*h2) % m
+}
+
+// estimateBloomCount 根据置位数量估算元素数量：n = -(m/k) * ln(1 - X/m)
+func estimateBloomCount(m, k, ones uint64) uint64 {
+	if ones >= m {
+		return uint64(math.Round(float64(m) / float64(k) * math.Log(float64(m))))
+	}
+	return uint64(math.Round(-float64(m) / float64(k) * math.Log(1-float64(ones)/float64(m))))
+}
+
+// bloomFalsePositiveRate 误判率：(1 - e^(-kn/m))^k
+func bloomFalsePositiveRate(m, k, n uint64) float64 {
+	return math.Pow(1-math.Exp(-float64(k)*float64(n)/float64(m)), float64(k))
+}
@@ -0,0 +1,157 @@
+package setUtil
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestNewBloomFilter(t *testing.T) {
+	bf := NewBloomFilter[string](1000, 0.01)
+	// 1000个元素、1%误判率时的最优参数约为 m=9586, k=7
+	if bf.Cap() != 9586 || bf.HashCount() != 7 {
+		t.Errorf("Unexpected params m=%d k=%d", bf.Cap(), bf.HashCount())
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for invalid false positive rate")
+		}
+	}()
+	NewBloomFilter[string](1000, 1)
+}
+
+func TestBloomFilterAddAndContains(t *testing.T) {
+	const n = 10000
+	bf := NewBloomFilter[string](n, 0.01)
+	for i := 0; i < n; i++ {
+		bf.Add(fmt.Sprintf("id-%d", i))
+	}
+
+	// 已添加的元素一定存在
+	for i := 0; i < n; i++ {
+		if !bf.Contains(fmt.Sprintf("id-%d", i)) {
+			t.Fatalf("Expected id-%d to be contained", i)
+		}
+	}
+
+	// 误判率应接近目标值
+	falsePositives := 0
+	for i := n; i < 2*n; i++ {
+		if bf.Contains(fmt.Sprintf("id-%d", i)) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / n; rate > 0.02 {
+		t.Errorf("False positive rate %.4f exceeds expected", rate)
+	}
+
+	// 估算数量误差在5%以内
+	if est := bf.EstimatedCount(); est < n*95/100 || est > n*105/100 {
+		t.Errorf("EstimatedCount %d too far from %d", est, n)
+	}
+	if rate := bf.FalsePositiveRate(); rate <= 0 || rate > 0.02 {
+		t.Errorf("Unexpected FalsePositiveRate %.4f", rate)
+	}
+
+	bf.Clear()
+	if bf.Contains("id-1") || bf.EstimatedCount() != 0 {
+		t.Error("Expected empty filter after clear")
+	}
+}
+
+func TestBloomFilterTypes(t *testing.T) {
+	ints := NewBloomFilter[int64](100, 0.01)
+	ints.AddAll(1, 2, 3)
+	if !ints.Contains(2) || ints.Contains(100) {
+		t.Error("Unexpected Contains result for int64 filter")
+	}
+
+	type key struct {
+		ServerID int
+		UserID   string
+	}
+	structs := NewBloomFilter[key](100, 0.01)
+	structs.Add(key{1, "a"})
+	if !structs.Contains(key{1, "a"}) || structs.Contains(key{2, "a"}) {
+		t.Error("Unexpected Contains result for struct filter")
+	}
+
+	bytes := NewBloomFilter[[]byte](100, 0.01)
+	bytes.Add([]byte("abc"))
+	if !bytes.Contains([]byte("abc")) {
+		t.Error("Unexpected Contains result for []byte filter")
+	}
+}
+
+func TestBloomFilterMerge(t *testing.T) {
+	a := NewBloomFilter[string](100, 0.01)
+	b := NewBloomFilter[string](100, 0.01)
+	a.Add("a")
+	b.Add("b")
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	if !a.Contains("a") || !a.Contains("b") {
+		t.Error("Expected merged filter to contain both elements")
+	}
+
+	c := NewBloomFilter[string](1000, 0.01)
+	if err := a.Merge(c); err == nil {
+		t.Error("Expected error when merging incompatible filters")
+	}
+}
+
+func TestBloomFilterSerialization(t *testing.T) {
+	bf := NewBloomFilter[string](100, 0.01)
+	bf.AddAll("a", "b", "c")
+
+	check := func(name string, decoded *BloomFilter[string]) {
+		if decoded.Cap() != bf.Cap() || decoded.HashCount() != bf.HashCount() {
+			t.Errorf("%s: params mismatch", name)
+		}
+		for _, v := range []string{"a", "b", "c"} {
+			if !decoded.Contains(v) {
+				t.Errorf("%s: expected %s to be contained", name, v)
+			}
+		}
+	}
+
+	data, err := bf.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary error: %v", err)
+	}
+	var fromBinary BloomFilter[string]
+	if err = fromBinary.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary error: %v", err)
+	}
+	check("binary", &fromBinary)
+	if err = fromBinary.UnmarshalBinary(data[:20]); err == nil {
+		t.Error("Expected error for truncated data")
+	}
+
+	data, err = json.Marshal(bf)
+	if err != nil {
+		t.Fatalf("MarshalJSON error: %v", err)
+	}
+	var fromJSON BloomFilter[string]
+	if err = json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("UnmarshalJSON error: %v", err)
+	}
+	check("json", &fromJSON)
+
+	type doc struct {
+		Filter *BloomFilter[string] `bson:"filter"`
+	}
+	data, err = bson.Marshal(doc{Filter: bf})
+	if err != nil {
+		t.Fatalf("MarshalBSON error: %v", err)
+	}
+	var fromBSON doc
+	if err = bson.Unmarshal(data, &fromBSON); err != nil {
+		t.Fatalf("UnmarshalBSON error: %v", err)
+	}
+	check("bson", fromBSON.Filter)
+}
@@ -9,10 +9,11 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-var _ bson.ValueMarshaler = (*ConcurrentHashSet)(nil)
-var _ bson.ValueUnmarshaler = (*ConcurrentHashSet)(nil)
-var _ json.Marshaler = (*ConcurrentHashSet)(nil)
-var _ json.Unmarshaler = (*ConcurrentHashSet)(nil)
+var _ ISet[int] = (*ConcurrentHashSet[int])(nil)
+var _ bson.ValueMarshaler = (*ConcurrentHashSet[int])(nil)
+var _ bson.ValueUnmarshaler = (*ConcurrentHashSet[int])(nil)
+var _ json.Marshaler = (*ConcurrentHashSet[int])(nil)
+var _ json.Unmarshaler = (*ConcurrentHashSet[int])(nil)
 
 // ConcurrentHashSet 基于 ConcurrentHashMap 实现的并发安全集合
 type ConcurrentHashSet[T comparable] struct {
@@ -83,6 +84,36 @@ func (s *ConcurrentHashSet[T]) IsEmpty() bool {
 	return s.Size() == 0
 }
 
+// Union 返回并集
+func (s *ConcurrentHashSet[T]) Union(other ISet[T]) ISet[T] {
+	return union[T](NewConcurrentHashSet[T](), s, other)
+}
+
+// Intersect 返回交集
+func (s *ConcurrentHashSet[T]) Intersect(other ISet[T]) ISet[T] {
+	return intersect[T](NewConcurrentHashSet[T](), s, other)
+}
+
+// Difference 返回差集（存在于当前集合但不存在于other的元素）
+func (s *ConcurrentHashSet[T]) Difference(other ISet[T]) ISet[T] {
+	return difference[T](NewConcurrentHashSet[T](), s, other)
+}
+
+// SymmetricDifference 返回对称差集（只存在于其中一个集合的元素）
+func (s *ConcurrentHashSet[T]) SymmetricDifference(other ISet[T]) ISet[T] {
+	return symmetricDifference[T](NewConcurrentHashSet[T](), s, other)
+}
+
+// IsSubsetOf 判断当前集合是否为other的子集
+func (s *ConcurrentHashSet[T]) IsSubsetOf(other ISet[T]) bool {
+	return isSubsetOf[T](s, other)
+}
+
+// Equal 判断两个集合是否包含相同的元素
+func (s *ConcurrentHashSet[T]) Equal(other ISet[T]) bool {
+	return equal[T](s, other)
+}
+
 func (s *ConcurrentHashSet[T]) ToString() string {
 	bytes, err := json.Marshal(s.ToSlice())
 	if err != nil {
@@ -199,3 +199,32 @@ func TestConcurrentOperations(t *testing.T) {
 			size, numOperations/2, numOperations)
 	}
 }
+
+func TestSetAlgebra3(t *testing.T) {
+	a := NewConcurrentHashSet("a", "b", "c")
+	b := NewHashSet("b", "c", "d")
+
+	union := a.Union(b)
+	if _, ok := union.(*ConcurrentHashSet[string]); !ok {
+		t.Errorf("Expected result type *ConcurrentHashSet, got %T", union)
+	}
+	if !union.Equal(NewHashSet("a", "b", "c", "d")) {
+		t.Errorf("Unexpected union: %v", union.ToSlice())
+	}
+	if !a.Intersect(b).Equal(NewHashSet("b", "c")) {
+		t.Errorf("Unexpected intersect: %v", a.Intersect(b).ToSlice())
+	}
+	if !a.Difference(b).Equal(NewHashSet("a")) {
+		t.Errorf("Unexpected difference: %v", a.Difference(b).ToSlice())
+	}
+	if !a.SymmetricDifference(b).Equal(NewHashSet("a", "d")) {
+		t.Errorf("Unexpected symmetric difference: %v", a.SymmetricDifference(b).ToSlice())
+	}
+	if !a.Intersect(b).IsSubsetOf(a) {
+		t.Error("Expected intersect to be subset")
+	}
+	// 与自身运算不应死锁
+	if !a.Union(a).Equal(a) {
+		t.Error("Expected union with self to equal self")
+	}
+}
@@ -0,0 +1,238 @@
+package setUtil
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/Tomatosky/jo-util/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var _ bson.ValueMarshaler = (*CountingBloomFilter[int])(nil)
+var _ bson.ValueUnmarshaler = (*CountingBloomFilter[int])(nil)
+var _ json.Marshaler = (*CountingBloomFilter[int])(nil)
+var _ json.Unmarshaler = (*CountingBloomFilter[int])(nil)
+var _ encoding.BinaryMarshaler = (*CountingBloomFilter[int])(nil)
+var _ encoding.BinaryUnmarshaler = (*CountingBloomFilter[int])(nil)
+
+// CountingBloomFilter 并发安全的计数布隆过滤器，支持删除元素
+// 每个位置使用8位计数器，达到上限后不再增减
+type CountingBloomFilter[T any] struct {
+	mu       sync.RWMutex
+	m        uint64  // 计数器个数
+	k        uint64  // 哈希函数个数
+	counters []uint8 // 计数器数组
+}
+
+// NewCountingBloomFilter 根据预期元素数量和目标误判率创建计数布隆过滤器
+// expectedItems: 预期元素数量
+// falsePositiveRate: 目标误判率，取值范围 (0, 1)
+func NewCountingBloomFilter[T any](expectedItems uint64, falsePositiveRate float64) *CountingBloomFilter[T] {
+	m, k := bloomParams(expectedItems, falsePositiveRate)
+	return NewCountingBloomFilterWithSize[T](m, k)
+}
+
+// NewCountingBloomFilterWithSize 根据计数器个数和哈希函数个数创建计数布隆过滤器
+func NewCountingBloomFilterWithSize[T any](m, k uint64) *CountingBloomFilter[T] {
+	if m == 0 || k == 0 {
+		logger.Log.Error(fmt.Sprintf("%v", "bloom filter size and hash count must be greater than 0"))
+		panic("bloom filter size and hash count must be greater than 0")
+	}
+	return &CountingBloomFilter[T]{
+		m:        m,
+		k:        k,
+		counters: make([]uint8, m),
+	}
+}
+
+// Add 添加元素
+func (b *CountingBloomFilter[T]) Add(element T) {
+	h1, h2 := bloomHash(element)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := uint64(0); i < b.k; i++ {
+		pos := bloomLocation(h1, h2, i, b.m)
+		if b.counters[pos] < math.MaxUint8 {
+			b.counters[pos]++
+		}
+	}
+}
+
+// AddAll 批量添加元素
+func (b *CountingBloomFilter[T]) AddAll(elements ...T) {
+	for _, e := range elements {
+		b.Add(e)
+	}
+}
+
+// Remove 移除元素，元素一定不存在时返回false
+// 只应移除确实添加过的元素，否则可能导致其他元素被误判为不存在
+func (b *CountingBloomFilter[T]) Remove(element T) bool {
+	h1, h2 := bloomHash(element)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.contains(h1, h2) {
+		return false
+	}
+	for i := uint64(0); i < b.k; i++ {
+		pos := bloomLocation(h1, h2, i, b.m)
+		// 已饱和的计数器无法确定真实值，保持不变
+		if b.counters[pos] < math.MaxUint8 {
+			b.counters[pos]--
+		}
+	}
+	return true
+}
+
+// Contains 检查元素是否可能存在
+func (b *CountingBloomFilter[T]) Contains(element T) bool {
+	h1, h2 := bloomHash(element)
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.contains(h1, h2)
+}
+
+func (b *CountingBloomFilter[T]) contains(h1, h2 uint64) bool {
+	for i := uint64(0); i < b.k; i++ {
+		if b.counters[bloomLocation(h1, h2, i, b.m)] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge 合并另一个计数布隆过滤器，两者的计数器个数和哈希函数个数必须相同
+func (b *CountingBloomFilter[T]) Merge(other *CountingBloomFilter[T]) error {
+	other.mu.RLock()
+	counters := append([]uint8(nil), other.counters...)
+	m, k := other.m, other.k
+	other.mu.RUnlock()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.m != m || b.k != k {
+		return fmt.Errorf("bloom filter mismatch: m=%d k=%d, other m=%d k=%d", b.m, b.k, m, k)
+	}
+	for i, c := range counters {
+		sum := int(b.counters[i]) + int(c)
+		if sum > math.MaxUint8 {
+			sum = math.MaxUint8
+		}
+		b.counters[i] = uint8(sum)
+	}
+	return nil
+}
+
+// EstimatedCount 根据非零计数器数量估算当前的不同元素数量
+func (b *CountingBloomFilter[T]) EstimatedCount() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	nonZero := uint64(0)
+	for _, c := range b.counters {
+		if c > 0 {
+			nonZero++
+		}
+	}
+	return estimateBloomCount(b.m, b.k, nonZero)
+}
+
+// FalsePositiveRate 根据当前估算数量计算误判率
+func (b *CountingBloomFilter[T]) FalsePositiveRate() float64 {
+	n := b.EstimatedCount()
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return bloomFalsePositiveRate(b.m, b.k, n)
+}
+
+// Cap 计数器个数
+func (b *CountingBloomFilter[T]) Cap() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.m
+}
+
+// HashCount 哈希函数个数
+func (b *CountingBloomFilter[T]) HashCount() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.k
+}
+
+// Clear 清空过滤器
+func (b *CountingBloomFilter[T]) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.counters = make([]uint8, b.m)
+}
+
+func (b *CountingBloomFilter[T]) toData() *bloomFilterData {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return &bloomFilterData{M: b.m, K: b.k, Data: append([]byte(nil), b.counters...)}
+}
+
+func (b *CountingBloomFilter[T]) fromData(d *bloomFilterData) error {
+	if d.M == 0 || d.K == 0 || uint64(len(d.Data)) != d.M {
+		return errors.New("invalid counting bloom filter data")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.m = d.M
+	b.k = d.K
+	b.counters = append([]uint8(nil), d.Data...)
+	return nil
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler 接口
+// 格式: m(8字节) + k(8字节) + 计数器数组
+func (b *CountingBloomFilter[T]) MarshalBinary() ([]byte, error) {
+	d := b.toData()
+	buf := make([]byte, 16, 16+len(d.Data))
+	binary.BigEndian.PutUint64(buf, d.M)
+	binary.BigEndian.PutUint64(buf[8:], d.K)
+	return append(buf, d.Data...), nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler 接口
+func (b *CountingBloomFilter[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 {
+		return errors.New("invalid counting bloom filter data")
+	}
+	return b.fromData(&bloomFilterData{
+		M:    binary.BigEndian.Uint64(data),
+		K:    binary.BigEndian.Uint64(data[8:]),
+		Data: data[16:],
+	})
+}
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (b *CountingBloomFilter[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.toData())
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+func (b *CountingBloomFilter[T]) UnmarshalJSON(data []byte) error {
+	var d bloomFilterData
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	return b.fromData(&d)
+}
+
+func (b *CountingBloomFilter[T]) MarshalBSONValue() (byte, []byte, error) {
+	typ, data, err := bson.MarshalValue(b.toData())
+	return byte(typ), data, err
+}
+
+func (b *CountingBloomFilter[T]) UnmarshalBSONValue(t byte, data []byte) error {
+	var d bloomFilterData
+	if err := bson.UnmarshalValue(bson.Type(t), data, &d); err != nil {
+		return err
+	}
+	return b.fromData(&d)
+}
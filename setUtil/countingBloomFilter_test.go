@@ -0,0 +1,146 @@
+package setUtil
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestCountingBloomFilterAddRemove(t *testing.T) {
+	bf := NewCountingBloomFilter[string](1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		bf.Add(fmt.Sprintf("id-%d", i))
+	}
+	for i := 0; i < 1000; i++ {
+		if !bf.Contains(fmt.Sprintf("id-%d", i)) {
+			t.Fatalf("Expected id-%d to be contained", i)
+		}
+	}
+
+	// 移除一半元素
+	for i := 0; i < 500; i++ {
+		if !bf.Remove(fmt.Sprintf("id-%d", i)) {
+			t.Errorf("Expected id-%d to be removed", i)
+		}
+	}
+	removedStillPresent := 0
+	for i := 0; i < 500; i++ {
+		if bf.Contains(fmt.Sprintf("id-%d", i)) {
+			removedStillPresent++
+		}
+	}
+	if removedStillPresent > 20 {
+		t.Errorf("Too many removed elements still reported: %d", removedStillPresent)
+	}
+	// 未移除的元素一定存在
+	for i := 500; i < 1000; i++ {
+		if !bf.Contains(fmt.Sprintf("id-%d", i)) {
+			t.Fatalf("Expected id-%d to still be contained", i)
+		}
+	}
+
+	if est := bf.EstimatedCount(); est < 450 || est > 550 {
+		t.Errorf("EstimatedCount %d too far from 500", est)
+	}
+
+	// 移除一定不存在的元素
+	empty := NewCountingBloomFilter[string](100, 0.01)
+	if empty.Remove("missing") {
+		t.Error("Expected Remove of missing element to return false")
+	}
+
+	bf.Clear()
+	if bf.Contains("id-600") {
+		t.Error("Expected empty filter after clear")
+	}
+}
+
+func TestCountingBloomFilterMerge(t *testing.T) {
+	a := NewCountingBloomFilter[int](100, 0.01)
+	b := NewCountingBloomFilter[int](100, 0.01)
+	a.Add(1)
+	b.Add(2)
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	if !a.Contains(1) || !a.Contains(2) {
+		t.Error("Expected merged filter to contain both elements")
+	}
+	a.Remove(2)
+	if a.Contains(2) || !b.Contains(2) {
+		t.Error("Expected removal to only affect merged filter")
+	}
+	if err := a.Merge(NewCountingBloomFilter[int](1000, 0.01)); err == nil {
+		t.Error("Expected error when merging incompatible filters")
+	}
+}
+
+func TestCountingBloomFilterSerialization(t *testing.T) {
+	bf := NewCountingBloomFilter[string](100, 0.01)
+	bf.AddAll("a", "b", "a")
+
+	data, err := bf.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary error: %v", err)
+	}
+	var fromBinary CountingBloomFilter[string]
+	if err = fromBinary.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary error: %v", err)
+	}
+	// 计数保留：移除一次后 a 仍存在
+	fromBinary.Remove("a")
+	if !fromBinary.Contains("a") || !fromBinary.Contains("b") {
+		t.Error("Expected counters to be preserved")
+	}
+
+	data, err = json.Marshal(bf)
+	if err != nil {
+		t.Fatalf("MarshalJSON error: %v", err)
+	}
+	var fromJSON CountingBloomFilter[string]
+	if err = json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("UnmarshalJSON error: %v", err)
+	}
+	if !fromJSON.Contains("a") || fromJSON.Cap() != bf.Cap() {
+		t.Error("Unexpected JSON decoded filter")
+	}
+
+	type doc struct {
+		Filter *CountingBloomFilter[string] `bson:"filter"`
+	}
+	data, err = bson.Marshal(doc{Filter: bf})
+	if err != nil {
+		t.Fatalf("MarshalBSON error: %v", err)
+	}
+	var fromBSON doc
+	if err = bson.Unmarshal(data, &fromBSON); err != nil {
+		t.Fatalf("UnmarshalBSON error: %v", err)
+	}
+	if !fromBSON.Filter.Contains("b") {
+		t.Error("Unexpected BSON decoded filter")
+	}
+}
+
+func TestCountingBloomFilterConcurrent(t *testing.T) {
+	bf := NewCountingBloomFilter[int](10000, 0.01)
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func(base int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				bf.Add(base*1000 + i)
+				bf.Contains(i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	for i := 0; i < 10000; i++ {
+		if !bf.Contains(i) {
+			t.Fatalf("Expected %d to be contained", i)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package setUtil
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Tomatosky/jo-util/logger"
+)
+
+const (
+	defaultSweepInterval = 30 * time.Second // 默认清理间隔
+	noExpiration         = math.MaxInt64    // 永不过期
+)
+
+// ExpiringSetOpt ExpiringSet 的配置
+type ExpiringSetOpt[T comparable] struct {
+	TTL           time.Duration   // 默认过期时间，<=0 表示永不过期
+	MaxSize       int             // 最大元素数量，<=0 表示不限制，满时淘汰最早过期的元素
+	SweepInterval time.Duration   // 后台清理间隔，默认30秒
+	OnExpired     func(element T) // 元素过期回调，被容量淘汰或主动移除的元素不会触发
+}
+
+// ExpiringSet 并发安全的过期集合，元素到期后自动移除
+type ExpiringSet[T comparable] struct {
+	mu        sync.Mutex
+	items     map[T]*expiringEntry[T]
+	heap      expiringHeap[T] // 按过期时间排序的小顶堆
+	ttl       time.Duration
+	maxSize   int
+	onExpired func(element T)
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+type expiringEntry[T comparable] struct {
+	element    T
+	expiration int64 // 过期时间（纳秒时间戳）
+	index      int   // 在堆中的下标
+}
+
+// NewExpiringSet 创建过期集合并启动后台清理
+func NewExpiringSet[T comparable](opt *ExpiringSetOpt[T]) *ExpiringSet[T] {
+	if opt == nil {
+		opt = &ExpiringSetOpt[T]{}
+	}
+	interval := opt.SweepInterval
+	if interval <= 0 {
+		interval = defaultSweepInterval
+	}
+	s := &ExpiringSet[T]{
+		items:     make(map[T]*expiringEntry[T]),
+		ttl:       opt.TTL,
+		maxSize:   opt.MaxSize,
+		onExpired: opt.OnExpired,
+		stop:      make(chan struct{}),
+	}
+	go s.runSweeper(interval)
+	return s
+}
+
+// Add 使用默认过期时间添加元素，已存在时刷新过期时间，已过期但尚未清理的元素先触发过期回调
+func (s *ExpiringSet[T]) Add(element T) {
+	s.AddWithTTL(element, s.ttl)
+}
+
+// AddWithTTL 使用指定过期时间添加元素，已存在时刷新过期时间，已过期但尚未清理的元素先触发过期回调
+// ttl<=0 表示永不过期，过期时间溢出时同样视为永不过期
+func (s *ExpiringSet[T]) AddWithTTL(element T, ttl time.Duration) {
+	s.mu.Lock()
+	expired := s.add(element, ttl, time.Now().UnixNano())
+	s.mu.Unlock()
+	s.notifyExpired(expired)
+}
+
+// AddIfAbsent 元素不存在时添加，返回是否添加成功，可用于请求去重
+// ttl 为可选的过期时间，不传时使用默认过期时间
+func (s *ExpiringSet[T]) AddIfAbsent(element T, ttl ...time.Duration) bool {
+	d := s.ttl
+	if len(ttl) > 0 {
+		d = ttl[0]
+	}
+	now := time.Now().UnixNano()
+
+	s.mu.Lock()
+	if e, ok := s.items[element]; ok && e.expiration > now {
+		s.mu.Unlock()
+		return false
+	}
+	expired := s.add(element, d, now)
+	s.mu.Unlock()
+	s.notifyExpired(expired)
+	return true
+}
+
+// add 添加或刷新元素，调用时需持有锁，返回清理掉的过期元素
+// 元素已过期但尚未清理时先按过期移除再重新添加，过期时间溢出时视为永不过期
+func (s *ExpiringSet[T]) add(element T, ttl time.Duration, now int64) []T {
+	expiration := int64(noExpiration)
+	if ttl > 0 {
+		expiration = now + min(int64(ttl), noExpiration-now)
+	}
+	var expired []T
+	if e, ok := s.items[element]; ok {
+		if e.expiration > now {
+			e.expiration = expiration
+			heap.Fix(&s.heap, e.index)
+			return nil
+		}
+		s.removeEntry(e)
+		expired = append(expired, element)
+	}
+
+	if s.maxSize > 0 && len(s.items) >= s.maxSize {
+		expired = append(expired, s.removeExpired(now)...)
+		// 仍然已满时淘汰最早过期的元素
+		for len(s.items) >= s.maxSize {
+			s.removeEntry(s.heap[0])
+		}
+	}
+	e := &expiringEntry[T]{element: element, expiration: expiration}
+	heap.Push(&s.heap, e)
+	s.items[element] = e
+	return expired
+}
+
+// Contains 检查元素是否存在且未过期
+func (s *ExpiringSet[T]) Contains(element T) bool {
+	s.mu.Lock()
+	e, ok := s.items[element]
+	if !ok {
+		s.mu.Unlock()
+		return false
+	}
+	if e.expiration > time.Now().UnixNano() {
+		s.mu.Unlock()
+		return true
+	}
+	s.removeEntry(e)
+	s.mu.Unlock()
+	s.notifyExpired([]T{element})
+	return false
+}
+
+// TTL 返回元素的剩余过期时间，永不过期的元素返回-1
+func (s *ExpiringSet[T]) TTL(element T) (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.items[element]
+	if !ok {
+		return 0, false
+	}
+	if e.expiration == noExpiration {
+		return -1, true
+	}
+	remaining := time.Duration(e.expiration - time.Now().UnixNano())
+	if remaining <= 0 {
+		return 0, false
+	}
+	return remaining, true
+}
+
+// Remove 移除元素
+func (s *ExpiringSet[T]) Remove(element T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.items[element]; ok {
+		s.removeEntry(e)
+	}
+}
+
+// Size 获取元素数量（可能包含已过期但尚未清理的元素）
+func (s *ExpiringSet[T]) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+// IsEmpty 判断是否为空
+func (s *ExpiringSet[T]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// ToSlice 返回所有未过期元素
+func (s *ExpiringSet[T]) ToSlice() []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UnixNano()
+	slice := make([]T, 0, len(s.items))
+	for k, e := range s.items {
+		if e.expiration > now {
+			slice = append(slice, k)
+		}
+	}
+	return slice
+}
+
+// Clear 清空集合，不触发过期回调
+func (s *ExpiringSet[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = make(map[T]*expiringEntry[T])
+	s.heap = nil
+}
+
+// Sweep 立即清理所有过期元素
+func (s *ExpiringSet[T]) Sweep() {
+	s.mu.Lock()
+	expired := s.removeExpired(time.Now().UnixNano())
+	s.mu.Unlock()
+	s.notifyExpired(expired)
+}
+
+// Close 停止后台清理，可重复调用
+func (s *ExpiringSet[T]) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// removeExpired 移除所有过期元素，调用时需持有锁
+func (s *ExpiringSet[T]) removeExpired(now int64) []T {
+	var expired []T
+	for len(s.heap) > 0 && s.heap[0].expiration <= now {
+		e := s.heap[0]
+		s.removeEntry(e)
+		expired = append(expired, e.element)
+	}
+	return expired
+}
+
+// removeEntry 移除元素，调用时需持有锁
+func (s *ExpiringSet[T]) removeEntry(e *expiringEntry[T]) {
+	heap.Remove(&s.heap, e.index)
+	delete(s.items, e.element)
+}
+
+// notifyExpired 在锁外执行过期回调
+func (s *ExpiringSet[T]) notifyExpired(expired []T) {
+	if s.onExpired == nil {
+		return
+	}
+	for _, element := range expired {
+		s.callOnExpired(element)
+	}
+}
+
+func (s *ExpiringSet[T]) callOnExpired(element T) {
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Log.Error(fmt.Sprintf("err=%v", err))
+		}
+	}()
+	s.onExpired(element)
+}
+
+// runSweeper 定期清理过期元素，直到 Close 被调用
+func (s *ExpiringSet[T]) runSweeper(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Sweep()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// expiringHeap 按过期时间排序的小顶堆，实现 heap.Interface
+type expiringHeap[T comparable] []*expiringEntry[T]
+
+func (h expiringHeap[T]) Len() int { return len(h) }
+
+func (h expiringHeap[T]) Less(i, j int) bool { return h[i].expiration < h[j].expiration }
+
+func (h expiringHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiringHeap[T]) Push(x any) {
+	e := x.(*expiringEntry[T])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiringHeap[T]) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
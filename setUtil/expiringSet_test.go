@@ -0,0 +1,235 @@
+package setUtil
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExpiringSetAddAndContains(t *testing.T) {
+	set := NewExpiringSet(&ExpiringSetOpt[string]{TTL: 100 * time.Millisecond})
+	defer set.Close()
+
+	set.Add("a")
+	set.AddWithTTL("b", 300*time.Millisecond)
+	set.AddWithTTL("c", 0) // 永不过期
+
+	if !set.Contains("a") || !set.Contains("b") || !set.Contains("c") {
+		t.Error("Expected all elements to be present")
+	}
+	if set.Size() != 3 {
+		t.Errorf("Expected size 3, got %d", set.Size())
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if set.Contains("a") {
+		t.Error("Expected a to be expired")
+	}
+	if !set.Contains("b") || !set.Contains("c") {
+		t.Error("Expected b and c to be present")
+	}
+
+	if d, ok := set.TTL("b"); !ok || d <= 0 || d > 200*time.Millisecond {
+		t.Errorf("Unexpected TTL for b: %v %v", d, ok)
+	}
+	if d, ok := set.TTL("c"); !ok || d != -1 {
+		t.Errorf("Unexpected TTL for c: %v %v", d, ok)
+	}
+	if _, ok := set.TTL("a"); ok {
+		t.Error("Expected no TTL for expired element")
+	}
+
+	set.Remove("c")
+	if set.Contains("c") {
+		t.Error("Expected c to be removed")
+	}
+}
+
+func TestExpiringSetRefresh(t *testing.T) {
+	set := NewExpiringSet(&ExpiringSetOpt[int]{TTL: 100 * time.Millisecond})
+	defer set.Close()
+
+	set.Add(1)
+	time.Sleep(60 * time.Millisecond)
+	set.Add(1) // 刷新过期时间
+	time.Sleep(60 * time.Millisecond)
+	if !set.Contains(1) {
+		t.Error("Expected refreshed element to be present")
+	}
+}
+
+func TestExpiringSetReAddExpired(t *testing.T) {
+	var mu sync.Mutex
+	var expired []int
+	set := NewExpiringSet(&ExpiringSetOpt[int]{
+		TTL:           20 * time.Millisecond,
+		SweepInterval: time.Hour,
+		OnExpired: func(element int) {
+			mu.Lock()
+			expired = append(expired, element)
+			mu.Unlock()
+		},
+	})
+	defer set.Close()
+
+	// 过期但尚未清理的元素重新添加时先触发过期回调
+	set.Add(1)
+	time.Sleep(40 * time.Millisecond)
+	set.Add(1)
+	mu.Lock()
+	if len(expired) != 1 || expired[0] != 1 {
+		t.Errorf("Expected OnExpired for 1, got %v", expired)
+	}
+	mu.Unlock()
+	if !set.Contains(1) {
+		t.Error("Expected re-added element to be present")
+	}
+
+	// 过期时间溢出时视为永不过期
+	set.AddWithTTL(2, time.Duration(math.MaxInt64))
+	if ttl, ok := set.TTL(2); !ok || ttl != -1 || !set.Contains(2) {
+		t.Errorf("Expected huge ttl to never expire, got %v, %v", ttl, ok)
+	}
+}
+
+func TestExpiringSetAddIfAbsent(t *testing.T) {
+	set := NewExpiringSet(&ExpiringSetOpt[string]{TTL: 50 * time.Millisecond})
+	defer set.Close()
+
+	if !set.AddIfAbsent("req-1") {
+		t.Error("Expected first AddIfAbsent to succeed")
+	}
+	if set.AddIfAbsent("req-1") {
+		t.Error("Expected duplicate AddIfAbsent to fail")
+	}
+	time.Sleep(80 * time.Millisecond)
+	if !set.AddIfAbsent("req-1", time.Second) {
+		t.Error("Expected AddIfAbsent to succeed after expiration")
+	}
+	if d, ok := set.TTL("req-1"); !ok || d < 900*time.Millisecond {
+		t.Errorf("Expected custom ttl, got %v", d)
+	}
+
+	// 并发去重，只有一个成功
+	var success atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if set.AddIfAbsent("req-2") {
+				success.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if success.Load() != 1 {
+		t.Errorf("Expected exactly one success, got %d", success.Load())
+	}
+}
+
+func TestExpiringSetMaxSize(t *testing.T) {
+	set := NewExpiringSet(&ExpiringSetOpt[int]{TTL: time.Minute, MaxSize: 3})
+	defer set.Close()
+
+	set.AddWithTTL(1, 3*time.Minute)
+	set.AddWithTTL(2, time.Minute)
+	set.AddWithTTL(3, 2*time.Minute)
+	set.Add(4) // 淘汰最早过期的2
+
+	if set.Size() != 3 {
+		t.Errorf("Expected size 3, got %d", set.Size())
+	}
+	if set.Contains(2) {
+		t.Error("Expected element with earliest expiration to be evicted")
+	}
+	got := set.ToSlice()
+	sort.Ints(got)
+	if len(got) != 3 || got[0] != 1 || got[1] != 3 || got[2] != 4 {
+		t.Errorf("Unexpected elements %v", got)
+	}
+
+	// 更新已存在的元素不触发淘汰
+	set.Add(1)
+	if set.Size() != 3 || !set.Contains(3) {
+		t.Error("Expected no eviction when refreshing existing element")
+	}
+}
+
+func TestExpiringSetSweeperAndCallback(t *testing.T) {
+	var mu sync.Mutex
+	var expired []string
+	set := NewExpiringSet(&ExpiringSetOpt[string]{
+		TTL:           50 * time.Millisecond,
+		SweepInterval: 20 * time.Millisecond,
+		OnExpired: func(element string) {
+			mu.Lock()
+			expired = append(expired, element)
+			mu.Unlock()
+		},
+	})
+	defer set.Close()
+
+	set.AddIfAbsent("a")
+	set.Add("b")
+	set.AddWithTTL("c", time.Minute)
+	set.Add("d")
+	set.Remove("d") // 主动移除不触发回调
+
+	time.Sleep(150 * time.Millisecond)
+
+	// 后台清理会移除过期元素，无需访问
+	if set.Size() != 1 {
+		t.Errorf("Expected size 1 after sweep, got %d", set.Size())
+	}
+	mu.Lock()
+	sort.Strings(expired)
+	if len(expired) != 2 || expired[0] != "a" || expired[1] != "b" {
+		t.Errorf("Expected callbacks for a and b, got %v", expired)
+	}
+	mu.Unlock()
+}
+
+func TestExpiringSetClose(t *testing.T) {
+	var count atomic.Int32
+	set := NewExpiringSet(&ExpiringSetOpt[int]{
+		TTL:           20 * time.Millisecond,
+		SweepInterval: 10 * time.Millisecond,
+		OnExpired: func(element int) {
+			count.Add(1)
+		},
+	})
+	set.Close()
+	set.Close() // 重复关闭无害
+
+	set.Add(1)
+	time.Sleep(50 * time.Millisecond)
+	if count.Load() != 0 {
+		t.Error("Expected no sweep after close")
+	}
+	// 关闭后仍然按需判断过期
+	if set.Contains(1) {
+		t.Error("Expected element to be expired")
+	}
+	if count.Load() != 1 {
+		t.Errorf("Expected callback on lazy expiration, got %d", count.Load())
+	}
+
+	set.Add(2)
+	set.Clear()
+	if !set.IsEmpty() {
+		t.Error("Expected empty set after clear")
+	}
+}
+
+func TestExpiringSetNilOpt(t *testing.T) {
+	set := NewExpiringSet[string](nil)
+	defer set.Close()
+	set.Add("a")
+	if d, ok := set.TTL("a"); !ok || d != -1 {
+		t.Errorf("Expected element without expiration, got %v %v", d, ok)
+	}
+}
@@ -8,10 +8,11 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-var _ bson.ValueMarshaler = (*HashSet)(nil)
-var _ bson.ValueUnmarshaler = (*HashSet)(nil)
-var _ json.Marshaler = (*HashSet)(nil)
-var _ json.Unmarshaler = (*HashSet)(nil)
+var _ ISet[int] = (*HashSet[int])(nil)
+var _ bson.ValueMarshaler = (*HashSet[int])(nil)
+var _ bson.ValueUnmarshaler = (*HashSet[int])(nil)
+var _ json.Marshaler = (*HashSet[int])(nil)
+var _ json.Unmarshaler = (*HashSet[int])(nil)
 
 // HashSet 非并发安全的哈希集合实现
 type HashSet[T comparable] struct {
@@ -83,6 +84,36 @@ func (s *HashSet[T]) IsEmpty() bool {
 	return len(s.m) == 0
 }
 
+// Union 返回并集
+func (s *HashSet[T]) Union(other ISet[T]) ISet[T] {
+	return union[T](NewHashSet[T](), s, other)
+}
+
+// Intersect 返回交集
+func (s *HashSet[T]) Intersect(other ISet[T]) ISet[T] {
+	return intersect[T](NewHashSet[T](), s, other)
+}
+
+// Difference 返回差集（存在于当前集合但不存在于other的元素）
+func (s *HashSet[T]) Difference(other ISet[T]) ISet[T] {
+	return difference[T](NewHashSet[T](), s, other)
+}
+
+// SymmetricDifference 返回对称差集（只存在于其中一个集合的元素）
+func (s *HashSet[T]) SymmetricDifference(other ISet[T]) ISet[T] {
+	return symmetricDifference[T](NewHashSet[T](), s, other)
+}
+
+// IsSubsetOf 判断当前集合是否为other的子集
+func (s *HashSet[T]) IsSubsetOf(other ISet[T]) bool {
+	return isSubsetOf[T](s, other)
+}
+
+// Equal 判断两个集合是否包含相同的元素
+func (s *HashSet[T]) Equal(other ISet[T]) bool {
+	return equal[T](s, other)
+}
+
 func (s *HashSet[T]) ToString() string {
 	bytes, err := json.Marshal(s.ToSlice())
 	if err != nil {
@@ -188,3 +188,49 @@ func TestToString(t *testing.T) {
 		}
 	}
 }
+
+func TestSetAlgebra(t *testing.T) {
+	a := NewHashSet(1, 2, 3, 4)
+	b := NewHashSet(3, 4, 5)
+
+	tests := []struct {
+		name     string
+		got      ISet[int]
+		expected []int
+	}{
+		{name: "Union", got: a.Union(b), expected: []int{1, 2, 3, 4, 5}},
+		{name: "Intersect", got: a.Intersect(b), expected: []int{3, 4}},
+		{name: "Difference", got: a.Difference(b), expected: []int{1, 2}},
+		{name: "SymmetricDifference", got: a.SymmetricDifference(b), expected: []int{1, 2, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := tt.got.(*HashSet[int]); !ok {
+				t.Errorf("Expected result type *HashSet, got %T", tt.got)
+			}
+			if !tt.got.Equal(NewHashSet(tt.expected...)) {
+				t.Errorf("Expected %v, got %v", tt.expected, tt.got.ToSlice())
+			}
+		})
+	}
+
+	// 原集合不应被修改
+	if a.Size() != 4 || b.Size() != 3 {
+		t.Errorf("Expected original sets unchanged, got %d and %d", a.Size(), b.Size())
+	}
+
+	// 与其他类型的集合运算
+	c := NewConcurrentHashSet(1, 2)
+	if !c.IsSubsetOf(a) {
+		t.Error("Expected {1, 2} to be subset of {1, 2, 3, 4}")
+	}
+	if a.IsSubsetOf(c) {
+		t.Error("Expected {1, 2, 3, 4} not to be subset of {1, 2}")
+	}
+	if !NewHashSet[int]().IsSubsetOf(c) {
+		t.Error("Expected empty set to be subset of any set")
+	}
+	if a.Equal(b) || !a.Equal(NewConcurrentHashSet(4, 3, 2, 1)) {
+		t.Error("Unexpected Equal result")
+	}
+}
@@ -0,0 +1,337 @@
+package setUtil
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"sync"
+
+	"github.com/Tomatosky/jo-util/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var _ bson.ValueMarshaler = (*HyperLogLog[int])(nil)
+var _ bson.ValueUnmarshaler = (*HyperLogLog[int])(nil)
+var _ json.Marshaler = (*HyperLogLog[int])(nil)
+var _ json.Unmarshaler = (*HyperLogLog[int])(nil)
+var _ encoding.BinaryMarshaler = (*HyperLogLog[int])(nil)
+var _ encoding.BinaryUnmarshaler = (*HyperLogLog[int])(nil)
+
+const (
+	HllMinPrecision = 4  // 最小精度
+	HllMaxPrecision = 18 // 最大精度
+
+	hllVersion      = 1 // 序列化格式版本
+	hllEncodeDense  = 0 // 稠密编码
+	hllEncodeSparse = 1 // 稀疏编码
+)
+
+// HyperLogLog 并发安全的基数估算器
+// 使用 2^precision 个寄存器，标准误差约为 1.04/sqrt(2^precision)
+// 元素较少时使用稀疏表示，只记录非零寄存器，超过 m/8 个后转为稠密表示
+type HyperLogLog[T any] struct {
+	mu        sync.RWMutex
+	p         uint8            // 精度
+	m         uint32           // 寄存器个数 2^p
+	sparse    map[uint32]uint8 // 稀疏表示，寄存器下标 -> 值
+	registers []uint8          // 稠密表示
+}
+
+// NewHyperLogLog 创建基数估算器
+// precision: 精度，取值范围 [4, 18]，常用 14（16384个寄存器，误差约0.81%）
+func NewHyperLogLog[T any](precision uint8) *HyperLogLog[T] {
+	if precision < HllMinPrecision || precision > HllMaxPrecision {
+		logger.Log.Error(fmt.Sprintf("hyperloglog precision must be between %d and %d", HllMinPrecision, HllMaxPrecision))
+		panic(fmt.Sprintf("hyperloglog precision must be between %d and %d", HllMinPrecision, HllMaxPrecision))
+	}
+	return &HyperLogLog[T]{
+		p:      precision,
+		m:      1 << precision,
+		sparse: make(map[uint32]uint8),
+	}
+}
+
+// Add 添加元素
+func (h *HyperLogLog[T]) Add(element T) {
+	idx, rank := h.position(hllHash(element))
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.set(idx, rank)
+}
+
+// AddAll 批量添加元素
+func (h *HyperLogLog[T]) AddAll(elements ...T) {
+	for _, e := range elements {
+		h.Add(e)
+	}
+}
+
+// position 计算哈希值对应的寄存器下标和值
+func (h *HyperLogLog[T]) position(hash uint64) (uint32, uint8) {
+	idx := uint32(hash >> (64 - h.p))
+	w := hash<<h.p | 1<<(h.p-1) // 保证 w 不为0，rank 最大为 64-p+1
+	return idx, uint8(bits.LeadingZeros64(w)) + 1
+}
+
+// set 更新寄存器，调用时需持有写锁
+func (h *HyperLogLog[T]) set(idx uint32, rank uint8) {
+	if h.registers != nil {
+		if rank > h.registers[idx] {
+			h.registers[idx] = rank
+		}
+		return
+	}
+	if rank > h.sparse[idx] {
+		h.sparse[idx] = rank
+		if uint32(len(h.sparse)) > h.m/8 {
+			h.toDense()
+		}
+	}
+}
+
+// toDense 转换为稠密表示，调用时需持有写锁
+func (h *HyperLogLog[T]) toDense() {
+	h.registers = make([]uint8, h.m)
+	for idx, rank := range h.sparse {
+		h.registers[idx] = rank
+	}
+	h.sparse = nil
+}
+
+// Count 估算不同元素的数量
+func (h *HyperLogLog[T]) Count() uint64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	m := float64(h.m)
+	zeros := 0
+	sum := 0.0
+	if h.registers == nil {
+		zeros = int(h.m) - len(h.sparse)
+		sum = float64(zeros)
+		for _, rank := range h.sparse {
+			sum += math.Ldexp(1, -int(rank))
+		}
+	} else {
+		for _, rank := range h.registers {
+			if rank == 0 {
+				zeros++
+			}
+			sum += math.Ldexp(1, -int(rank))
+		}
+	}
+
+	estimate := hllAlpha(h.m) * m * m / sum
+	// 小基数时使用线性计数修正
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(math.Round(estimate))
+}
+
+// Merge 合并另一个估算器，两者的精度必须相同
+func (h *HyperLogLog[T]) Merge(other *HyperLogLog[T]) error {
+	other.mu.RLock()
+	p := other.p
+	var sparse map[uint32]uint8
+	var registers []uint8
+	if other.registers == nil {
+		sparse = make(map[uint32]uint8, len(other.sparse))
+		for idx, rank := range other.sparse {
+			sparse[idx] = rank
+		}
+	} else {
+		registers = append([]uint8(nil), other.registers...)
+	}
+	other.mu.RUnlock()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.p != p {
+		return fmt.Errorf("hyperloglog precision mismatch: %d, other %d", h.p, p)
+	}
+	if registers != nil {
+		if h.registers == nil {
+			h.toDense()
+		}
+		for idx, rank := range registers {
+			if rank > h.registers[idx] {
+				h.registers[idx] = rank
+			}
+		}
+		return nil
+	}
+	for idx, rank := range sparse {
+		h.set(idx, rank)
+	}
+	return nil
+}
+
+// Precision 精度
+func (h *HyperLogLog[T]) Precision() uint8 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.p
+}
+
+// IsSparse 是否为稀疏表示
+func (h *HyperLogLog[T]) IsSparse() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.registers == nil
+}
+
+// Clear 清空估算器
+func (h *HyperLogLog[T]) Clear() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sparse = make(map[uint32]uint8)
+	h.registers = nil
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler 接口
+// 格式: 版本(1字节) + 精度(1字节) + 编码(1字节) + 数据
+// 稠密编码的数据为全部寄存器，稀疏编码的数据为按下标排序的 下标(4字节) + 值(1字节)
+func (h *HyperLogLog[T]) MarshalBinary() ([]byte, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.registers != nil {
+		buf := make([]byte, 3, 3+len(h.registers))
+		buf[0], buf[1], buf[2] = hllVersion, h.p, hllEncodeDense
+		return append(buf, h.registers...), nil
+	}
+
+	indexes := make([]uint32, 0, len(h.sparse))
+	for idx := range h.sparse {
+		indexes = append(indexes, idx)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i] < indexes[j]
+	})
+	buf := make([]byte, 3+5*len(indexes))
+	buf[0], buf[1], buf[2] = hllVersion, h.p, hllEncodeSparse
+	for i, idx := range indexes {
+		binary.BigEndian.PutUint32(buf[3+5*i:], idx)
+		buf[3+5*i+4] = h.sparse[idx]
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler 接口
+func (h *HyperLogLog[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 3 || data[0] != hllVersion {
+		return errors.New("invalid hyperloglog data")
+	}
+	p := data[1]
+	if p < HllMinPrecision || p > HllMaxPrecision {
+		return errors.New("invalid hyperloglog precision")
+	}
+	m := uint32(1) << p
+	maxRank := 64 - p + 1
+	payload := data[3:]
+
+	var sparse map[uint32]uint8
+	var registers []uint8
+	switch data[2] {
+	case hllEncodeDense:
+		if uint32(len(payload)) != m {
+			return errors.New("invalid hyperloglog data")
+		}
+		for _, rank := range payload {
+			if rank > maxRank {
+				return errors.New("invalid hyperloglog data")
+			}
+		}
+		registers = append([]uint8(nil), payload...)
+	case hllEncodeSparse:
+		if len(payload)%5 != 0 {
+			return errors.New("invalid hyperloglog data")
+		}
+		sparse = make(map[uint32]uint8, len(payload)/5)
+		for i := 0; i < len(payload); i += 5 {
+			idx := binary.BigEndian.Uint32(payload[i:])
+			rank := payload[i+4]
+			if idx >= m || rank > maxRank {
+				return errors.New("invalid hyperloglog data")
+			}
+			sparse[idx] = rank
+		}
+	default:
+		return errors.New("invalid hyperloglog encoding")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.p = p
+	h.m = m
+	h.sparse = sparse
+	h.registers = registers
+	return nil
+}
+
+// MarshalJSON 实现 json.Marshaler 接口，输出为二进制数据的 base64 字符串
+func (h *HyperLogLog[T]) MarshalJSON() ([]byte, error) {
+	data, err := h.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+func (h *HyperLogLog[T]) UnmarshalJSON(data []byte) error {
+	var raw []byte
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	return h.UnmarshalBinary(raw)
+}
+
+func (h *HyperLogLog[T]) MarshalBSONValue() (byte, []byte, error) {
+	data, err := h.MarshalBinary()
+	if err != nil {
+		return 0, nil, err
+	}
+	typ, bytes, err := bson.MarshalValue(data)
+	return byte(typ), bytes, err
+}
+
+func (h *HyperLogLog[T]) UnmarshalBSONValue(t byte, data []byte) error {
+	var raw []byte
+	if err := bson.UnmarshalValue(bson.Type(t), data, &raw); err != nil {
+		return err
+	}
+	return h.UnmarshalBinary(raw)
+}
+
+// hllAlpha 偏差修正常数
+func hllAlpha(m uint32) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
+
+// hllHash 计算元素的64位哈希值，使用 fmix64 打散以保证高位分布均匀
+func hllHash(element any) uint64 {
+	h1, h2 := bloomHash(element)
+	k := h1 ^ h2
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
@@ -0,0 +1,205 @@
+package setUtil
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func relativeError(got uint64, want int) float64 {
+	return math.Abs(float64(got)-float64(want)) / float64(want)
+}
+
+func TestNewHyperLogLog(t *testing.T) {
+	hll := NewHyperLogLog[string](14)
+	if hll.Precision() != 14 || !hll.IsSparse() || hll.Count() != 0 {
+		t.Errorf("Unexpected new hyperloglog: p=%d sparse=%v count=%d", hll.Precision(), hll.IsSparse(), hll.Count())
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for invalid precision")
+		}
+	}()
+	NewHyperLogLog[string](3)
+}
+
+func TestHyperLogLogCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision uint8
+		n         int
+		maxError  float64
+	}{
+		{name: "small cardinality", precision: 14, n: 100, maxError: 0.02},
+		{name: "medium cardinality", precision: 14, n: 10000, maxError: 0.03},
+		{name: "large cardinality", precision: 14, n: 200000, maxError: 0.03},
+		{name: "low precision", precision: 10, n: 50000, maxError: 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hll := NewHyperLogLog[string](tt.precision)
+			for i := 0; i < tt.n; i++ {
+				hll.Add(fmt.Sprintf("user-%d", i))
+				hll.Add(fmt.Sprintf("user-%d", i)) // 重复元素不影响计数
+			}
+			if e := relativeError(hll.Count(), tt.n); e > tt.maxError {
+				t.Errorf("Count() = %d, want about %d (error %.4f)", hll.Count(), tt.n, e)
+			}
+		})
+	}
+}
+
+func TestHyperLogLogSparseToDense(t *testing.T) {
+	hll := NewHyperLogLog[int](10)
+	for i := 0; i < 50; i++ {
+		hll.Add(i)
+	}
+	if !hll.IsSparse() {
+		t.Error("Expected sparse representation for small cardinality")
+	}
+	sparseCount := hll.Count()
+
+	for i := 50; i < 5000; i++ {
+		hll.Add(i)
+	}
+	if hll.IsSparse() {
+		t.Error("Expected dense representation for large cardinality")
+	}
+	if e := relativeError(sparseCount, 50); e > 0.05 {
+		t.Errorf("Sparse Count() = %d, want about 50", sparseCount)
+	}
+
+	hll.Clear()
+	if !hll.IsSparse() || hll.Count() != 0 {
+		t.Error("Expected empty sparse hyperloglog after clear")
+	}
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	// 模拟两个服务器，用户有一半重合
+	server1 := NewHyperLogLog[int](14)
+	server2 := NewHyperLogLog[int](14)
+	for i := 0; i < 20000; i++ {
+		server1.Add(i)
+	}
+	for i := 10000; i < 30000; i++ {
+		server2.Add(i)
+	}
+	if err := server1.Merge(server2); err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	if e := relativeError(server1.Count(), 30000); e > 0.03 {
+		t.Errorf("Merged Count() = %d, want about 30000", server1.Count())
+	}
+
+	// 稀疏与稀疏合并
+	a := NewHyperLogLog[int](14)
+	b := NewHyperLogLog[int](14)
+	a.AddAll(1, 2, 3)
+	b.AddAll(3, 4, 5)
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	if !a.IsSparse() || a.Count() != 5 {
+		t.Errorf("Expected sparse merged count 5, got sparse=%v count=%d", a.IsSparse(), a.Count())
+	}
+
+	// 稀疏与稠密合并
+	if err := a.Merge(server2); err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	if a.IsSparse() {
+		t.Error("Expected dense after merging dense sketch")
+	}
+
+	if err := a.Merge(NewHyperLogLog[int](12)); err == nil {
+		t.Error("Expected error when merging different precision")
+	}
+}
+
+func TestHyperLogLogSerialization(t *testing.T) {
+	sparse := NewHyperLogLog[string](12)
+	sparse.AddAll("a", "b", "c")
+	dense := NewHyperLogLog[string](12)
+	for i := 0; i < 10000; i++ {
+		dense.Add(fmt.Sprintf("user-%d", i))
+	}
+
+	for _, hll := range []*HyperLogLog[string]{sparse, dense} {
+		data, err := hll.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary error: %v", err)
+		}
+		var fromBinary HyperLogLog[string]
+		if err = fromBinary.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary error: %v", err)
+		}
+		if fromBinary.Count() != hll.Count() || fromBinary.IsSparse() != hll.IsSparse() {
+			t.Errorf("Binary round trip mismatch: %d vs %d", fromBinary.Count(), hll.Count())
+		}
+
+		data, err = json.Marshal(hll)
+		if err != nil {
+			t.Fatalf("MarshalJSON error: %v", err)
+		}
+		var fromJSON HyperLogLog[string]
+		if err = json.Unmarshal(data, &fromJSON); err != nil {
+			t.Fatalf("UnmarshalJSON error: %v", err)
+		}
+		if fromJSON.Count() != hll.Count() {
+			t.Errorf("JSON round trip mismatch: %d vs %d", fromJSON.Count(), hll.Count())
+		}
+
+		type doc struct {
+			Day string               `bson:"day"`
+			HLL *HyperLogLog[string] `bson:"hll"`
+		}
+		data, err = bson.Marshal(doc{Day: "2024-01-01", HLL: hll})
+		if err != nil {
+			t.Fatalf("MarshalBSON error: %v", err)
+		}
+		var fromBSON doc
+		if err = bson.Unmarshal(data, &fromBSON); err != nil {
+			t.Fatalf("UnmarshalBSON error: %v", err)
+		}
+		if fromBSON.HLL.Count() != hll.Count() {
+			t.Errorf("BSON round trip mismatch: %d vs %d", fromBSON.HLL.Count(), hll.Count())
+		}
+	}
+
+	// 稀疏编码比稠密编码更小
+	sparseData, _ := sparse.MarshalBinary()
+	denseData, _ := dense.MarshalBinary()
+	if len(sparseData) >= len(denseData) {
+		t.Errorf("Expected sparse encoding to be smaller: %d vs %d", len(sparseData), len(denseData))
+	}
+
+	var invalid HyperLogLog[string]
+	if err := invalid.UnmarshalBinary([]byte{1, 12, 0, 1}); err == nil {
+		t.Error("Expected error for invalid data")
+	}
+}
+
+func TestHyperLogLogConcurrent(t *testing.T) {
+	hll := NewHyperLogLog[int](14)
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func(base int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				hll.Add(base*1000 + i)
+				hll.Count()
+			}
+		}(g)
+	}
+	wg.Wait()
+	if e := relativeError(hll.Count(), 10000); e > 0.03 {
+		t.Errorf("Count() = %d, want about 10000", hll.Count())
+	}
+}
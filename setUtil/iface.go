@@ -0,0 +1,22 @@
+package setUtil
+
+type ISet[T comparable] interface {
+	Add(element T)
+	AddAll(elements ...T)
+	Remove(element T)
+	Contains(element T) bool
+	Size() int
+	Clear()
+	Range(f func(T) bool)
+	ToSlice() []T
+	IsEmpty() bool
+	ToString() string
+	Union(other ISet[T]) ISet[T]
+	Intersect(other ISet[T]) ISet[T]
+	Difference(other ISet[T]) ISet[T]
+	SymmetricDifference(other ISet[T]) ISet[T]
+	IsSubsetOf(other ISet[T]) bool
+	Equal(other ISet[T]) bool
+	MarshalJSON() ([]byte, error)
+	UnmarshalJSON(data []byte) error
+}
@@ -0,0 +1,182 @@
+package setUtil
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+
+	"github.com/Tomatosky/jo-util/logger"
+	"github.com/Tomatosky/jo-util/mapUtil"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var _ ISet[int] = (*LinkedHashSet[int])(nil)
+var _ bson.ValueMarshaler = (*LinkedHashSet[int])(nil)
+var _ bson.ValueUnmarshaler = (*LinkedHashSet[int])(nil)
+var _ json.Marshaler = (*LinkedHashSet[int])(nil)
+var _ json.Unmarshaler = (*LinkedHashSet[int])(nil)
+
+// LinkedHashSet 保持插入顺序的集合，非并发安全
+type LinkedHashSet[T comparable] struct {
+	m *mapUtil.OrderedMap[T, struct{}] // 使用空结构体作为值类型
+}
+
+// NewLinkedHashSet 构造函数
+func NewLinkedHashSet[T comparable](elements ...T) *LinkedHashSet[T] {
+	set := &LinkedHashSet[T]{
+		m: mapUtil.NewOrderedMapWithCapacity[T, struct{}](len(elements)),
+	}
+	set.AddAll(elements...)
+	return set
+}
+
+// Add 添加元素，已存在的元素保持原有位置
+func (s *LinkedHashSet[T]) Add(element T) {
+	s.m.PutIfAbsent(element, struct{}{})
+}
+
+// AddAll 批量添加元素
+func (s *LinkedHashSet[T]) AddAll(elements ...T) {
+	for _, e := range elements {
+		s.Add(e)
+	}
+}
+
+// Remove 移除元素
+func (s *LinkedHashSet[T]) Remove(element T) {
+	s.m.Remove(element)
+}
+
+// Contains 检查元素存在性
+func (s *LinkedHashSet[T]) Contains(element T) bool {
+	return s.m.ContainsKey(element)
+}
+
+// Size 获取元素数量
+func (s *LinkedHashSet[T]) Size() int {
+	return s.m.Size()
+}
+
+// Clear 清空集合
+func (s *LinkedHashSet[T]) Clear() {
+	s.m.Clear()
+}
+
+// Range 按插入顺序遍历元素（返回false可提前终止）
+func (s *LinkedHashSet[T]) Range(f func(T) bool) {
+	s.m.Range(func(key T, value struct{}) bool {
+		return f(key)
+	})
+}
+
+// All 返回按插入顺序遍历的迭代器
+func (s *LinkedHashSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for k := range s.m.AllFromFront() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice 按插入顺序转换为切片
+func (s *LinkedHashSet[T]) ToSlice() []T {
+	return s.m.Keys()
+}
+
+// IsEmpty 判断是否为空
+func (s *LinkedHashSet[T]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// First 返回最早插入的元素
+func (s *LinkedHashSet[T]) First() (T, bool) {
+	el := s.m.Front()
+	if el == nil {
+		var zero T
+		return zero, false
+	}
+	return el.Key, true
+}
+
+// Last 返回最后插入的元素
+func (s *LinkedHashSet[T]) Last() (T, bool) {
+	el := s.m.Back()
+	if el == nil {
+		var zero T
+		return zero, false
+	}
+	return el.Key, true
+}
+
+// Union 返回并集，先保持当前集合的顺序，再追加other中的新元素
+func (s *LinkedHashSet[T]) Union(other ISet[T]) ISet[T] {
+	return union[T](NewLinkedHashSet[T](), s, other)
+}
+
+// Intersect 返回交集，保持当前集合的顺序
+func (s *LinkedHashSet[T]) Intersect(other ISet[T]) ISet[T] {
+	return intersect[T](NewLinkedHashSet[T](), s, other)
+}
+
+// Difference 返回差集（存在于当前集合但不存在于other的元素）
+func (s *LinkedHashSet[T]) Difference(other ISet[T]) ISet[T] {
+	return difference[T](NewLinkedHashSet[T](), s, other)
+}
+
+// SymmetricDifference 返回对称差集（只存在于其中一个集合的元素）
+func (s *LinkedHashSet[T]) SymmetricDifference(other ISet[T]) ISet[T] {
+	return symmetricDifference[T](NewLinkedHashSet[T](), s, other)
+}
+
+// IsSubsetOf 判断当前集合是否为other的子集
+func (s *LinkedHashSet[T]) IsSubsetOf(other ISet[T]) bool {
+	return isSubsetOf[T](s, other)
+}
+
+// Equal 判断两个集合是否包含相同的元素（不比较顺序）
+func (s *LinkedHashSet[T]) Equal(other ISet[T]) bool {
+	return equal[T](s, other)
+}
+
+func (s *LinkedHashSet[T]) ToString() string {
+	bytes, err := json.Marshal(s.ToSlice())
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("%v", err))
+		panic(err)
+	}
+	return string(bytes)
+}
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (s *LinkedHashSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+func (s *LinkedHashSet[T]) UnmarshalJSON(data []byte) error {
+	var elements []T
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+	s.m = mapUtil.NewOrderedMapWithCapacity[T, struct{}](len(elements))
+	s.AddAll(elements...)
+	return nil
+}
+
+func (s *LinkedHashSet[T]) MarshalBSONValue() (byte, []byte, error) {
+	elements := s.ToSlice()
+	typ, data, err := bson.MarshalValue(elements)
+	return byte(typ), data, err
+}
+
+func (s *LinkedHashSet[T]) UnmarshalBSONValue(t byte, data []byte) error {
+	var elements []T
+	if err := bson.UnmarshalValue(bson.Type(t), data, &elements); err != nil {
+		return err
+	}
+	s.m = mapUtil.NewOrderedMapWithCapacity[T, struct{}](len(elements))
+	s.AddAll(elements...)
+	return nil
+}
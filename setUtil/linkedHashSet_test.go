@@ -0,0 +1,121 @@
+package setUtil
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestNewLinkedHashSet(t *testing.T) {
+	set := NewLinkedHashSet("c", "a", "b", "a")
+	if set.Size() != 3 {
+		t.Errorf("Expected set size 3, got %d", set.Size())
+	}
+	if !reflect.DeepEqual(set.ToSlice(), []string{"c", "a", "b"}) {
+		t.Errorf("Expected insertion order [c a b], got %v", set.ToSlice())
+	}
+}
+
+func TestLinkedHashSetOrder(t *testing.T) {
+	set := NewLinkedHashSet[int]()
+	set.AddAll(3, 1, 2)
+
+	// 重复添加不改变顺序
+	set.Add(3)
+	if !reflect.DeepEqual(set.ToSlice(), []int{3, 1, 2}) {
+		t.Errorf("Expected [3 1 2], got %v", set.ToSlice())
+	}
+
+	set.Remove(1)
+	set.Add(1)
+	if !reflect.DeepEqual(set.ToSlice(), []int{3, 2, 1}) {
+		t.Errorf("Expected [3 2 1] after re-adding 1, got %v", set.ToSlice())
+	}
+
+	if v, ok := set.First(); !ok || v != 3 {
+		t.Errorf("First() = %d, %v", v, ok)
+	}
+	if v, ok := set.Last(); !ok || v != 1 {
+		t.Errorf("Last() = %d, %v", v, ok)
+	}
+
+	var iterated []int
+	for v := range set.All() {
+		iterated = append(iterated, v)
+		if len(iterated) == 2 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(iterated, []int{3, 2}) {
+		t.Errorf("Expected All() to yield [3 2], got %v", iterated)
+	}
+
+	set.Clear()
+	if !set.IsEmpty() {
+		t.Error("Expected empty set after clear")
+	}
+	if _, ok := set.First(); ok {
+		t.Error("First() on empty set should return false")
+	}
+}
+
+func TestLinkedHashSetAlgebra(t *testing.T) {
+	a := NewLinkedHashSet(4, 3, 2, 1)
+	b := NewHashSet(1, 2, 5)
+
+	union := a.Union(b)
+	if _, ok := union.(*LinkedHashSet[int]); !ok {
+		t.Errorf("Expected result type *LinkedHashSet, got %T", union)
+	}
+	if got := union.ToSlice(); !reflect.DeepEqual(got[:4], []int{4, 3, 2, 1}) || got[4] != 5 {
+		t.Errorf("Unexpected union order: %v", got)
+	}
+	if got := a.Intersect(b).ToSlice(); !reflect.DeepEqual(got, []int{2, 1}) {
+		t.Errorf("Unexpected intersect: %v", got)
+	}
+	if got := a.Difference(b).ToSlice(); !reflect.DeepEqual(got, []int{4, 3}) {
+		t.Errorf("Unexpected difference: %v", got)
+	}
+	if got := a.SymmetricDifference(b).ToSlice(); !reflect.DeepEqual(got, []int{4, 3, 5}) {
+		t.Errorf("Unexpected symmetric difference: %v", got)
+	}
+	if !NewLinkedHashSet(1, 2).IsSubsetOf(a) || !a.Equal(NewLinkedHashSet(1, 2, 3, 4)) {
+		t.Error("Unexpected IsSubsetOf/Equal result")
+	}
+}
+
+func TestLinkedHashSetSerialization(t *testing.T) {
+	set := NewLinkedHashSet("b", "a", "c")
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("MarshalJSON error: %v", err)
+	}
+	if string(data) != `["b","a","c"]` {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+
+	var decoded LinkedHashSet[string]
+	if err = json.Unmarshal([]byte(`["z","x","y","x"]`), &decoded); err != nil {
+		t.Fatalf("UnmarshalJSON error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.ToSlice(), []string{"z", "x", "y"}) {
+		t.Errorf("Unexpected decoded order: %v", decoded.ToSlice())
+	}
+
+	type doc struct {
+		Set *LinkedHashSet[string] `bson:"set"`
+	}
+	bytes, err := bson.Marshal(doc{Set: set})
+	if err != nil {
+		t.Fatalf("MarshalBSON error: %v", err)
+	}
+	var out doc
+	if err = bson.Unmarshal(bytes, &out); err != nil {
+		t.Fatalf("UnmarshalBSON error: %v", err)
+	}
+	if !reflect.DeepEqual(out.Set.ToSlice(), []string{"b", "a", "c"}) {
+		t.Errorf("Unexpected BSON decoded order: %v", out.Set.ToSlice())
+	}
+}
@@ -0,0 +1,62 @@
+package setUtil
+
+// union 将 a 和 b 的所有元素加入 result
+func union[T comparable](result, a, b ISet[T]) ISet[T] {
+	result.AddAll(a.ToSlice()...)
+	result.AddAll(b.ToSlice()...)
+	return result
+}
+
+// intersect 将同时存在于 a 和 b 的元素加入 result
+func intersect[T comparable](result, a, b ISet[T]) ISet[T] {
+	for _, e := range a.ToSlice() {
+		if b.Contains(e) {
+			result.Add(e)
+		}
+	}
+	return result
+}
+
+// difference 将存在于 a 但不存在于 b 的元素加入 result
+func difference[T comparable](result, a, b ISet[T]) ISet[T] {
+	for _, e := range a.ToSlice() {
+		if !b.Contains(e) {
+			result.Add(e)
+		}
+	}
+	return result
+}
+
+// symmetricDifference 将只存在于 a 或 b 其中之一的元素加入 result
+func symmetricDifference[T comparable](result, a, b ISet[T]) ISet[T] {
+	difference(result, a, b)
+	return difference(result, b, a)
+}
+
+// isSubsetOf 判断 a 是否为 b 的子集
+func isSubsetOf[T comparable](a, b ISet[T]) bool {
+	elements := a.ToSlice()
+	if len(elements) > b.Size() {
+		return false
+	}
+	for _, e := range elements {
+		if !b.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// equal 判断 a 和 b 是否包含相同的元素
+func equal[T comparable](a, b ISet[T]) bool {
+	elements := a.ToSlice()
+	if len(elements) != b.Size() {
+		return false
+	}
+	for _, e := range elements {
+		if !b.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+package setUtil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Tomatosky/jo-util/logger"
+	"github.com/Tomatosky/jo-util/mapUtil"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var _ ISet[int] = (*TreeSet[int])(nil)
+var _ bson.ValueMarshaler = (*TreeSet[int])(nil)
+var _ bson.ValueUnmarshaler = (*TreeSet[int])(nil)
+var _ json.Marshaler = (*TreeSet[int])(nil)
+var _ json.Unmarshaler = (*TreeSet[int])(nil)
+
+// TreeSet 基于 TreeMap 实现的并发安全有序集合
+type TreeSet[T comparable] struct {
+	m    *mapUtil.TreeMap[T, struct{}] // 使用空结构体作为值类型
+	less func(a, b T) bool             // 元素比较函数
+}
+
+// NewTreeSet 构造函数
+// less: 用于比较元素的函数，确定元素的顺序
+func NewTreeSet[T comparable](less func(a, b T) bool, elements ...T) *TreeSet[T] {
+	set := &TreeSet[T]{
+		m:    mapUtil.NewTreeMap[T, struct{}](less),
+		less: less,
+	}
+	set.AddAll(elements...)
+	return set
+}
+
+// Add 添加元素
+func (s *TreeSet[T]) Add(element T) {
+	s.m.Put(element, struct{}{})
+}
+
+// AddAll 批量添加元素
+func (s *TreeSet[T]) AddAll(elements ...T) {
+	for _, e := range elements {
+		s.Add(e)
+	}
+}
+
+// Remove 移除元素
+func (s *TreeSet[T]) Remove(element T) {
+	s.m.Remove(element)
+}
+
+// Contains 检查元素存在性
+func (s *TreeSet[T]) Contains(element T) bool {
+	return s.m.ContainsKey(element)
+}
+
+// Size 获取元素数量
+func (s *TreeSet[T]) Size() int {
+	return s.m.Size()
+}
+
+// Clear 清空集合
+func (s *TreeSet[T]) Clear() {
+	s.m.Clear()
+}
+
+// Range 按升序遍历元素（返回false可提前终止）
+func (s *TreeSet[T]) Range(f func(T) bool) {
+	s.m.Range(func(key T, value struct{}) bool {
+		return f(key)
+	})
+}
+
+// ToSlice 转换为升序切片
+func (s *TreeSet[T]) ToSlice() []T {
+	return s.m.Keys()
+}
+
+// IsEmpty 判断是否为空
+func (s *TreeSet[T]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// First 返回最小元素
+func (s *TreeSet[T]) First() (T, bool) {
+	return s.m.FirstKey()
+}
+
+// Last 返回最大元素
+func (s *TreeSet[T]) Last() (T, bool) {
+	return s.m.LastKey()
+}
+
+// Floor 返回小于等于给定元素的最大元素
+func (s *TreeSet[T]) Floor(element T) (T, bool) {
+	return s.m.FloorKey(element)
+}
+
+// Lower 返回严格小于给定元素的最大元素
+func (s *TreeSet[T]) Lower(element T) (T, bool) {
+	return s.m.LowerKey(element)
+}
+
+// Ceiling 返回大于等于给定元素的最小元素
+func (s *TreeSet[T]) Ceiling(element T) (T, bool) {
+	return s.m.CeilingKey(element)
+}
+
+// Higher 返回严格大于给定元素的最小元素
+func (s *TreeSet[T]) Higher(element T) (T, bool) {
+	return s.m.HigherKey(element)
+}
+
+// HeadSet 返回所有小于to的元素组成的新集合
+func (s *TreeSet[T]) HeadSet(to T) *TreeSet[T] {
+	result := NewTreeSet[T](s.less)
+	s.m.Range(func(key T, value struct{}) bool {
+		if !s.less(key, to) {
+			return false
+		}
+		result.Add(key)
+		return true
+	})
+	return result
+}
+
+// TailSet 返回所有大于等于from的元素组成的新集合
+func (s *TreeSet[T]) TailSet(from T) *TreeSet[T] {
+	result := NewTreeSet[T](s.less)
+	s.m.RangeFrom(from, func(key T, value struct{}) bool {
+		result.Add(key)
+		return true
+	})
+	return result
+}
+
+// SubSet 返回所有位于[from, to)区间的元素组成的新集合
+func (s *TreeSet[T]) SubSet(from, to T) *TreeSet[T] {
+	result := NewTreeSet[T](s.less)
+	s.m.RangeFrom(from, func(key T, value struct{}) bool {
+		if !s.less(key, to) {
+			return false
+		}
+		result.Add(key)
+		return true
+	})
+	return result
+}
+
+// Union 返回并集
+func (s *TreeSet[T]) Union(other ISet[T]) ISet[T] {
+	return union[T](NewTreeSet[T](s.less), s, other)
+}
+
+// Intersect 返回交集
+func (s *TreeSet[T]) Intersect(other ISet[T]) ISet[T] {
+	return intersect[T](NewTreeSet[T](s.less), s, other)
+}
+
+// Difference 返回差集（存在于当前集合但不存在于other的元素）
+func (s *TreeSet[T]) Difference(other ISet[T]) ISet[T] {
+	return difference[T](NewTreeSet[T](s.less), s, other)
+}
+
+// SymmetricDifference 返回对称差集（只存在于其中一个集合的元素）
+func (s *TreeSet[T]) SymmetricDifference(other ISet[T]) ISet[T] {
+	return symmetricDifference[T](NewTreeSet[T](s.less), s, other)
+}
+
+// IsSubsetOf 判断当前集合是否为other的子集
+func (s *TreeSet[T]) IsSubsetOf(other ISet[T]) bool {
+	return isSubsetOf[T](s, other)
+}
+
+// Equal 判断两个集合是否包含相同的元素
+func (s *TreeSet[T]) Equal(other ISet[T]) bool {
+	return equal[T](s, other)
+}
+
+func (s *TreeSet[T]) ToString() string {
+	bytes, err := json.Marshal(s.ToSlice())
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("%v", err))
+		panic(err)
+	}
+	return string(bytes)
+}
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (s *TreeSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口，需先通过 NewTreeSet 指定比较函数
+func (s *TreeSet[T]) UnmarshalJSON(data []byte) error {
+	if s.less == nil {
+		return errors.New("TreeSet less function is nil, create it with NewTreeSet first")
+	}
+	var elements []T
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+	s.m = mapUtil.NewTreeMap[T, struct{}](s.less)
+	s.AddAll(elements...)
+	return nil
+}
+
+func (s *TreeSet[T]) MarshalBSONValue() (byte, []byte, error) {
+	elements := s.ToSlice()
+	typ, data, err := bson.MarshalValue(elements)
+	return byte(typ), data, err
+}
+
+// UnmarshalBSONValue 实现 bson.ValueUnmarshaler 接口，需先通过 NewTreeSet 指定比较函数
+func (s *TreeSet[T]) UnmarshalBSONValue(t byte, data []byte) error {
+	if s.less == nil {
+		return errors.New("TreeSet less function is nil, create it with NewTreeSet first")
+	}
+	var elements []T
+	if err := bson.UnmarshalValue(bson.Type(t), data, &elements); err != nil {
+		return err
+	}
+	s.m = mapUtil.NewTreeMap[T, struct{}](s.less)
+	s.AddAll(elements...)
+	return nil
+}
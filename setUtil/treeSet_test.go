@@ -0,0 +1,198 @@
+package setUtil
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func intLess(a, b int) bool {
+	return a < b
+}
+
+func TestNewTreeSet(t *testing.T) {
+	set := NewTreeSet(intLess, 5, 1, 3, 1)
+	if set.Size() != 3 {
+		t.Errorf("Expected set size 3, got %d", set.Size())
+	}
+	if !reflect.DeepEqual(set.ToSlice(), []int{1, 3, 5}) {
+		t.Errorf("Expected sorted slice [1 3 5], got %v", set.ToSlice())
+	}
+
+	// 降序
+	desc := NewTreeSet(func(a, b int) bool { return a > b }, 1, 2, 3)
+	if !reflect.DeepEqual(desc.ToSlice(), []int{3, 2, 1}) {
+		t.Errorf("Expected sorted slice [3 2 1], got %v", desc.ToSlice())
+	}
+}
+
+func TestTreeSetBasic(t *testing.T) {
+	set := NewTreeSet[int](intLess)
+	if !set.IsEmpty() {
+		t.Error("Expected empty set")
+	}
+	set.AddAll(3, 1, 2)
+	if !set.Contains(2) || set.Contains(4) {
+		t.Error("Unexpected Contains result")
+	}
+	set.Remove(2)
+	if set.Contains(2) || set.Size() != 2 {
+		t.Errorf("Expected 2 removed, got %v", set.ToSlice())
+	}
+
+	var ranged []int
+	set.Range(func(v int) bool {
+		ranged = append(ranged, v)
+		return true
+	})
+	if !reflect.DeepEqual(ranged, []int{1, 3}) {
+		t.Errorf("Expected Range in order [1 3], got %v", ranged)
+	}
+
+	set.Clear()
+	if set.Size() != 0 {
+		t.Errorf("Expected size 0 after clear, got %d", set.Size())
+	}
+}
+
+func TestTreeSetNavigable(t *testing.T) {
+	set := NewTreeSet(intLess, 10, 20, 30, 40, 50)
+
+	if v, ok := set.First(); !ok || v != 10 {
+		t.Errorf("First() = %d, %v", v, ok)
+	}
+	if v, ok := set.Last(); !ok || v != 50 {
+		t.Errorf("Last() = %d, %v", v, ok)
+	}
+	if v, ok := set.Floor(25); !ok || v != 20 {
+		t.Errorf("Floor(25) = %d, %v", v, ok)
+	}
+	if v, ok := set.Floor(20); !ok || v != 20 {
+		t.Errorf("Floor(20) = %d, %v", v, ok)
+	}
+	if _, ok := set.Floor(5); ok {
+		t.Error("Floor(5) should not exist")
+	}
+	if v, ok := set.Lower(20); !ok || v != 10 {
+		t.Errorf("Lower(20) = %d, %v", v, ok)
+	}
+	if v, ok := set.Ceiling(25); !ok || v != 30 {
+		t.Errorf("Ceiling(25) = %d, %v", v, ok)
+	}
+	if _, ok := set.Ceiling(55); ok {
+		t.Error("Ceiling(55) should not exist")
+	}
+	if v, ok := set.Higher(30); !ok || v != 40 {
+		t.Errorf("Higher(30) = %d, %v", v, ok)
+	}
+
+	if got := set.HeadSet(30).ToSlice(); !reflect.DeepEqual(got, []int{10, 20}) {
+		t.Errorf("HeadSet(30) = %v", got)
+	}
+	if got := set.TailSet(30).ToSlice(); !reflect.DeepEqual(got, []int{30, 40, 50}) {
+		t.Errorf("TailSet(30) = %v", got)
+	}
+	if got := set.SubSet(15, 45).ToSlice(); !reflect.DeepEqual(got, []int{20, 30, 40}) {
+		t.Errorf("SubSet(15, 45) = %v", got)
+	}
+	if got := set.SubSet(60, 70).ToSlice(); len(got) != 0 {
+		t.Errorf("SubSet(60, 70) = %v", got)
+	}
+
+	empty := NewTreeSet[int](intLess)
+	if _, ok := empty.First(); ok {
+		t.Error("First() on empty set should return false")
+	}
+}
+
+func TestTreeSetAlgebra(t *testing.T) {
+	a := NewTreeSet(intLess, 5, 4, 3, 2, 1)
+	b := NewHashSet(4, 5, 6)
+
+	union := a.Union(b)
+	if ts, ok := union.(*TreeSet[int]); !ok || !reflect.DeepEqual(ts.ToSlice(), []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("Unexpected union: %T %v", union, union.ToSlice())
+	}
+	if got := a.Intersect(b).ToSlice(); !reflect.DeepEqual(got, []int{4, 5}) {
+		t.Errorf("Unexpected intersect: %v", got)
+	}
+	if got := a.Difference(b).ToSlice(); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Unexpected difference: %v", got)
+	}
+	if got := a.SymmetricDifference(b).ToSlice(); !reflect.DeepEqual(got, []int{1, 2, 3, 6}) {
+		t.Errorf("Unexpected symmetric difference: %v", got)
+	}
+	if !a.HeadSet(3).IsSubsetOf(a) || a.IsSubsetOf(b) {
+		t.Error("Unexpected IsSubsetOf result")
+	}
+	if !a.Equal(NewHashSet(1, 2, 3, 4, 5)) {
+		t.Error("Unexpected Equal result")
+	}
+}
+
+func TestTreeSetSerialization(t *testing.T) {
+	set := NewTreeSet(intLess, 3, 1, 2)
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("MarshalJSON error: %v", err)
+	}
+	if string(data) != "[1,2,3]" {
+		t.Errorf("Expected [1,2,3], got %s", data)
+	}
+	if set.ToString() != "[1,2,3]" {
+		t.Errorf("Expected ToString [1,2,3], got %s", set.ToString())
+	}
+
+	decoded := NewTreeSet[int](intLess)
+	if err = json.Unmarshal([]byte("[9,7,8]"), decoded); err != nil {
+		t.Fatalf("UnmarshalJSON error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.ToSlice(), []int{7, 8, 9}) {
+		t.Errorf("Expected [7 8 9], got %v", decoded.ToSlice())
+	}
+
+	// 未指定比较函数时反序列化应返回错误
+	var zero TreeSet[int]
+	if err = json.Unmarshal([]byte("[1]"), &zero); err == nil {
+		t.Error("Expected error when unmarshalling into zero TreeSet")
+	}
+
+	type doc struct {
+		Set *TreeSet[int] `bson:"set"`
+	}
+	bytes, err := bson.Marshal(doc{Set: set})
+	if err != nil {
+		t.Fatalf("MarshalBSON error: %v", err)
+	}
+	out := doc{Set: NewTreeSet[int](intLess)}
+	if err = bson.Unmarshal(bytes, &out); err != nil {
+		t.Fatalf("UnmarshalBSON error: %v", err)
+	}
+	if !reflect.DeepEqual(out.Set.ToSlice(), []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v", out.Set.ToSlice())
+	}
+}
+
+func TestTreeSetConcurrent(t *testing.T) {
+	set := NewTreeSet[int](intLess)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(base int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				set.Add(base*100 + j)
+				set.Contains(j)
+				set.Floor(j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if set.Size() != 1000 {
+		t.Errorf("Expected size 1000, got %d", set.Size())
+	}
+}
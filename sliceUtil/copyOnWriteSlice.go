@@ -3,28 +3,45 @@ package sliceUtil
 import (
 	"encoding/json"
 	"fmt"
+	"iter"
+	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/Tomatosky/jo-util/logger"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-var _ bson.ValueMarshaler = (*CopyOnWriteSlice)(nil)
-var _ bson.ValueUnmarshaler = (*CopyOnWriteSlice)(nil)
-var _ json.Marshaler = (*CopyOnWriteSlice)(nil)
-var _ json.Unmarshaler = (*CopyOnWriteSlice)(nil)
+var _ bson.ValueMarshaler = (*CopyOnWriteSlice[int])(nil)
+var _ bson.ValueUnmarshaler = (*CopyOnWriteSlice[int])(nil)
+var _ json.Marshaler = (*CopyOnWriteSlice[int])(nil)
+var _ json.Unmarshaler = (*CopyOnWriteSlice[int])(nil)
 
 // CopyOnWriteSlice 线程安全的动态数组，写时复制
+// 读操作无锁，直接读取原子发布的快照；写操作串行执行，复制快照修改后再原子替换
 type CopyOnWriteSlice[T comparable] struct {
-	mu   sync.RWMutex // 读写锁
-	data []T          // 实际存储数据的数组
+	mu   sync.Mutex          // 写锁，保证写操作串行
+	data atomic.Pointer[[]T] // 当前快照，发布后不再修改
 }
 
 // NewCopyOnWriteSlice 创建新的线程安全动态数组
 func NewCopyOnWriteSlice[T comparable]() *CopyOnWriteSlice[T] {
-	return &CopyOnWriteSlice[T]{
-		data: make([]T, 0),
+	c := &CopyOnWriteSlice[T]{}
+	c.store(make([]T, 0))
+	return c
+}
+
+// load 获取当前快照，快照只读
+func (c *CopyOnWriteSlice[T]) load() []T {
+	if p := c.data.Load(); p != nil {
+		return *p
 	}
+	return nil
+}
+
+// store 发布新的快照，调用时需持有写锁
+func (c *CopyOnWriteSlice[T]) store(data []T) {
+	c.data.Store(&data)
 }
 
 // Add 添加元素到末尾（写操作需要复制整个数组）
@@ -33,9 +50,10 @@ func (c *CopyOnWriteSlice[T]) Add(element T) {
 	defer c.mu.Unlock()
 
 	// 创建新数组并追加元素
-	newData := append(make([]T, 0, len(c.data)+1), c.data...)
+	old := c.load()
+	newData := append(make([]T, 0, len(old)+1), old...)
 	newData = append(newData, element)
-	c.data = newData
+	c.store(newData)
 }
 
 func (c *CopyOnWriteSlice[T]) AddAll(elements ...T) {
@@ -43,9 +61,35 @@ func (c *CopyOnWriteSlice[T]) AddAll(elements ...T) {
 	defer c.mu.Unlock()
 
 	// 创建新数组并追加所有元素
-	newData := append(make([]T, 0, len(c.data)+len(elements)), c.data...)
+	old := c.load()
+	newData := append(make([]T, 0, len(old)+len(elements)), old...)
 	newData = append(newData, elements...)
-	c.data = newData
+	c.store(newData)
+}
+
+// AddAllAbsent 添加不存在的元素，elements 中的重复元素只添加一次，返回实际添加的数量
+func (c *CopyOnWriteSlice[T]) AddAllAbsent(elements ...T) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old := c.load()
+	seen := make(map[T]struct{}, len(old)+len(elements))
+	for _, v := range old {
+		seen[v] = struct{}{}
+	}
+	added := make([]T, 0, len(elements))
+	for _, v := range elements {
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			added = append(added, v)
+		}
+	}
+	if len(added) == 0 {
+		return 0
+	}
+	newData := append(make([]T, 0, len(old)+len(added)), old...)
+	c.store(append(newData, added...))
+	return len(added)
 }
 
 // Insert 在指定索引插入元素
@@ -53,35 +97,34 @@ func (c *CopyOnWriteSlice[T]) Insert(index int, element T) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if index < 0 || index > len(c.data) {
+	old := c.load()
+	if index < 0 || index > len(old) {
 		logger.Log.Error(fmt.Sprintf("%v", "index out of range"))
 		panic("index out of range")
 	}
 
 	// 创建长度+1的新数组
-	newData := make([]T, len(c.data)+1)
+	newData := make([]T, len(old)+1)
 	// 复制前半部分
-	copy(newData[:index], c.data[:index])
+	copy(newData[:index], old[:index])
 	// 插入元素
 	newData[index] = element
 	// 复制后半部分
-	copy(newData[index+1:], c.data[index:])
-	c.data = newData
+	copy(newData[index+1:], old[index:])
+	c.store(newData)
 }
 
-// Get 获取元素（读操作使用读锁）
+// Get 获取元素（读操作无锁）
 func (c *CopyOnWriteSlice[T]) Get(index int) T {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
+	data := c.load()
 	if index < 0 {
-		index += len(c.data)
+		index += len(data)
 	}
-	if index < 0 || index >= len(c.data) {
+	if index < 0 || index >= len(data) {
 		logger.Log.Error(fmt.Sprintf("%v", "index out of range"))
 		panic("index out of range")
 	}
-	return c.data[index]
+	return data[index]
 }
 
 // Remove 移除指定索引元素
@@ -89,46 +132,112 @@ func (c *CopyOnWriteSlice[T]) Remove(index int) T {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if index < 0 || index >= len(c.data) {
+	old := c.load()
+	if index < 0 || index >= len(old) {
 		logger.Log.Error(fmt.Sprintf("%v", "index out of range"))
 		panic("index out of range")
 	}
 
-	removed := c.data[index]
+	removed := old[index]
 	// 创建新数组并跳过指定元素
-	newData := make([]T, len(c.data)-1)
-	copy(newData[:index], c.data[:index])
-	copy(newData[index:], c.data[index+1:])
-	c.data = newData
+	newData := make([]T, len(old)-1)
+	copy(newData[:index], old[:index])
+	copy(newData[index:], old[index+1:])
+	c.store(newData)
 	return removed
 }
 
-// Size 当前元素数量（读操作）
+// RemoveIf 删除所有满足条件的元素，返回删除的数量
+// predicate 在持有写锁时调用，其中禁止调用 Add/Remove 等写操作，否则死锁，读操作不受影响
+func (c *CopyOnWriteSlice[T]) RemoveIf(predicate func(T) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old := c.load()
+	newData := make([]T, 0, len(old))
+	for _, v := range old {
+		if !predicate(v) {
+			newData = append(newData, v)
+		}
+	}
+	count := len(old) - len(newData)
+	if count > 0 {
+		c.store(newData)
+	}
+	return count
+}
+
+// ReplaceAll 使用 operator 的结果替换每个元素
+// operator 在持有写锁时调用，其中禁止调用 Add/Remove 等写操作，否则死锁
+func (c *CopyOnWriteSlice[T]) ReplaceAll(operator func(T) T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old := c.load()
+	newData := make([]T, len(old))
+	for i, v := range old {
+		newData[i] = operator(v)
+	}
+	c.store(newData)
+}
+
+// Sort 按 less 稳定排序
+// less 在持有写锁时调用，其中禁止调用写操作，否则死锁
+func (c *CopyOnWriteSlice[T]) Sort(less func(a, b T) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	newData := append([]T(nil), c.load()...)
+	sort.SliceStable(newData, func(i, j int) bool {
+		return less(newData[i], newData[j])
+	})
+	c.store(newData)
+}
+
+// Mutate 批量写操作，只复制一次数组
+// f 接收当前数据的副本，可以任意修改后返回新的数据，f 返回后不应再持有该切片
+// f 在持有写锁时执行，修改只能作用于传入的副本，在 f 中调用 c.Add 等写操作会死锁
+// 例: c.Mutate(func(data []int) []int { return append(data[:0], data[1:]...) })
+func (c *CopyOnWriteSlice[T]) Mutate(f func([]T) []T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	newData := f(append([]T(nil), c.load()...))
+	if newData == nil {
+		newData = make([]T, 0)
+	}
+	c.store(newData)
+}
+
+// Size 当前元素数量（读操作无锁）
 func (c *CopyOnWriteSlice[T]) Size() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return len(c.data)
+	return len(c.load())
 }
 
-// Range 安全遍历（遍历过程中数组不会被修改）
+// Range 安全遍历当前快照（遍历过程中的修改不影响本次遍历）
 func (c *CopyOnWriteSlice[T]) Range(f func(int, T) bool) {
-	c.mu.RLock()
-	snapshot := append(make([]T, 0, len(c.data)), c.data...) // 创建快照
-	c.mu.RUnlock()
-
-	for i, v := range snapshot {
+	for i, v := range c.load() {
 		if !f(i, v) {
 			break
 		}
 	}
 }
 
-// Contains 检查元素是否存在（读操作）
-func (c *CopyOnWriteSlice[T]) Contains(element T) bool {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// All 返回当前快照的迭代器，可用于 for range
+func (c *CopyOnWriteSlice[T]) All() iter.Seq[T] {
+	snapshot := c.load()
+	return func(yield func(T) bool) {
+		for _, v := range snapshot {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
 
-	for _, v := range c.data {
+// Contains 检查元素是否存在（读操作无锁）
+func (c *CopyOnWriteSlice[T]) Contains(element T) bool {
+	for _, v := range c.load() {
 		if v == element {
 			return true
 		}
@@ -138,36 +247,20 @@ func (c *CopyOnWriteSlice[T]) Contains(element T) bool {
 
 // RemoveObject 删除所有匹配元素
 func (c *CopyOnWriteSlice[T]) RemoveObject(element T) int {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// 收集保留元素
-	newData := make([]T, 0, len(c.data))
-	count := 0
-	for _, v := range c.data {
-		if v != element {
-			newData = append(newData, v)
-		} else {
-			count++
-		}
-	}
-	c.data = newData
-	return count
+	return c.RemoveIf(func(v T) bool {
+		return v == element
+	})
 }
 
-// ToSlice 返回数组副本（读操作）
+// ToSlice 返回数组副本（读操作无锁）
 func (c *CopyOnWriteSlice[T]) ToSlice() []T {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return append(make([]T, 0, len(c.data)), c.data...)
+	data := c.load()
+	return append(make([]T, 0, len(data)), data...)
 }
 
-// ToString 返回JSON格式字符串（读操作）
+// ToString 返回JSON格式字符串（读操作无锁）
 func (c *CopyOnWriteSlice[T]) ToString() string {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	bytes, err := json.Marshal(c.data)
+	bytes, err := c.MarshalJSON()
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("%v", err))
 		panic(err)
@@ -177,9 +270,7 @@ func (c *CopyOnWriteSlice[T]) ToString() string {
 
 // MarshalJSON 实现 json.Marshaler 接口
 func (c *CopyOnWriteSlice[T]) MarshalJSON() ([]byte, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return json.Marshal(c.data[:])
+	return json.Marshal(c.load())
 }
 
 // UnmarshalJSON 实现 json.Unmarshaler 接口
@@ -189,8 +280,7 @@ func (c *CopyOnWriteSlice[T]) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	c.data = []T{}
-	c.AddAll(tmp...)
+	c.reset(tmp)
 	return nil
 }
 
@@ -205,7 +295,16 @@ func (c *CopyOnWriteSlice[T]) UnmarshalBSONValue(t byte, data []byte) error {
 	if err := bson.UnmarshalValue(bson.Type(t), data, &elements); err != nil {
 		return err
 	}
-	c.data = []T{}
-	c.AddAll(elements...)
+	c.reset(elements)
 	return nil
 }
+
+// reset 使用 elements 替换全部数据
+func (c *CopyOnWriteSlice[T]) reset(elements []T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elements == nil {
+		elements = make([]T, 0)
+	}
+	c.store(elements)
+}
@@ -2,6 +2,7 @@ package sliceUtil
 
 import (
 	"encoding/json"
+	"reflect"
 	"sync"
 	"testing"
 )
@@ -766,3 +767,165 @@ func BenchmarkConcurrentRead(b *testing.B) {
 		}
 	})
 }
+
+// TestAddAllAbsent 测试添加不存在的元素
+func TestAddAllAbsent(t *testing.T) {
+	slice := NewCopyOnWriteSlice[int]()
+	slice.AddAll(1, 2)
+
+	added := slice.AddAllAbsent(2, 3, 3, 4)
+	if added != 2 {
+		t.Errorf("Expected 2 added, got %d", added)
+	}
+	expected := []int{1, 2, 3, 4}
+	if !reflect.DeepEqual(slice.ToSlice(), expected) {
+		t.Errorf("Expected %v, got %v", expected, slice.ToSlice())
+	}
+	if slice.AddAllAbsent(1, 4) != 0 {
+		t.Error("Expected no element added")
+	}
+}
+
+// TestRemoveIf 测试按条件删除
+func TestRemoveIf(t *testing.T) {
+	slice := NewCopyOnWriteSlice[int]()
+	slice.AddAll(1, 2, 3, 4, 5, 6)
+
+	count := slice.RemoveIf(func(v int) bool { return v%2 == 0 })
+	if count != 3 {
+		t.Errorf("Expected 3 removed, got %d", count)
+	}
+	if !reflect.DeepEqual(slice.ToSlice(), []int{1, 3, 5}) {
+		t.Errorf("Unexpected elements %v", slice.ToSlice())
+	}
+	if slice.RemoveIf(func(v int) bool { return v > 10 }) != 0 {
+		t.Error("Expected nothing removed")
+	}
+}
+
+// TestReplaceAllAndSort 测试替换和排序
+func TestReplaceAllAndSort(t *testing.T) {
+	slice := NewCopyOnWriteSlice[int]()
+	slice.AddAll(3, 1, 2)
+
+	slice.ReplaceAll(func(v int) int { return v * 10 })
+	if !reflect.DeepEqual(slice.ToSlice(), []int{30, 10, 20}) {
+		t.Errorf("Unexpected elements after ReplaceAll %v", slice.ToSlice())
+	}
+
+	slice.Sort(func(a, b int) bool { return a < b })
+	if !reflect.DeepEqual(slice.ToSlice(), []int{10, 20, 30}) {
+		t.Errorf("Unexpected elements after Sort %v", slice.ToSlice())
+	}
+}
+
+// TestMutate 测试批量写操作
+func TestMutate(t *testing.T) {
+	slice := NewCopyOnWriteSlice[int]()
+	slice.AddAll(1, 2, 3)
+	before := slice.All()
+
+	slice.Mutate(func(data []int) []int {
+		data[0] = 100
+		data = append(data, 4, 5)
+		return data[1:]
+	})
+	if !reflect.DeepEqual(slice.ToSlice(), []int{2, 3, 4, 5}) {
+		t.Errorf("Unexpected elements after Mutate %v", slice.ToSlice())
+	}
+
+	// 之前的快照不受影响
+	var old []int
+	for v := range before {
+		old = append(old, v)
+	}
+	if !reflect.DeepEqual(old, []int{1, 2, 3}) {
+		t.Errorf("Snapshot modified by Mutate: %v", old)
+	}
+
+	slice.Mutate(func(data []int) []int { return nil })
+	if slice.Size() != 0 || slice.ToString() != "[]" {
+		t.Errorf("Expected empty slice, got %s", slice.ToString())
+	}
+}
+
+// TestAllSnapshot 测试迭代器使用快照
+func TestAllSnapshot(t *testing.T) {
+	slice := NewCopyOnWriteSlice[int]()
+	slice.AddAll(1, 2, 3)
+
+	var got []int
+	for v := range slice.All() {
+		got = append(got, v)
+		slice.Add(v * 10) // 遍历过程中修改不影响本次遍历
+	}
+	if !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v", got)
+	}
+	if slice.Size() != 6 {
+		t.Errorf("Expected size 6, got %d", slice.Size())
+	}
+
+	// 提前终止
+	count := 0
+	for range slice.All() {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 iterations, got %d", count)
+	}
+}
+
+// TestZeroValue 测试零值可用
+func TestZeroValue(t *testing.T) {
+	var slice CopyOnWriteSlice[string]
+	if slice.Size() != 0 || slice.Contains("a") {
+		t.Error("Expected empty zero value")
+	}
+	// 零值与 nil 切片一致序列化为 null
+	if data, err := json.Marshal(&slice); err != nil || string(data) != "null" || slice.ToString() != "null" {
+		t.Errorf("Expected zero value to marshal as null, got %s, %v", data, err)
+	}
+	slice.Add("a")
+	if slice.Get(0) != "a" {
+		t.Errorf("Expected a, got %s", slice.Get(0))
+	}
+
+	var holder struct {
+		Items CopyOnWriteSlice[int] `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(`{"items":[1,2]}`), &holder); err != nil {
+		t.Fatal(err)
+	}
+	if holder.Items.Size() != 2 {
+		t.Errorf("Expected size 2, got %d", holder.Items.Size())
+	}
+}
+
+// TestConcurrentMutate 测试并发批量写与无锁读
+func TestConcurrentMutate(t *testing.T) {
+	slice := NewCopyOnWriteSlice[int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(val int) {
+			defer wg.Done()
+			slice.Mutate(func(data []int) []int {
+				return append(data, val, val)
+			})
+		}(i)
+		go func() {
+			defer wg.Done()
+			for range slice.All() {
+			}
+			_ = slice.Size()
+		}()
+	}
+	wg.Wait()
+	if slice.Size() != 100 {
+		t.Errorf("Expected size 100, got %d", slice.Size())
+	}
+}
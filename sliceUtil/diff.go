@@ -0,0 +1,316 @@
+package sliceUtil
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DiffResult 两个切片按键比较的差异
+type DiffResult[T any] struct {
+	Added   []T         `json:"added" bson:"added"`     // 新增的元素，按新切片中的顺序
+	Removed []T         `json:"removed" bson:"removed"` // 删除的元素，按旧切片中的顺序
+	Moved   []Move[T]   `json:"moved" bson:"moved"`     // 相对顺序发生变化的元素，按新切片中的顺序
+	Changed []Change[T] `json:"changed" bson:"changed"` // 键相同但内容发生变化的元素，按新切片中的顺序
+}
+
+// Move 位置发生变化的元素
+type Move[T any] struct {
+	Item T   `json:"item" bson:"item"` // 新切片中的元素
+	From int `json:"from" bson:"from"` // 在旧切片中的下标
+	To   int `json:"to" bson:"to"`     // 在新切片中的下标
+}
+
+// Change 内容发生变化的元素
+type Change[T any] struct {
+	Old   T   `json:"old" bson:"old"`
+	New   T   `json:"new" bson:"new"`
+	Index int `json:"index" bson:"index"` // 在新切片中的下标
+}
+
+// IsEmpty 是否没有任何差异
+func (d *DiffResult[T]) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Moved) == 0 && len(d.Changed) == 0
+}
+
+// Diff 按键比较新旧切片，返回新增、删除、移动和内容变化的元素，内容使用 reflect.DeepEqual 比较
+// 键重复时按出现顺序依次匹配，新切片中第k个出现的键与旧切片中第k个出现的键匹配
+// 移动的元素为保持其余元素相对顺序不变所需移动的最少元素
+// 例: Diff(oldItems, newItems, func(item Item) string { return item.Id })
+func Diff[T any, K comparable](old, new []T, keyFn func(T) K) *DiffResult[T] {
+	return DiffFunc(old, new, keyFn, func(a, b T) bool {
+		return reflect.DeepEqual(a, b)
+	})
+}
+
+// DiffFunc 按键比较新旧切片，使用 equal 判断内容是否变化
+func DiffFunc[T any, K comparable](old, new []T, keyFn func(T) K, equal func(a, b T) bool) *DiffResult[T] {
+	result := &DiffResult[T]{
+		Added:   make([]T, 0),
+		Removed: make([]T, 0),
+		Moved:   make([]Move[T], 0),
+		Changed: make([]Change[T], 0),
+	}
+
+	// 每个键在旧切片中出现的下标
+	oldIndex := make(map[K][]int, len(old))
+	for i, item := range old {
+		key := keyFn(item)
+		oldIndex[key] = append(oldIndex[key], i)
+	}
+
+	// 新切片中与旧切片匹配的元素
+	matchedOld := make([]bool, len(old))
+	var commonNew, commonOld []int
+	for j, item := range new {
+		key := keyFn(item)
+		indexes := oldIndex[key]
+		if len(indexes) == 0 {
+			result.Added = append(result.Added, item)
+			continue
+		}
+		i := indexes[0]
+		oldIndex[key] = indexes[1:]
+		matchedOld[i] = true
+		commonNew = append(commonNew, j)
+		commonOld = append(commonOld, i)
+		if !equal(old[i], item) {
+			result.Changed = append(result.Changed, Change[T]{Old: old[i], New: item, Index: j})
+		}
+	}
+	for i, item := range old {
+		if !matchedOld[i] {
+			result.Removed = append(result.Removed, item)
+		}
+	}
+
+	// 旧下标的最长递增子序列中的元素保持不动，其余元素视为移动
+	stay := longestIncreasing(commonOld)
+	for k, j := range commonNew {
+		if !stay[k] {
+			result.Moved = append(result.Moved, Move[T]{Item: new[j], From: commonOld[k], To: j})
+		}
+	}
+	return result
+}
+
+// longestIncreasing 计算最长严格递增子序列，返回每个位置是否属于该子序列
+func longestIncreasing(nums []int) []bool {
+	// tails[l] 为长度 l+1 的递增子序列的末尾位置
+	tails := make([]int, 0, len(nums))
+	prev := make([]int, len(nums))
+	for i := range nums {
+		l := LowerBoundFunc(tails, i, func(a, b int) bool {
+			return nums[a] < nums[b]
+		})
+		if l > 0 {
+			prev[i] = tails[l-1]
+		} else {
+			prev[i] = -1
+		}
+		if l == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[l] = i
+		}
+	}
+
+	result := make([]bool, len(nums))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+			result[i] = true
+		}
+	}
+	return result
+}
+
+// EditOp 编辑操作类型
+type EditOp int
+
+const (
+	EditKeep   EditOp = iota // 保留旧切片中的元素
+	EditDelete               // 删除旧切片中的元素
+	EditInsert               // 插入新元素
+)
+
+func (op EditOp) String() string {
+	switch op {
+	case EditKeep:
+		return "keep"
+	case EditDelete:
+		return "delete"
+	case EditInsert:
+		return "insert"
+	default:
+		return fmt.Sprintf("EditOp(%d)", int(op))
+	}
+}
+
+// Edit 编辑脚本中的一步操作
+type Edit[T any] struct {
+	Op    EditOp `json:"op" bson:"op"`
+	Index int    `json:"index" bson:"index"` // 保留和删除为旧切片中的下标，插入为新切片中的下标
+	Value T      `json:"value" bson:"value"`
+}
+
+// EditScript 基于最长公共子序列计算将 old 转换为 new 的编辑脚本，保留的元素最多
+// 使用 Hirschberg 算法，时间复杂度为 O(n*m)，额外空间为 O(n+m)，会先跳过相同的前缀和后缀
+// 例: EditScript([]string{"a", "b", "c"}, []string{"a", "c", "d"}) // keep a, delete b, keep c, insert d
+func EditScript[T comparable](old, new []T) []Edit[T] {
+	return EditScriptFunc(old, new, func(a, b T) bool {
+		return a == b
+	})
+}
+
+// EditScriptFunc 使用 equal 判断元素是否相同计算编辑脚本
+func EditScriptFunc[T any](old, new []T, equal func(a, b T) bool) []Edit[T] {
+	e := &editScripter[T]{
+		old:   old,
+		new:   new,
+		equal: equal,
+		fwd:   make([]int, len(new)+1),
+		bwd:   make([]int, len(new)+1),
+		edits: make([]Edit[T], 0, len(old)+len(new)),
+	}
+	e.diff(0, len(old), 0, len(new))
+	return e.edits
+}
+
+// editScripter 计算编辑脚本的状态，fwd 和 bwd 为各层递归共用的 LCS 长度行
+type editScripter[T any] struct {
+	old, new []T
+	equal    func(a, b T) bool
+	fwd, bwd []int
+	edits    []Edit[T]
+}
+
+// diff 计算 old[i1:i2] 到 new[j1:j2] 的编辑脚本并追加到 edits
+// 按 old 的中点切分，找到使两侧 LCS 长度之和最大的 new 切分点后分别递归
+func (e *editScripter[T]) diff(i1, i2, j1, j2 int) {
+	// 跳过相同的前缀和后缀
+	for i1 < i2 && j1 < j2 && e.equal(e.old[i1], e.new[j1]) {
+		e.keep(i1)
+		i1++
+		j1++
+	}
+	suffix := 0
+	for i1 < i2-suffix && j1 < j2-suffix && e.equal(e.old[i2-1-suffix], e.new[j2-1-suffix]) {
+		suffix++
+	}
+	i2 -= suffix
+	j2 -= suffix
+
+	switch {
+	case i1 == i2 || j1 == j2:
+		e.replace(i1, i2, j1, j2)
+	case i2-i1 == 1:
+		// 只剩一个旧元素时找到第一个相同的新元素保留
+		j := j1
+		for j < j2 && !e.equal(e.old[i1], e.new[j]) {
+			j++
+		}
+		if j == j2 {
+			e.replace(i1, i2, j1, j2)
+		} else {
+			e.replace(i1, i1, j1, j)
+			e.keep(i1)
+			e.replace(i2, i2, j+1, j2)
+		}
+	default:
+		mid := (i1 + i2) / 2
+		e.forward(i1, mid, j1, j2)
+		e.backward(mid, i2, j1, j2)
+		// 长度相同时取靠后的切分点，使插入排在删除之前
+		split, best := j1, -1
+		for j := j1; j <= j2; j++ {
+			if l := e.fwd[j-j1] + e.bwd[j-j1]; l >= best {
+				split, best = j, l
+			}
+		}
+		e.diff(i1, mid, j1, split)
+		e.diff(mid, i2, split, j2)
+	}
+
+	for k := 0; k < suffix; k++ {
+		e.keep(i2 + k)
+	}
+}
+
+// forward 计算 fwd[j] 为 old[i1:i2] 与 new[j1:j1+j] 的 LCS 长度
+func (e *editScripter[T]) forward(i1, i2, j1, j2 int) {
+	row := e.fwd[:j2-j1+1]
+	clear(row)
+	for i := i1; i < i2; i++ {
+		diag := 0 // 上一行 j-1 列的值
+		for j := j1; j < j2; j++ {
+			up := row[j-j1+1]
+			if e.equal(e.old[i], e.new[j]) {
+				row[j-j1+1] = diag + 1
+			} else {
+				row[j-j1+1] = max(up, row[j-j1])
+			}
+			diag = up
+		}
+	}
+}
+
+// backward 计算 bwd[j] 为 old[i1:i2] 与 new[j1+j:j2] 的 LCS 长度
+func (e *editScripter[T]) backward(i1, i2, j1, j2 int) {
+	row := e.bwd[:j2-j1+1]
+	clear(row)
+	for i := i2 - 1; i >= i1; i-- {
+		diag := 0 // 上一行 j+1 列的值
+		for j := j2 - 1; j >= j1; j-- {
+			up := row[j-j1]
+			if e.equal(e.old[i], e.new[j]) {
+				row[j-j1] = diag + 1
+			} else {
+				row[j-j1] = max(up, row[j-j1+1])
+			}
+			diag = up
+		}
+	}
+}
+
+func (e *editScripter[T]) keep(i int) {
+	e.edits = append(e.edits, Edit[T]{Op: EditKeep, Index: i, Value: e.old[i]})
+}
+
+// replace 插入 new[j1:j2] 后删除 old[i1:i2]
+func (e *editScripter[T]) replace(i1, i2, j1, j2 int) {
+	for j := j1; j < j2; j++ {
+		e.edits = append(e.edits, Edit[T]{Op: EditInsert, Index: j, Value: e.new[j]})
+	}
+	for i := i1; i < i2; i++ {
+		e.edits = append(e.edits, Edit[T]{Op: EditDelete, Index: i, Value: e.old[i]})
+	}
+}
+
+// ApplyEdits 将编辑脚本应用到 old 上得到新切片，是 EditScript 的逆操作
+// 脚本与 old 不匹配时返回错误
+func ApplyEdits[T comparable](old []T, edits []Edit[T]) ([]T, error) {
+	result := make([]T, 0, len(old))
+	i := 0
+	for _, e := range edits {
+		switch e.Op {
+		case EditKeep, EditDelete:
+			if i >= len(old) || e.Index != i || old[i] != e.Value {
+				return nil, fmt.Errorf("edit %s at index %d does not match source", e.Op, e.Index)
+			}
+			if e.Op == EditKeep {
+				result = append(result, old[i])
+			}
+			i++
+		case EditInsert:
+			if e.Index != len(result) {
+				return nil, fmt.Errorf("edit insert at index %d does not match result length %d", e.Index, len(result))
+			}
+			result = append(result, e.Value)
+		default:
+			return nil, fmt.Errorf("unknown edit op: %d", e.Op)
+		}
+	}
+	if i != len(old) {
+		return nil, fmt.Errorf("edit script does not cover source, %d of %d elements consumed", i, len(old))
+	}
+	return result, nil
+}
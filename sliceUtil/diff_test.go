@@ -0,0 +1,210 @@
+package sliceUtil
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type diffItem struct {
+	Id    string
+	Value int
+}
+
+func diffItemKey(item diffItem) string { return item.Id }
+
+func TestDiff(t *testing.T) {
+	old := []diffItem{{"a", 1}, {"b", 2}, {"c", 3}, {"d", 4}}
+	new := []diffItem{{"a", 1}, {"c", 30}, {"e", 5}, {"d", 4}, {"b", 2}}
+
+	d := Diff(old, new, diffItemKey)
+	if !reflect.DeepEqual(d.Added, []diffItem{{"e", 5}}) {
+		t.Errorf("Added = %v", d.Added)
+	}
+	if len(d.Removed) != 0 {
+		t.Errorf("Removed = %v", d.Removed)
+	}
+	// a c d 保持相对顺序，只有 b 移动
+	if !reflect.DeepEqual(d.Moved, []Move[diffItem]{{Item: diffItem{"b", 2}, From: 1, To: 4}}) {
+		t.Errorf("Moved = %v", d.Moved)
+	}
+	if !reflect.DeepEqual(d.Changed, []Change[diffItem]{{Old: diffItem{"c", 3}, New: diffItem{"c", 30}, Index: 1}}) {
+		t.Errorf("Changed = %v", d.Changed)
+	}
+	if d.IsEmpty() {
+		t.Error("Expected non-empty diff")
+	}
+}
+
+func TestDiffRemovedAndDuplicates(t *testing.T) {
+	old := []diffItem{{"a", 1}, {"b", 2}, {"a", 3}}
+	new := []diffItem{{"b", 2}, {"a", 1}, {"a", 9}}
+
+	d := Diff(old, new, diffItemKey)
+	// 重复的键按出现顺序依次匹配
+	if len(d.Added) != 0 || len(d.Removed) != 0 {
+		t.Errorf("Added = %v, Removed = %v", d.Added, d.Removed)
+	}
+	if !reflect.DeepEqual(d.Changed, []Change[diffItem]{{Old: diffItem{"a", 3}, New: diffItem{"a", 9}, Index: 2}}) {
+		t.Errorf("Changed = %v", d.Changed)
+	}
+	if len(d.Moved) != 1 {
+		t.Errorf("Moved = %v", d.Moved)
+	}
+
+	removed := Diff(old, []diffItem{{"a", 1}}, diffItemKey)
+	if !reflect.DeepEqual(removed.Removed, []diffItem{{"b", 2}, {"a", 3}}) || len(removed.Added) != 0 {
+		t.Errorf("Removed = %v, Added = %v", removed.Removed, removed.Added)
+	}
+
+	same := Diff(old, old, diffItemKey)
+	if !same.IsEmpty() {
+		t.Errorf("Expected empty diff, got %+v", same)
+	}
+
+	all := Diff(nil, old, diffItemKey)
+	if len(all.Added) != 3 || len(all.Removed) != 0 {
+		t.Errorf("Diff from empty = %+v", all)
+	}
+}
+
+func TestDiffFunc(t *testing.T) {
+	old := []diffItem{{"a", 1}, {"b", 2}}
+	new := []diffItem{{"a", 100}, {"b", 3}}
+	// 只有差值超过10才视为变化
+	d := DiffFunc(old, new, diffItemKey, func(a, b diffItem) bool {
+		return b.Value-a.Value <= 10
+	})
+	if len(d.Changed) != 1 || d.Changed[0].New.Id != "a" {
+		t.Errorf("Changed = %v", d.Changed)
+	}
+}
+
+func editString(edits []Edit[string]) string {
+	parts := Map(edits, func(e Edit[string]) string {
+		return map[EditOp]string{EditKeep: " ", EditDelete: "-", EditInsert: "+"}[e.Op] + e.Value
+	})
+	return strings.Join(parts, ",")
+}
+
+func TestEditScript(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{"Example", "abc", "acd", " a,-b, c,+d"},
+		{"Same", "abc", "abc", " a, b, c"},
+		{"FromEmpty", "", "ab", "+a,+b"},
+		{"ToEmpty", "ab", "", "-a,-b"},
+		{"CommonPrefixSuffix", "xaby", "xcy", " x,+c,-a,-b, y"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := strings.Split(tt.old, "")
+			new := strings.Split(tt.new, "")
+			edits := EditScript(old, new)
+			if got := editString(edits); got != tt.want {
+				t.Errorf("EditScript() = %q, want %q", got, tt.want)
+			}
+			applied, err := ApplyEdits(old, edits)
+			if err != nil {
+				t.Fatalf("ApplyEdits() error = %v", err)
+			}
+			if strings.Join(applied, "") != tt.new {
+				t.Errorf("ApplyEdits() = %v, want %v", applied, tt.new)
+			}
+		})
+	}
+}
+
+func TestEditScriptRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randSlice := func() []int {
+		s := make([]int, r.Intn(30))
+		for i := range s {
+			s[i] = r.Intn(5)
+		}
+		return s
+	}
+	for n := 0; n < 200; n++ {
+		old, new := randSlice(), randSlice()
+		edits := EditScript(old, new)
+		got, err := ApplyEdits(old, edits)
+		if err != nil {
+			t.Fatalf("ApplyEdits() error = %v", err)
+		}
+		if len(got) != len(new) || (len(new) > 0 && !reflect.DeepEqual(got, new)) {
+			t.Fatalf("ApplyEdits() = %v, want %v", got, new)
+		}
+		if keeps := countKeeps(edits); keeps != lcsLength(old, new) {
+			t.Fatalf("EditScript(%v, %v) keeps %d, want %d", old, new, keeps, lcsLength(old, new))
+		}
+	}
+}
+
+func TestEditScriptLarge(t *testing.T) {
+	// 两个几乎完全不同的长切片，二维表需要 5000*5000 个格子
+	old := make([]int, 5000)
+	new := make([]int, 5000)
+	for i := range old {
+		old[i] = i * 2
+		new[i] = i*2 + 1
+	}
+	old[2500], new[1000] = -1, -1
+	edits := EditScript(old, new)
+	if keeps := countKeeps(edits); keeps != 1 {
+		t.Errorf("Expected 1 kept element, got %d", keeps)
+	}
+	got, err := ApplyEdits(old, edits)
+	if err != nil || !reflect.DeepEqual(got, new) {
+		t.Fatalf("ApplyEdits() error = %v", err)
+	}
+}
+
+func countKeeps[T any](edits []Edit[T]) int {
+	n := 0
+	for _, e := range edits {
+		if e.Op == EditKeep {
+			n++
+		}
+	}
+	return n
+}
+
+// lcsLength 二维表计算最长公共子序列长度
+func lcsLength(a, b []int) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	return lcs[0][0]
+}
+
+func TestApplyEditsMismatch(t *testing.T) {
+	edits := EditScript([]int{1, 2, 3}, []int{1, 3})
+	if _, err := ApplyEdits([]int{1, 5, 3}, edits); err == nil {
+		t.Error("Expected error when source does not match")
+	}
+	if _, err := ApplyEdits([]int{1, 2, 3, 4}, edits); err == nil {
+		t.Error("Expected error when script does not cover source")
+	}
+	if _, err := ApplyEdits([]int{}, []Edit[int]{{Op: EditInsert, Index: 1, Value: 1}}); err == nil {
+		t.Error("Expected error for invalid insert index")
+	}
+	if EditOp(9).String() != "EditOp(9)" || EditInsert.String() != "insert" {
+		t.Error("Unexpected EditOp string")
+	}
+}
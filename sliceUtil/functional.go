@@ -0,0 +1,237 @@
+package sliceUtil
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+
+	"github.com/Tomatosky/jo-util/logger"
+)
+
+// Pair 二元组，用于 Zip/Unzip
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Map 将切片中的每个元素转换为新的值
+// 例: Map([]int{1, 2, 3}, func(v int) string { return strconv.Itoa(v) }) // ["1", "2", "3"]
+func Map[T any, R any](slice []T, mapper func(T) R) []R {
+	result := make([]R, len(slice))
+	for i, item := range slice {
+		result[i] = mapper(item)
+	}
+	return result
+}
+
+// Filter 返回满足条件的元素组成的新切片
+func Filter[T any](slice []T, predicate func(T) bool) []T {
+	result := make([]T, 0)
+	for _, item := range slice {
+		if predicate(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Reduce 从初始值开始依次累积切片中的元素
+// 例: Reduce([]int{1, 2, 3}, 0, func(acc, v int) int { return acc + v }) // 6
+func Reduce[T any, R any](slice []T, initial R, reducer func(R, T) R) R {
+	acc := initial
+	for _, item := range slice {
+		acc = reducer(acc, item)
+	}
+	return acc
+}
+
+// FlatMap 将每个元素转换为切片后展开合并
+func FlatMap[T any, R any](slice []T, mapper func(T) []R) []R {
+	result := make([]R, 0, len(slice))
+	for _, item := range slice {
+		result = append(result, mapper(item)...)
+	}
+	return result
+}
+
+// GroupBy 按键分组，组内元素保持原有顺序
+func GroupBy[T any, K comparable](slice []T, getKey func(T) K) map[K][]T {
+	result := make(map[K][]T)
+	for _, item := range slice {
+		key := getKey(item)
+		result[key] = append(result[key], item)
+	}
+	return result
+}
+
+// Partition 按条件将切片拆分为满足条件和不满足条件的两部分
+func Partition[T any](slice []T, predicate func(T) bool) (matched []T, unmatched []T) {
+	matched = make([]T, 0)
+	unmatched = make([]T, 0)
+	for _, item := range slice {
+		if predicate(item) {
+			matched = append(matched, item)
+		} else {
+			unmatched = append(unmatched, item)
+		}
+	}
+	return matched, unmatched
+}
+
+// Chunk 按指定大小将切片拆分为多个子切片，最后一个子切片可能不足 size 个元素
+// 例: Chunk([]int{1, 2, 3, 4, 5}, 2) // [[1 2] [3 4] [5]]
+func Chunk[T any](slice []T, size int) [][]T {
+	if size <= 0 {
+		logger.Log.Error(fmt.Sprintf("%v", "chunk size must be greater than 0"))
+		panic("chunk size must be greater than 0")
+	}
+	result := make([][]T, 0, (len(slice)+size-1)/size)
+	for i := 0; i < len(slice); i += size {
+		end := min(i+size, len(slice))
+		// 限制容量，避免对子切片 append 时覆盖后续元素
+		result = append(result, slice[i:end:end])
+	}
+	return result
+}
+
+// Window 返回长度为 size 的滑动窗口，元素不足 size 个时返回空切片
+// 例: Window([]int{1, 2, 3, 4}, 2) // [[1 2] [2 3] [3 4]]
+func Window[T any](slice []T, size int) [][]T {
+	if size <= 0 {
+		logger.Log.Error(fmt.Sprintf("%v", "window size must be greater than 0"))
+		panic("window size must be greater than 0")
+	}
+	if len(slice) < size {
+		return make([][]T, 0)
+	}
+	result := make([][]T, 0, len(slice)-size+1)
+	for i := 0; i+size <= len(slice); i++ {
+		result = append(result, slice[i:i+size:i+size])
+	}
+	return result
+}
+
+// Zip 将两个切片按下标组合为二元组，长度以较短的切片为准
+func Zip[A any, B any](a []A, b []B) []Pair[A, B] {
+	n := min(len(a), len(b))
+	result := make([]Pair[A, B], n)
+	for i := 0; i < n; i++ {
+		result[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return result
+}
+
+// Unzip 将二元组切片拆分为两个切片，是 Zip 的逆操作
+func Unzip[A any, B any](pairs []Pair[A, B]) ([]A, []B) {
+	a := make([]A, len(pairs))
+	b := make([]B, len(pairs))
+	for i, p := range pairs {
+		a[i] = p.First
+		b[i] = p.Second
+	}
+	return a, b
+}
+
+// DistinctBy 按键去重，键相同时保留第一个出现的元素
+func DistinctBy[T any, K comparable](slice []T, getKey func(T) K) []T {
+	seen := make(map[K]struct{})
+	result := make([]T, 0)
+	for _, item := range slice {
+		key := getKey(item)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// CountBy 按键统计元素数量
+func CountBy[T any, K comparable](slice []T, getKey func(T) K) map[K]int {
+	result := make(map[K]int)
+	for _, item := range slice {
+		result[getKey(item)]++
+	}
+	return result
+}
+
+// SortBy 按键升序稳定排序，该函数会修改传入的切片本身
+// 例: SortBy(users, func(u User) int { return u.Age })
+func SortBy[T any, K cmp.Ordered](slice []T, getKey func(T) K) []T {
+	sort.SliceStable(slice, func(i, j int) bool {
+		return cmp.Less(getKey(slice[i]), getKey(slice[j]))
+	})
+	return slice
+}
+
+// MinBy 返回键最小的元素，键相同时返回第一个，切片为空时返回false
+func MinBy[T any, K cmp.Ordered](slice []T, getKey func(T) K) (T, bool) {
+	var zero T
+	if len(slice) == 0 {
+		return zero, false
+	}
+	result, minKey := slice[0], getKey(slice[0])
+	for _, item := range slice[1:] {
+		if key := getKey(item); cmp.Less(key, minKey) {
+			result, minKey = item, key
+		}
+	}
+	return result, true
+}
+
+// MaxBy 返回键最大的元素，键相同时返回第一个，切片为空时返回false
+func MaxBy[T any, K cmp.Ordered](slice []T, getKey func(T) K) (T, bool) {
+	var zero T
+	if len(slice) == 0 {
+		return zero, false
+	}
+	result, maxKey := slice[0], getKey(slice[0])
+	for _, item := range slice[1:] {
+		if key := getKey(item); cmp.Less(maxKey, key) {
+			result, maxKey = item, key
+		}
+	}
+	return result, true
+}
+
+// Find 返回第一个满足条件的元素，不存在时返回false
+func Find[T any](slice []T, predicate func(T) bool) (T, bool) {
+	for _, item := range slice {
+		if predicate(item) {
+			return item, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// FindLast 返回最后一个满足条件的元素，不存在时返回false
+func FindLast[T any](slice []T, predicate func(T) bool) (T, bool) {
+	for i := len(slice) - 1; i >= 0; i-- {
+		if predicate(slice[i]) {
+			return slice[i], true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Every 判断是否所有元素都满足条件，空切片返回true
+func Every[T any](slice []T, predicate func(T) bool) bool {
+	for _, item := range slice {
+		if !predicate(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// Some 判断是否存在满足条件的元素，空切片返回false
+func Some[T any](slice []T, predicate func(T) bool) bool {
+	for _, item := range slice {
+		if predicate(item) {
+			return true
+		}
+	}
+	return false
+}
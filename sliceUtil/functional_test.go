@@ -0,0 +1,238 @@
+package sliceUtil
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type testUser struct {
+	Name string
+	Age  int
+}
+
+var testUsers = []testUser{
+	{"alice", 30},
+	{"bob", 25},
+	{"carol", 30},
+	{"dave", 20},
+}
+
+func isEven(v int) bool { return v%2 == 0 }
+
+func TestMap(t *testing.T) {
+	got := Map([]int{1, 2, 3}, strconv.Itoa)
+	if !reflect.DeepEqual(got, []string{"1", "2", "3"}) {
+		t.Errorf("Map() = %v", got)
+	}
+	if got := Map([]int{}, strconv.Itoa); len(got) != 0 {
+		t.Errorf("Map() on empty slice = %v", got)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		slice []int
+		want  []int
+	}{
+		{"Mixed", []int{1, 2, 3, 4}, []int{2, 4}},
+		{"NoneMatch", []int{1, 3}, []int{}},
+		{"EmptySlice", []int{}, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Filter(tt.slice, isEven); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	sum := Reduce([]int{1, 2, 3}, 0, func(acc, v int) int { return acc + v })
+	if sum != 6 {
+		t.Errorf("Reduce() = %v, want 6", sum)
+	}
+	s := Reduce([]int{1, 2, 3}, "", func(acc string, v int) string { return acc + strconv.Itoa(v) })
+	if s != "123" {
+		t.Errorf("Reduce() = %v, want 123", s)
+	}
+}
+
+func TestFlatMap(t *testing.T) {
+	got := FlatMap([]int{1, 2, 3}, func(v int) []int { return Fill(make([]int, v), v) })
+	if !reflect.DeepEqual(got, []int{1, 2, 2, 3, 3, 3}) {
+		t.Errorf("FlatMap() = %v", got)
+	}
+}
+
+func TestGroupByAndCountBy(t *testing.T) {
+	groups := GroupBy(testUsers, func(u testUser) int { return u.Age })
+	if len(groups) != 3 || !reflect.DeepEqual(groups[30], []testUser{testUsers[0], testUsers[2]}) {
+		t.Errorf("GroupBy() = %v", groups)
+	}
+
+	counts := CountBy(testUsers, func(u testUser) int { return u.Age })
+	if !reflect.DeepEqual(counts, map[int]int{30: 2, 25: 1, 20: 1}) {
+		t.Errorf("CountBy() = %v", counts)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	even, odd := Partition([]int{1, 2, 3, 4, 5}, isEven)
+	if !reflect.DeepEqual(even, []int{2, 4}) || !reflect.DeepEqual(odd, []int{1, 3, 5}) {
+		t.Errorf("Partition() = %v, %v", even, odd)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		name  string
+		slice []int
+		size  int
+		want  [][]int
+	}{
+		{"Uneven", []int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{"Even", []int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{"SizeLargerThanSlice", []int{1, 2}, 5, [][]int{{1, 2}}},
+		{"EmptySlice", []int{}, 3, [][]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Chunk(tt.slice, tt.size); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Chunk() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 对子切片 append 不影响原切片
+	src := []int{1, 2, 3, 4}
+	chunks := Chunk(src, 2)
+	_ = append(chunks[0], 99)
+	if src[2] != 3 {
+		t.Error("Chunk() append should not overwrite source slice")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Chunk() with size 0 should panic")
+		}
+	}()
+	Chunk(src, 0)
+}
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name  string
+		slice []int
+		size  int
+		want  [][]int
+	}{
+		{"Normal", []int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {2, 3}, {3, 4}}},
+		{"SizeEqualsLen", []int{1, 2, 3}, 3, [][]int{{1, 2, 3}}},
+		{"TooShort", []int{1, 2}, 3, [][]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Window(tt.slice, tt.size); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Window() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZipAndUnzip(t *testing.T) {
+	pairs := Zip([]int{1, 2, 3}, []string{"a", "b"})
+	want := []Pair[int, string]{{1, "a"}, {2, "b"}}
+	if !reflect.DeepEqual(pairs, want) {
+		t.Errorf("Zip() = %v, want %v", pairs, want)
+	}
+
+	a, b := Unzip(pairs)
+	if !reflect.DeepEqual(a, []int{1, 2}) || !reflect.DeepEqual(b, []string{"a", "b"}) {
+		t.Errorf("Unzip() = %v, %v", a, b)
+	}
+}
+
+func TestDistinctBy(t *testing.T) {
+	got := DistinctBy(testUsers, func(u testUser) int { return u.Age })
+	want := []testUser{testUsers[0], testUsers[1], testUsers[3]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DistinctBy() = %v, want %v", got, want)
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	users := append([]testUser(nil), testUsers...)
+	SortBy(users, func(u testUser) int { return u.Age })
+	want := []testUser{testUsers[3], testUsers[1], testUsers[0], testUsers[2]}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("SortBy() = %v, want %v", users, want)
+	}
+
+	SortBy(users, func(u testUser) string { return u.Name })
+	if users[0].Name != "alice" || users[3].Name != "dave" {
+		t.Errorf("SortBy() by name = %v", users)
+	}
+}
+
+func TestMinByAndMaxBy(t *testing.T) {
+	age := func(u testUser) int { return u.Age }
+	if u, ok := MinBy(testUsers, age); !ok || u.Name != "dave" {
+		t.Errorf("MinBy() = %v, %v", u, ok)
+	}
+	// 键相同时返回第一个
+	if u, ok := MaxBy(testUsers, age); !ok || u.Name != "alice" {
+		t.Errorf("MaxBy() = %v, %v", u, ok)
+	}
+	if _, ok := MinBy([]testUser{}, age); ok {
+		t.Error("MinBy() on empty slice should return false")
+	}
+	if _, ok := MaxBy([]testUser{}, age); ok {
+		t.Error("MaxBy() on empty slice should return false")
+	}
+}
+
+func TestFindAndFindLast(t *testing.T) {
+	if v, ok := Find([]int{1, 2, 3, 4}, isEven); !ok || v != 2 {
+		t.Errorf("Find() = %v, %v", v, ok)
+	}
+	if v, ok := FindLast([]int{1, 2, 3, 4, 5}, isEven); !ok || v != 4 {
+		t.Errorf("FindLast() = %v, %v", v, ok)
+	}
+	if _, ok := Find([]int{1, 3}, isEven); ok {
+		t.Error("Find() should return false when nothing matches")
+	}
+	if _, ok := FindLast([]int{}, isEven); ok {
+		t.Error("FindLast() on empty slice should return false")
+	}
+}
+
+func TestEveryAndSome(t *testing.T) {
+	tests := []struct {
+		name      string
+		slice     []int
+		wantEvery bool
+		wantSome  bool
+	}{
+		{"AllMatch", []int{2, 4}, true, true},
+		{"SomeMatch", []int{1, 2}, false, true},
+		{"NoneMatch", []int{1, 3}, false, false},
+		{"EmptySlice", []int{}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Every(tt.slice, isEven); got != tt.wantEvery {
+				t.Errorf("Every() = %v, want %v", got, tt.wantEvery)
+			}
+			if got := Some(tt.slice, isEven); got != tt.wantSome {
+				t.Errorf("Some() = %v, want %v", got, tt.wantSome)
+			}
+		})
+	}
+}
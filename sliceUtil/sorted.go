@@ -0,0 +1,159 @@
+package sliceUtil
+
+import (
+	"cmp"
+	"container/heap"
+)
+
+// BinarySearch 在升序切片中查找目标值，返回目标值的位置（不存在时为插入位置）以及是否找到
+// 存在多个相等元素时返回第一个的位置
+func BinarySearch[T cmp.Ordered](sorted []T, target T) (int, bool) {
+	return BinarySearchFunc(sorted, target, cmp.Less[T])
+}
+
+// BinarySearchFunc 在按 less 排序的切片中查找目标值
+func BinarySearchFunc[T any](sorted []T, target T, less func(a, b T) bool) (int, bool) {
+	i := LowerBoundFunc(sorted, target, less)
+	return i, i < len(sorted) && !less(target, sorted[i])
+}
+
+// LowerBound 返回升序切片中第一个大于等于 target 的元素位置，不存在时返回 len(sorted)
+func LowerBound[T cmp.Ordered](sorted []T, target T) int {
+	return LowerBoundFunc(sorted, target, cmp.Less[T])
+}
+
+// LowerBoundFunc 返回按 less 排序的切片中第一个不小于 target 的元素位置
+func LowerBoundFunc[T any](sorted []T, target T, less func(a, b T) bool) int {
+	lo, hi := 0, len(sorted)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if less(sorted[mid], target) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// UpperBound 返回升序切片中第一个大于 target 的元素位置，不存在时返回 len(sorted)
+func UpperBound[T cmp.Ordered](sorted []T, target T) int {
+	return UpperBoundFunc(sorted, target, cmp.Less[T])
+}
+
+// UpperBoundFunc 返回按 less 排序的切片中第一个大于 target 的元素位置
+func UpperBoundFunc[T any](sorted []T, target T, less func(a, b T) bool) int {
+	lo, hi := 0, len(sorted)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if less(target, sorted[mid]) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+// InsertSorted 将元素插入升序切片并保持有序，相等元素插入到已有元素之后
+// 例: InsertSorted([]int{1, 3, 5}, 4) // [1 3 4 5]
+func InsertSorted[T cmp.Ordered](sorted []T, value T) []T {
+	return InsertSortedFunc(sorted, value, cmp.Less[T])
+}
+
+// InsertSortedFunc 将元素插入按 less 排序的切片并保持有序
+func InsertSortedFunc[T any](sorted []T, value T, less func(a, b T) bool) []T {
+	i := UpperBoundFunc(sorted, value, less)
+	var zero T
+	sorted = append(sorted, zero)
+	copy(sorted[i+1:], sorted[i:])
+	sorted[i] = value
+	return sorted
+}
+
+// MergeSorted 多路归并多个升序切片，返回新的升序切片，相等元素按切片的先后顺序排列
+// 例: MergeSorted([]int{1, 4}, []int{2, 5}, []int{3}) // [1 2 3 4 5]
+func MergeSorted[T cmp.Ordered](slices ...[]T) []T {
+	return MergeSortedFunc(cmp.Less[T], slices...)
+}
+
+// MergeSortedFunc 多路归并多个按 less 排序的切片
+func MergeSortedFunc[T any](less func(a, b T) bool, slices ...[]T) []T {
+	total := 0
+	h := &mergeHeap[T]{less: less}
+	for i, s := range slices {
+		total += len(s)
+		if len(s) > 0 {
+			h.items = append(h.items, mergeCursor{slice: i})
+		}
+	}
+	h.slices = slices
+	heap.Init(h)
+
+	result := make([]T, 0, total)
+	for h.Len() > 0 {
+		c := &h.items[0]
+		result = append(result, slices[c.slice][c.pos])
+		c.pos++
+		if c.pos < len(slices[c.slice]) {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return result
+}
+
+// IsSorted 判断切片是否为升序
+func IsSorted[T cmp.Ordered](slice []T) bool {
+	return IsSortedFunc(slice, cmp.Less[T])
+}
+
+// IsSortedFunc 判断切片是否按 less 排序
+func IsSortedFunc[T any](slice []T, less func(a, b T) bool) bool {
+	for i := 1; i < len(slice); i++ {
+		if less(slice[i], slice[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeCursor 多路归并中某个切片的当前位置
+type mergeCursor struct {
+	slice int // 切片下标
+	pos   int // 当前元素下标
+}
+
+// mergeHeap 多路归并使用的小顶堆，实现 heap.Interface
+type mergeHeap[T any] struct {
+	slices [][]T
+	items  []mergeCursor
+	less   func(a, b T) bool
+}
+
+func (h *mergeHeap[T]) Len() int { return len(h.items) }
+
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	va, vb := h.slices[a.slice][a.pos], h.slices[b.slice][b.pos]
+	if h.less(va, vb) {
+		return true
+	}
+	if h.less(vb, va) {
+		return false
+	}
+	// 相等时按切片顺序，保证稳定
+	return a.slice < b.slice
+}
+
+func (h *mergeHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *mergeHeap[T]) Push(x any) { h.items = append(h.items, x.(mergeCursor)) }
+
+func (h *mergeHeap[T]) Pop() any {
+	n := len(h.items)
+	c := h.items[n-1]
+	h.items = h.items[:n-1]
+	return c
+}
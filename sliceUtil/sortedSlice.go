@@ -0,0 +1,357 @@
+package sliceUtil
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/Tomatosky/jo-util/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var _ bson.ValueMarshaler = (*SortedSlice[int])(nil)
+var _ bson.ValueUnmarshaler = (*SortedSlice[int])(nil)
+var _ json.Marshaler = (*SortedSlice[int])(nil)
+var _ json.Unmarshaler = (*SortedSlice[int])(nil)
+
+var errSortedSliceNoLess = errors.New("SortedSlice elements have no natural order, create it with NewSortedSlice")
+
+// SortedSlice 线程安全的有序切片，插入时保持顺序，允许重复元素
+// 零值可直接使用，元素为数字或字符串（包括以其为底层类型的自定义类型）时按自然顺序（升序）排列
+// 因此 *SortedSlice[T] 类型的结构体字段可以直接通过 json 或 bson 反序列化
+type SortedSlice[T any] struct {
+	mu   sync.RWMutex
+	data []T
+	less func(a, b T) bool // 元素比较函数，零值时在第一次使用时设置为自然顺序
+	once sync.Once
+}
+
+// NewSortedSlice 构造函数
+// less: 用于比较元素的函数，确定元素的顺序
+func NewSortedSlice[T any](less func(a, b T) bool, elements ...T) *SortedSlice[T] {
+	s := &SortedSlice[T]{less: less}
+	s.AddAll(elements...)
+	return s
+}
+
+// NewOrderedSortedSlice 使用元素的自然顺序（升序）创建有序切片
+func NewOrderedSortedSlice[T cmp.Ordered](elements ...T) *SortedSlice[T] {
+	return NewSortedSlice(cmp.Less[T], elements...)
+}
+
+// lessFunc 返回元素比较函数，零值的有序切片在元素可按自然顺序比较时使用升序，否则返回nil
+func (s *SortedSlice[T]) lessFunc() func(a, b T) bool {
+	s.once.Do(func() {
+		if s.less == nil {
+			s.less = naturalLess[T]()
+		}
+	})
+	return s.less
+}
+
+// mustLess 返回元素比较函数，无法比较时panic
+func (s *SortedSlice[T]) mustLess() func(a, b T) bool {
+	less := s.lessFunc()
+	if less == nil {
+		logger.Log.Error(fmt.Sprintf("%v", errSortedSliceNoLess))
+		panic(errSortedSliceNoLess)
+	}
+	return less
+}
+
+// naturalLess 返回元素的自然顺序，元素的底层类型不是数字或字符串时返回nil
+// 常用的内置类型直接比较，自定义类型通过反射按底层类型比较
+func naturalLess[T any]() func(a, b T) bool {
+	var less any
+	switch any(*new(T)).(type) {
+	case int:
+		less = cmp.Less[int]
+	case int32:
+		less = cmp.Less[int32]
+	case int64:
+		less = cmp.Less[int64]
+	case uint32:
+		less = cmp.Less[uint32]
+	case uint64:
+		less = cmp.Less[uint64]
+	case float64:
+		less = cmp.Less[float64]
+	case string:
+		less = cmp.Less[string]
+	}
+	if less != nil {
+		return less.(func(a, b T) bool)
+	}
+	rt := reflect.TypeFor[T]()
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b T) bool { return reflect.ValueOf(a).Int() < reflect.ValueOf(b).Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b T) bool { return reflect.ValueOf(a).Uint() < reflect.ValueOf(b).Uint() }
+	case reflect.Float32, reflect.Float64:
+		return func(a, b T) bool { return cmp.Less(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float()) }
+	case reflect.String:
+		return func(a, b T) bool { return reflect.ValueOf(a).String() < reflect.ValueOf(b).String() }
+	default:
+		return nil
+	}
+}
+
+// Add 插入元素，相等元素插入到已有元素之后，返回插入位置
+func (s *SortedSlice[T]) Add(element T) int {
+	less := s.mustLess()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := UpperBoundFunc(s.data, element, less)
+	s.data = InsertSortedFunc(s.data, element, less)
+	return i
+}
+
+// AddAll 批量插入元素，排序一次后与已有元素归并
+func (s *SortedSlice[T]) AddAll(elements ...T) {
+	if len(elements) == 0 {
+		return
+	}
+	less := s.mustLess()
+	sorted := sortedCopy(elements, less)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = MergeSortedFunc(less, s.data, sorted)
+}
+
+// sortedCopy 返回按 less 稳定排序的副本
+func sortedCopy[T any](elements []T, less func(a, b T) bool) []T {
+	sorted := append([]T(nil), elements...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(sorted[i], sorted[j])
+	})
+	return sorted
+}
+
+// Remove 移除一个与 element 相等的元素，返回是否移除
+func (s *SortedSlice[T]) Remove(element T) bool {
+	less := s.mustLess()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, found := BinarySearchFunc(s.data, element, less)
+	if !found {
+		return false
+	}
+	// slices.Delete 会清零移出的尾部，避免继续引用已移除的元素
+	s.data = slices.Delete(s.data, i, i+1)
+	return true
+}
+
+// RemoveAt 移除指定索引的元素，支持负数索引
+func (s *SortedSlice[T]) RemoveAt(index int) T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if index < 0 {
+		index += len(s.data)
+	}
+	if index < 0 || index >= len(s.data) {
+		logger.Log.Error(fmt.Sprintf("%v", "index out of range"))
+		panic("index out of range")
+	}
+	removed := s.data[index]
+	s.data = slices.Delete(s.data, index, index+1)
+	return removed
+}
+
+// RemoveRange 移除区间 [from, to) 内的元素，返回移除的数量
+// 例: 清理过期的时间戳 s.RemoveRange(0, expireBefore)
+func (s *SortedSlice[T]) RemoveRange(from, to T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lo, hi := s.bounds(from, to)
+	if lo >= hi {
+		return 0
+	}
+	s.data = slices.Delete(s.data, lo, hi)
+	return hi - lo
+}
+
+// Get 获取元素，支持负数索引
+func (s *SortedSlice[T]) Get(index int) T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if index < 0 {
+		index += len(s.data)
+	}
+	if index < 0 || index >= len(s.data) {
+		logger.Log.Error(fmt.Sprintf("%v", "index out of range"))
+		panic("index out of range")
+	}
+	return s.data[index]
+}
+
+// IndexOf 返回第一个与 element 相等的元素位置，不存在时返回-1
+func (s *SortedSlice[T]) IndexOf(element T) int {
+	less := s.mustLess()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if i, found := BinarySearchFunc(s.data, element, less); found {
+		return i
+	}
+	return -1
+}
+
+// Contains 检查元素是否存在
+func (s *SortedSlice[T]) Contains(element T) bool {
+	return s.IndexOf(element) >= 0
+}
+
+// First 返回最小的元素
+func (s *SortedSlice[T]) First() (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.data) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.data[0], true
+}
+
+// Last 返回最大的元素
+func (s *SortedSlice[T]) Last() (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.data) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.data[len(s.data)-1], true
+}
+
+// SubSlice 返回区间 [from, to) 内的元素副本
+func (s *SortedSlice[T]) SubSlice(from, to T) []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	lo, hi := s.bounds(from, to)
+	if lo >= hi {
+		return make([]T, 0)
+	}
+	return append(make([]T, 0, hi-lo), s.data[lo:hi]...)
+}
+
+// HeadSlice 返回小于 to 的元素副本
+func (s *SortedSlice[T]) HeadSlice(to T) []T {
+	less := s.mustLess()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	hi := LowerBoundFunc(s.data, to, less)
+	return append(make([]T, 0, hi), s.data[:hi]...)
+}
+
+// TailSlice 返回大于等于 from 的元素副本
+func (s *SortedSlice[T]) TailSlice(from T) []T {
+	less := s.mustLess()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	lo := LowerBoundFunc(s.data, from, less)
+	return append(make([]T, 0, len(s.data)-lo), s.data[lo:]...)
+}
+
+// CountRange 返回区间 [from, to) 内的元素数量
+func (s *SortedSlice[T]) CountRange(from, to T) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	lo, hi := s.bounds(from, to)
+	return max(hi-lo, 0)
+}
+
+// bounds 返回区间 [from, to) 对应的下标范围，调用时需持有锁
+func (s *SortedSlice[T]) bounds(from, to T) (int, int) {
+	less := s.mustLess()
+	return LowerBoundFunc(s.data, from, less), LowerBoundFunc(s.data, to, less)
+}
+
+// Size 当前元素数量
+func (s *SortedSlice[T]) Size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.data)
+}
+
+// IsEmpty 判断是否为空
+func (s *SortedSlice[T]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// Clear 清空
+func (s *SortedSlice[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = nil
+}
+
+// Range 按顺序遍历当前快照（返回false可提前终止）
+func (s *SortedSlice[T]) Range(f func(int, T) bool) {
+	for i, v := range s.ToSlice() {
+		if !f(i, v) {
+			break
+		}
+	}
+}
+
+// ToSlice 返回有序的元素副本
+func (s *SortedSlice[T]) ToSlice() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append(make([]T, 0, len(s.data)), s.data...)
+}
+
+func (s *SortedSlice[T]) ToString() string {
+	bytes, err := json.Marshal(s.ToSlice())
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("%v", err))
+		panic(err)
+	}
+	return string(bytes)
+}
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (s *SortedSlice[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口，零值的有序切片按自然顺序排列，元素无法比较时返回错误
+func (s *SortedSlice[T]) UnmarshalJSON(data []byte) error {
+	var elements []T
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+	return s.reset(elements)
+}
+
+func (s *SortedSlice[T]) MarshalBSONValue() (byte, []byte, error) {
+	elements := s.ToSlice()
+	typ, data, err := bson.MarshalValue(elements)
+	return byte(typ), data, err
+}
+
+func (s *SortedSlice[T]) UnmarshalBSONValue(t byte, data []byte) error {
+	var elements []T
+	if err := bson.UnmarshalValue(bson.Type(t), data, &elements); err != nil {
+		return err
+	}
+	return s.reset(elements)
+}
+
+// reset 使用 elements 替换全部数据，排序后在一次加锁中替换，读取方不会看到中间状态
+func (s *SortedSlice[T]) reset(elements []T) error {
+	less := s.lessFunc()
+	if less == nil {
+		return errSortedSliceNoLess
+	}
+	sorted := sortedCopy(elements, less)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = sorted
+	return nil
+}
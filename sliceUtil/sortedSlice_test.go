@@ -0,0 +1,195 @@
+package sliceUtil
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSortedSliceAddAndRemove(t *testing.T) {
+	s := NewOrderedSortedSlice(5, 1, 3)
+	if !reflect.DeepEqual(s.ToSlice(), []int{1, 3, 5}) {
+		t.Errorf("Unexpected elements %v", s.ToSlice())
+	}
+
+	if i := s.Add(4); i != 2 {
+		t.Errorf("Expected insert position 2, got %d", i)
+	}
+	s.Add(3)
+	s.AddAll(6, 0, 2)
+	if !reflect.DeepEqual(s.ToSlice(), []int{0, 1, 2, 3, 3, 4, 5, 6}) {
+		t.Errorf("Unexpected elements %v", s.ToSlice())
+	}
+
+	if !s.Remove(3) || s.IndexOf(3) != 3 {
+		t.Error("Expected one 3 removed")
+	}
+	if s.Remove(10) {
+		t.Error("Expected remove of missing element to fail")
+	}
+	if s.RemoveAt(-1) != 6 || s.RemoveAt(0) != 0 {
+		t.Error("Unexpected RemoveAt result")
+	}
+	if !reflect.DeepEqual(s.ToSlice(), []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Unexpected elements %v", s.ToSlice())
+	}
+	if s.Get(0) != 1 || s.Get(-1) != 5 || s.Size() != 5 {
+		t.Error("Unexpected Get/Size result")
+	}
+	if first, _ := s.First(); first != 1 {
+		t.Errorf("Expected first 1, got %d", first)
+	}
+	if last, _ := s.Last(); last != 5 {
+		t.Errorf("Expected last 5, got %d", last)
+	}
+	if !s.Contains(4) || s.Contains(7) || s.IndexOf(7) != -1 {
+		t.Error("Unexpected Contains/IndexOf result")
+	}
+
+	s.Clear()
+	if !s.IsEmpty() {
+		t.Error("Expected empty after Clear")
+	}
+	if _, ok := s.First(); ok {
+		t.Error("Expected no first element")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic on out of range index")
+		}
+	}()
+	s.Get(0)
+}
+
+func TestSortedSliceRangeQueries(t *testing.T) {
+	s := NewOrderedSortedSlice(10, 20, 20, 30, 40, 50)
+
+	if got := s.SubSlice(20, 40); !reflect.DeepEqual(got, []int{20, 20, 30}) {
+		t.Errorf("SubSlice() = %v", got)
+	}
+	if got := s.SubSlice(40, 20); len(got) != 0 {
+		t.Errorf("SubSlice() with from > to = %v", got)
+	}
+	if got := s.HeadSlice(30); !reflect.DeepEqual(got, []int{10, 20, 20}) {
+		t.Errorf("HeadSlice() = %v", got)
+	}
+	if got := s.TailSlice(35); !reflect.DeepEqual(got, []int{40, 50}) {
+		t.Errorf("TailSlice() = %v", got)
+	}
+	if got := s.CountRange(15, 45); got != 4 {
+		t.Errorf("CountRange() = %v, want 4", got)
+	}
+	if got := s.RemoveRange(0, 25); got != 3 {
+		t.Errorf("RemoveRange() = %v, want 3", got)
+	}
+	if !reflect.DeepEqual(s.ToSlice(), []int{30, 40, 50}) {
+		t.Errorf("Unexpected elements after RemoveRange %v", s.ToSlice())
+	}
+
+	var visited []int
+	s.Range(func(i int, v int) bool {
+		visited = append(visited, v)
+		return i < 1
+	})
+	if !reflect.DeepEqual(visited, []int{30, 40}) {
+		t.Errorf("Range() visited %v", visited)
+	}
+}
+
+func TestSortedSliceCustomLess(t *testing.T) {
+	s := NewSortedSlice(func(a, b testUser) bool { return a.Age > b.Age }, testUsers...)
+	names := Map(s.ToSlice(), func(u testUser) string { return u.Name })
+	if !reflect.DeepEqual(names, []string{"alice", "carol", "bob", "dave"}) {
+		t.Errorf("Unexpected order %v", names)
+	}
+}
+
+func TestSortedSliceJSON(t *testing.T) {
+	s := NewOrderedSortedSlice(3, 1, 2)
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "[1,2,3]" || s.ToString() != "[1,2,3]" {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	restored := NewOrderedSortedSlice[int]()
+	if err := json.Unmarshal([]byte("[5,4,6]"), restored); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(restored.ToSlice(), []int{4, 5, 6}) {
+		t.Errorf("Unexpected elements %v", restored.ToSlice())
+	}
+
+	// 零值按自然顺序反序列化，指针字段可以直接往返
+	type holder struct {
+		Scores *SortedSlice[int]      `json:"scores"`
+		Users  *SortedSlice[testUser] `json:"users,omitempty"`
+	}
+	var h holder
+	if err := json.Unmarshal([]byte(`{"scores":[3,1,2]}`), &h); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(h.Scores.ToSlice(), []int{1, 2, 3}) {
+		t.Errorf("Unexpected elements %v", h.Scores.ToSlice())
+	}
+	h.Scores.Add(0)
+	if out, _ := json.Marshal(h); string(out) != `{"scores":[0,1,2,3]}` {
+		t.Errorf("Unexpected round trip %s", out)
+	}
+	type level int
+	var levels SortedSlice[level]
+	if err := json.Unmarshal([]byte("[2,-1,1]"), &levels); err != nil || !reflect.DeepEqual(levels.ToSlice(), []level{-1, 1, 2}) {
+		t.Errorf("Unexpected named type elements %v, %v", levels.ToSlice(), err)
+	}
+	if err := json.Unmarshal([]byte(`{"users":[{}]}`), &h); err == nil {
+		t.Error("Expected error when elements have no natural order")
+	}
+	if got := NewOrderedSortedSlice[int]().ToString(); got != "[]" {
+		t.Errorf("Expected [] for empty slice, got %s", got)
+	}
+}
+
+func TestSortedSliceBSON(t *testing.T) {
+	original := NewOrderedSortedSlice("b", "c", "a")
+	bsonType, data, err := original.MarshalBSONValue()
+	if err != nil {
+		t.Fatalf("MarshalBSONValue failed: %v", err)
+	}
+
+	restored := NewOrderedSortedSlice[string]()
+	if err := restored.UnmarshalBSONValue(bsonType, data); err != nil {
+		t.Fatalf("UnmarshalBSONValue failed: %v", err)
+	}
+	if !reflect.DeepEqual(restored.ToSlice(), []string{"a", "b", "c"}) {
+		t.Errorf("Unexpected elements %v", restored.ToSlice())
+	}
+
+	var zero SortedSlice[string]
+	if err := zero.UnmarshalBSONValue(bsonType, data); err != nil || !reflect.DeepEqual(zero.ToSlice(), []string{"a", "b", "c"}) {
+		t.Errorf("Zero value should unmarshal in natural order, got %v, %v", zero.ToSlice(), err)
+	}
+}
+
+func TestSortedSliceConcurrent(t *testing.T) {
+	s := NewOrderedSortedSlice[int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func(v int) {
+			defer wg.Done()
+			s.Add(v)
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = s.SubSlice(10, 50)
+		}()
+	}
+	wg.Wait()
+	if s.Size() != 100 || !IsSorted(s.ToSlice()) {
+		t.Errorf("Expected 100 sorted elements, got %v", s.ToSlice())
+	}
+}
@@ -0,0 +1,140 @@
+package sliceUtil
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBinarySearchAndBounds(t *testing.T) {
+	sorted := []int{1, 3, 3, 3, 5, 7}
+	tests := []struct {
+		name      string
+		target    int
+		wantIndex int
+		wantFound bool
+		wantLower int
+		wantUpper int
+	}{
+		{"Duplicates", 3, 1, true, 1, 4},
+		{"First", 1, 0, true, 0, 1},
+		{"Last", 7, 5, true, 5, 6},
+		{"Missing", 4, 4, false, 4, 4},
+		{"BeforeAll", 0, 0, false, 0, 0},
+		{"AfterAll", 9, 6, false, 6, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i, found := BinarySearch(sorted, tt.target)
+			if i != tt.wantIndex || found != tt.wantFound {
+				t.Errorf("BinarySearch() = %v, %v, want %v, %v", i, found, tt.wantIndex, tt.wantFound)
+			}
+			if got := LowerBound(sorted, tt.target); got != tt.wantLower {
+				t.Errorf("LowerBound() = %v, want %v", got, tt.wantLower)
+			}
+			if got := UpperBound(sorted, tt.target); got != tt.wantUpper {
+				t.Errorf("UpperBound() = %v, want %v", got, tt.wantUpper)
+			}
+		})
+	}
+
+	if i, found := BinarySearch([]int{}, 1); i != 0 || found {
+		t.Errorf("BinarySearch() on empty slice = %v, %v", i, found)
+	}
+}
+
+func TestBinarySearchFunc(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := []time.Time{base, base.Add(time.Hour), base.Add(2 * time.Hour)}
+	before := func(a, b time.Time) bool { return a.Before(b) }
+
+	i, found := BinarySearchFunc(times, base.Add(time.Hour), before)
+	if i != 1 || !found {
+		t.Errorf("BinarySearchFunc() = %v, %v", i, found)
+	}
+	if got := LowerBoundFunc(times, base.Add(30*time.Minute), before); got != 1 {
+		t.Errorf("LowerBoundFunc() = %v, want 1", got)
+	}
+	if got := UpperBoundFunc(times, base.Add(2*time.Hour), before); got != 3 {
+		t.Errorf("UpperBoundFunc() = %v, want 3", got)
+	}
+}
+
+func TestInsertSorted(t *testing.T) {
+	tests := []struct {
+		name   string
+		sorted []int
+		value  int
+		want   []int
+	}{
+		{"Middle", []int{1, 3, 5}, 4, []int{1, 3, 4, 5}},
+		{"Front", []int{1, 3, 5}, 0, []int{0, 1, 3, 5}},
+		{"Back", []int{1, 3, 5}, 6, []int{1, 3, 5, 6}},
+		{"Duplicate", []int{1, 3, 5}, 3, []int{1, 3, 3, 5}},
+		{"Empty", []int{}, 1, []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InsertSorted(tt.sorted, tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InsertSorted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 相等元素插入到已有元素之后
+	users := []testUser{{"a", 20}, {"b", 30}}
+	users = InsertSortedFunc(users, testUser{"c", 20}, func(a, b testUser) bool { return a.Age < b.Age })
+	if users[1].Name != "c" {
+		t.Errorf("InsertSortedFunc() = %v", users)
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	got := MergeSorted([]int{1, 4, 7}, []int{}, []int{2, 5, 8}, []int{3, 6, 9, 10})
+	want := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeSorted() = %v, want %v", got, want)
+	}
+	if got := MergeSorted[int](); len(got) != 0 {
+		t.Errorf("MergeSorted() with no input = %v", got)
+	}
+
+	// 相等元素按切片顺序排列
+	byAge := func(a, b testUser) bool { return a.Age < b.Age }
+	merged := MergeSortedFunc(byAge,
+		[]testUser{{"a", 20}, {"b", 30}},
+		[]testUser{{"c", 20}, {"d", 25}},
+	)
+	names := Map(merged, func(u testUser) string { return u.Name })
+	if !reflect.DeepEqual(names, []string{"a", "c", "d", "b"}) {
+		t.Errorf("MergeSortedFunc() = %v", names)
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	tests := []struct {
+		name  string
+		slice []int
+		want  bool
+	}{
+		{"Sorted", []int{1, 2, 2, 3}, true},
+		{"Unsorted", []int{1, 3, 2}, false},
+		{"Empty", []int{}, true},
+		{"Single", []int{1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsSorted(tt.slice); got != tt.want {
+				t.Errorf("IsSorted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	desc := func(a, b int) bool { return a > b }
+	if !IsSortedFunc([]int{3, 2, 1}, desc) || IsSortedFunc([]int{1, 2}, desc) {
+		t.Error("IsSortedFunc() returned unexpected result")
+	}
+}
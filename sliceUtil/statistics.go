@@ -0,0 +1,220 @@
+package sliceUtil
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Tomatosky/jo-util/logger"
+	"github.com/Tomatosky/jo-util/numberUtil"
+)
+
+// ErrEmptySlice 输入切片为空
+var ErrEmptySlice = errors.New("slice is empty")
+
+// HistogramBucket 直方图的一个区间 [Min, Max)，最后一个区间包含 Max
+type HistogramBucket struct {
+	Min   float64 `json:"min" bson:"min"`
+	Max   float64 `json:"max" bson:"max"`
+	Count int     `json:"count" bson:"count"`
+}
+
+// Average 平均值，切片为空时panic
+func Average[T numberUtil.Real](nums []T) float64 {
+	return mustStat(TryAverage(nums))
+}
+
+// TryAverage 平均值，切片为空时返回 ErrEmptySlice
+func TryAverage[T numberUtil.Real](nums []T) (float64, error) {
+	if len(nums) == 0 {
+		return 0, ErrEmptySlice
+	}
+	sum := 0.0
+	for _, v := range nums {
+		sum += float64(v)
+	}
+	return sum / float64(len(nums)), nil
+}
+
+// Median 中位数，元素个数为偶数时取中间两个数的平均值，切片为空时panic
+func Median[T numberUtil.Real](nums []T) float64 {
+	return mustStat(TryMedian(nums))
+}
+
+// TryMedian 中位数，切片为空时返回 ErrEmptySlice
+func TryMedian[T numberUtil.Real](nums []T) (float64, error) {
+	return TryPercentile(nums, 50)
+}
+
+// Percentile 百分位数，p 取值范围 [0, 100]，使用线性插值，切片为空时panic
+// 例: Percentile(latencies, 99) // P99
+func Percentile[T numberUtil.Real](nums []T, p float64) float64 {
+	return mustStat(TryPercentile(nums, p))
+}
+
+// TryPercentile 百分位数，切片为空时返回 ErrEmptySlice
+func TryPercentile[T numberUtil.Real](nums []T, p float64) (float64, error) {
+	result, err := TryPercentiles(nums, p)
+	if err != nil {
+		return 0, err
+	}
+	return result[0], nil
+}
+
+// Percentiles 一次计算多个百分位数，只排序一次，切片为空时panic
+// 例: Percentiles(latencies, 50, 90, 99) // [P50, P90, P99]
+func Percentiles[T numberUtil.Real](nums []T, ps ...float64) []float64 {
+	return mustStat(TryPercentiles(nums, ps...))
+}
+
+// TryPercentiles 一次计算多个百分位数，切片为空时返回 ErrEmptySlice
+func TryPercentiles[T numberUtil.Real](nums []T, ps ...float64) ([]float64, error) {
+	if len(nums) == 0 {
+		return nil, ErrEmptySlice
+	}
+	for _, p := range ps {
+		if p < 0 || p > 100 || math.IsNaN(p) {
+			return nil, fmt.Errorf("percentile out of range: %v", p)
+		}
+	}
+	sorted := sortedFloats(nums)
+	result := make([]float64, len(ps))
+	for i, p := range ps {
+		result[i] = percentileOfSorted(sorted, p)
+	}
+	return result, nil
+}
+
+// Variance 总体方差，切片为空时panic
+func Variance[T numberUtil.Real](nums []T) float64 {
+	return mustStat(TryVariance(nums))
+}
+
+// TryVariance 总体方差，切片为空时返回 ErrEmptySlice
+func TryVariance[T numberUtil.Real](nums []T) (float64, error) {
+	if len(nums) == 0 {
+		return 0, ErrEmptySlice
+	}
+	// Welford 算法，避免大数相减带来的精度损失
+	mean, m2 := 0.0, 0.0
+	for i, v := range nums {
+		x := float64(v)
+		delta := x - mean
+		mean += delta / float64(i+1)
+		m2 += delta * (x - mean)
+	}
+	return m2 / float64(len(nums)), nil
+}
+
+// StdDev 总体标准差，切片为空时panic
+func StdDev[T numberUtil.Real](nums []T) float64 {
+	return mustStat(TryStdDev(nums))
+}
+
+// TryStdDev 总体标准差，切片为空时返回 ErrEmptySlice
+func TryStdDev[T numberUtil.Real](nums []T) (float64, error) {
+	variance, err := TryVariance(nums)
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(variance), nil
+}
+
+// Mode 众数，出现次数相同时返回所有众数（升序），切片为空时panic
+func Mode[T numberUtil.Real](nums []T) []T {
+	return mustStat(TryMode(nums))
+}
+
+// TryMode 众数，切片为空时返回 ErrEmptySlice
+func TryMode[T numberUtil.Real](nums []T) ([]T, error) {
+	if len(nums) == 0 {
+		return nil, ErrEmptySlice
+	}
+	counts := make(map[T]int)
+	maxCount := 0
+	for _, v := range nums {
+		counts[v]++
+		maxCount = max(maxCount, counts[v])
+	}
+	result := make([]T, 0)
+	for v, c := range counts {
+		if c == maxCount {
+			result = append(result, v)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result, nil
+}
+
+// Histogram 在最小值和最大值之间划分 bucketCount 个等宽区间并统计每个区间的元素数量，切片为空或包含 NaN、Inf 时panic
+func Histogram[T numberUtil.Real](nums []T, bucketCount int) []HistogramBucket {
+	return mustStat(TryHistogram(nums, bucketCount))
+}
+
+// TryHistogram 等宽直方图，切片为空时返回 ErrEmptySlice，包含 NaN、Inf 时返回错误
+func TryHistogram[T numberUtil.Real](nums []T, bucketCount int) ([]HistogramBucket, error) {
+	if len(nums) == 0 {
+		return nil, ErrEmptySlice
+	}
+	if bucketCount <= 0 {
+		return nil, fmt.Errorf("bucket count must be greater than 0")
+	}
+	lo, hi := float64(nums[0]), float64(nums[0])
+	for _, v := range nums {
+		if f := float64(v); math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("histogram does not support non-finite value %v", f)
+		}
+		lo = min(lo, float64(v))
+		hi = max(hi, float64(v))
+	}
+	// 分别缩放再相减，避免 hi-lo 超出 float64 范围
+	width := hi/float64(bucketCount) - lo/float64(bucketCount)
+	buckets := make([]HistogramBucket, bucketCount)
+	for i := range buckets {
+		buckets[i].Min = lo + width*float64(i)
+		buckets[i].Max = lo + width*float64(i+1)
+	}
+	buckets[bucketCount-1].Max = hi
+	for _, v := range nums {
+		idx := 0
+		if width > 0 {
+			// 同样减半后相减，避免 v-lo 溢出为 Inf
+			idx = min(max(int((float64(v)/2-lo/2)/(width/2)), 0), bucketCount-1)
+		}
+		buckets[idx].Count++
+	}
+	return buckets, nil
+}
+
+// sortedFloats 复制为 float64 切片并升序排序，不修改原切片
+func sortedFloats[T numberUtil.Real](nums []T) []float64 {
+	sorted := make([]float64, len(nums))
+	for i, v := range nums {
+		sorted[i] = float64(v)
+	}
+	sort.Float64s(sorted)
+	return sorted
+}
+
+// percentileOfSorted 在已排序的切片上按线性插值计算百分位数
+func percentileOfSorted(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// mustStat 出错时记录日志并panic
+func mustStat[R any](result R, err error) R {
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("%v", err))
+		panic(err)
+	}
+	return result
+}
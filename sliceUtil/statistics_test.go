@@ -0,0 +1,165 @@
+package sliceUtil
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func floatEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestAverage(t *testing.T) {
+	if got := Average([]int{1, 2, 3, 4}); got != 2.5 {
+		t.Errorf("Average() = %v, want 2.5", got)
+	}
+	if got := Average([]float64{0.5, 1.5}); got != 1 {
+		t.Errorf("Average() = %v, want 1", got)
+	}
+	if _, err := TryAverage([]int{}); !errors.Is(err, ErrEmptySlice) {
+		t.Errorf("TryAverage() error = %v, want ErrEmptySlice", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Average() on empty slice should panic")
+		}
+	}()
+	Average([]int{})
+}
+
+func TestMedianAndPercentile(t *testing.T) {
+	tests := []struct {
+		name string
+		nums []float64
+		p    float64
+		want float64
+	}{
+		{"MedianOdd", []float64{3, 1, 2}, 50, 2},
+		{"MedianEven", []float64{4, 1, 3, 2}, 50, 2.5},
+		{"Min", []float64{5, 1, 9}, 0, 1},
+		{"Max", []float64{5, 1, 9}, 100, 9},
+		{"Interpolated", []float64{1, 2, 3, 4, 5}, 90, 4.6},
+		{"Single", []float64{7}, 99, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Percentile(tt.nums, tt.p); !floatEqual(got, tt.want) {
+				t.Errorf("Percentile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	nums := []int{5, 3, 1, 4, 2}
+	if got := Median(nums); got != 3 {
+		t.Errorf("Median() = %v, want 3", got)
+	}
+	// 不修改原切片
+	if !reflect.DeepEqual(nums, []int{5, 3, 1, 4, 2}) {
+		t.Errorf("Median() modified input: %v", nums)
+	}
+
+	if _, err := TryPercentile(nums, 101); err == nil {
+		t.Error("TryPercentile() with p > 100 should return error")
+	}
+	if _, err := TryMedian([]float64{}); !errors.Is(err, ErrEmptySlice) {
+		t.Errorf("TryMedian() error = %v, want ErrEmptySlice", err)
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	// 使用 time.Duration 计算延迟报告
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(100-i) * time.Millisecond
+	}
+	got := Percentiles(latencies, 50, 90, 99)
+	want := []float64{50.5e6, 90.1e6, 99.01e6}
+	for i := range want {
+		if !floatEqual(got[i], want[i]) {
+			t.Errorf("Percentiles()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if _, err := TryPercentiles([]int{}, 50); !errors.Is(err, ErrEmptySlice) {
+		t.Errorf("TryPercentiles() error = %v, want ErrEmptySlice", err)
+	}
+}
+
+func TestVarianceAndStdDev(t *testing.T) {
+	nums := []int{2, 4, 4, 4, 5, 5, 7, 9}
+	if got := Variance(nums); !floatEqual(got, 4) {
+		t.Errorf("Variance() = %v, want 4", got)
+	}
+	if got := StdDev(nums); !floatEqual(got, 2) {
+		t.Errorf("StdDev() = %v, want 2", got)
+	}
+	if got := Variance([]float32{1.5}); got != 0 {
+		t.Errorf("Variance() of single element = %v, want 0", got)
+	}
+	if _, err := TryStdDev([]uint{}); !errors.Is(err, ErrEmptySlice) {
+		t.Errorf("TryStdDev() error = %v, want ErrEmptySlice", err)
+	}
+}
+
+func TestMode(t *testing.T) {
+	tests := []struct {
+		name string
+		nums []int
+		want []int
+	}{
+		{"Single", []int{1, 2, 2, 3}, []int{2}},
+		{"Multiple", []int{3, 1, 3, 1, 2}, []int{1, 3}},
+		{"AllUnique", []int{3, 2, 1}, []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Mode(tt.nums); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Mode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := TryMode([]int{}); !errors.Is(err, ErrEmptySlice) {
+		t.Errorf("TryMode() error = %v, want ErrEmptySlice", err)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	buckets := Histogram([]float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 10}, 5)
+	counts := make([]int, len(buckets))
+	for i, b := range buckets {
+		counts[i] = b.Count
+	}
+	if !reflect.DeepEqual(counts, []int{2, 2, 2, 2, 2}) {
+		t.Errorf("Histogram() counts = %v", counts)
+	}
+	if buckets[0].Min != 0 || buckets[0].Max != 2 || buckets[4].Max != 10 {
+		t.Errorf("Histogram() bounds = %v", buckets)
+	}
+
+	// 所有元素相同时全部落入第一个区间
+	same := Histogram([]int{3, 3, 3}, 3)
+	if same[0].Count != 3 || same[1].Count != 0 {
+		t.Errorf("Histogram() of equal values = %v", same)
+	}
+
+	if _, err := TryHistogram([]int{1}, 0); err == nil {
+		t.Error("TryHistogram() with 0 buckets should return error")
+	}
+	if _, err := TryHistogram([]int{}, 3); !errors.Is(err, ErrEmptySlice) {
+		t.Errorf("TryHistogram() error = %v, want ErrEmptySlice", err)
+	}
+	for _, v := range []float64{math.Inf(1), math.Inf(-1), math.NaN()} {
+		if _, err := TryHistogram([]float64{1, 2, v}, 4); err == nil {
+			t.Errorf("TryHistogram() with %v should return error", v)
+		}
+	}
+	// 最大值与最小值之差超出 float64 范围
+	wide := Histogram([]float64{-math.MaxFloat64, 0, math.MaxFloat64}, 2)
+	if wide[0].Count != 1 || wide[1].Count != 2 {
+		t.Errorf("Histogram() of full range = %v", wide)
+	}
+}
@@ -0,0 +1,384 @@
+package sliceUtil
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+	"sync"
+
+	"github.com/Tomatosky/jo-util/logger"
+)
+
+const defaultParallelBatchSize = 256 // 并行模式默认批大小
+
+// TaskSubmitter 并行模式使用的任务提交接口，poolUtil.IPool 的实现均满足该接口
+// 同时实现 TrySubmit(task func()) error（如 poolUtil.IdPool、poolUtil.AntsPool）时优先使用 TrySubmit，提交失败的任务在调用方直接执行
+// 只实现 Submit 时必须保证提交的任务一定会执行，否则并行流会一直等待
+// 注意：在有界线程池的 worker 中对同一个线程池执行并行流可能死锁，worker 会等待排在自己之后的任务
+type TaskSubmitter interface {
+	Submit(task func())
+}
+
+// taskTrySubmitter 可以返回提交错误的提交器，如 poolUtil.IdPool 和 poolUtil.AntsPool
+type taskTrySubmitter interface {
+	TrySubmit(task func()) error
+}
+
+// taskDiscardSubmitter 提交成功的任务之后被丢弃时能够通知的提交器，如 poolUtil.IdPool（见 poolUtil.DiscardNotifier）
+type taskDiscardSubmitter interface {
+	TrySubmitOnDiscard(task func(), onDiscard func()) error
+}
+
+// Ranger 可遍历元素的容器，setUtil 中的集合均满足该接口
+type Ranger[T any] interface {
+	Range(f func(T) bool)
+}
+
+// MapRanger 可遍历键值对的容器，mapUtil.IMap 的实现均满足该接口
+type MapRanger[K any, V any] interface {
+	Range(f func(key K, value V) bool)
+}
+
+// Stream 基于 iter.Seq 的惰性流，中间操作在终止操作执行时才会计算
+// 设置并行模式后，Filter、Peek 及 MapStream 会按批提交到任务池执行，结果保持原有顺序
+type Stream[T any] struct {
+	seq       iter.Seq[T]
+	pool      TaskSubmitter // 为nil时串行执行
+	batchSize int
+}
+
+// Of 根据元素创建流
+func Of[T any](elements ...T) *Stream[T] {
+	return FromSlice(elements)
+}
+
+// FromSlice 根据切片创建流
+func FromSlice[T any](slice []T) *Stream[T] {
+	return FromSeq[T](func(yield func(T) bool) {
+		for _, item := range slice {
+			if !yield(item) {
+				return
+			}
+		}
+	})
+}
+
+// FromSeq 根据迭代器创建流
+func FromSeq[T any](seq iter.Seq[T]) *Stream[T] {
+	return &Stream[T]{seq: seq}
+}
+
+// FromCopyOnWriteSlice 根据 CopyOnWriteSlice 创建流，每次遍历时使用当时的快照
+func FromCopyOnWriteSlice[T comparable](slice *CopyOnWriteSlice[T]) *Stream[T] {
+	return FromSeq[T](func(yield func(T) bool) {
+		slice.All()(yield)
+	})
+}
+
+// FromSet 根据集合创建流，如 setUtil.HashSet、setUtil.TreeSet
+func FromSet[T any](set Ranger[T]) *Stream[T] {
+	return FromSeq[T](func(yield func(T) bool) {
+		set.Range(yield)
+	})
+}
+
+// FromMap 根据 map 容器创建键值对流，如 mapUtil.ConcurrentHashMap、mapUtil.TreeMap
+func FromMap[K any, V any](m MapRanger[K, V]) *Stream[Pair[K, V]] {
+	return FromSeq[Pair[K, V]](func(yield func(Pair[K, V]) bool) {
+		m.Range(func(key K, value V) bool {
+			return yield(Pair[K, V]{First: key, Second: value})
+		})
+	})
+}
+
+// derive 以当前流的执行模式创建新的流
+func (s *Stream[T]) derive(seq iter.Seq[T]) *Stream[T] {
+	return &Stream[T]{seq: seq, pool: s.pool, batchSize: s.batchSize}
+}
+
+// Parallel 设置并行模式，后续的 Filter、Peek 及 MapStream 在 pool 中按批执行
+// batchSize 为可选的批大小，默认256
+func (s *Stream[T]) Parallel(pool TaskSubmitter, batchSize ...int) *Stream[T] {
+	size := defaultParallelBatchSize
+	if len(batchSize) > 0 && batchSize[0] > 0 {
+		size = batchSize[0]
+	}
+	return &Stream[T]{seq: s.seq, pool: pool, batchSize: size}
+}
+
+// Sequential 取消并行模式
+func (s *Stream[T]) Sequential() *Stream[T] {
+	return &Stream[T]{seq: s.seq}
+}
+
+// IsParallel 是否为并行模式
+func (s *Stream[T]) IsParallel() bool {
+	return s.pool != nil
+}
+
+// Filter 保留满足条件的元素
+func (s *Stream[T]) Filter(predicate func(T) bool) *Stream[T] {
+	if s.pool != nil {
+		return s.derive(parallelApply(s.seq, s.pool, s.batchSize, func(item T) (T, bool) {
+			return item, predicate(item)
+		}))
+	}
+	return s.derive(func(yield func(T) bool) {
+		for item := range s.seq {
+			if predicate(item) && !yield(item) {
+				return
+			}
+		}
+	})
+}
+
+// Peek 对每个经过的元素执行操作，常用于调试
+func (s *Stream[T]) Peek(action func(T)) *Stream[T] {
+	if s.pool != nil {
+		return s.derive(parallelApply(s.seq, s.pool, s.batchSize, func(item T) (T, bool) {
+			action(item)
+			return item, true
+		}))
+	}
+	return s.derive(func(yield func(T) bool) {
+		for item := range s.seq {
+			action(item)
+			if !yield(item) {
+				return
+			}
+		}
+	})
+}
+
+// Take 只保留前n个元素
+func (s *Stream[T]) Take(n int) *Stream[T] {
+	return s.derive(func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		count := 0
+		for item := range s.seq {
+			if !yield(item) {
+				return
+			}
+			count++
+			if count >= n {
+				return
+			}
+		}
+	})
+}
+
+// Skip 跳过前n个元素
+func (s *Stream[T]) Skip(n int) *Stream[T] {
+	return s.derive(func(yield func(T) bool) {
+		count := 0
+		for item := range s.seq {
+			if count < n {
+				count++
+				continue
+			}
+			if !yield(item) {
+				return
+			}
+		}
+	})
+}
+
+// Distinct 去除重复元素，保留第一个出现的元素
+// 元素的动态类型必须可比较，否则会panic
+func (s *Stream[T]) Distinct() *Stream[T] {
+	return s.derive(func(yield func(T) bool) {
+		seen := make(map[any]struct{})
+		for item := range s.seq {
+			if _, ok := seen[item]; ok {
+				continue
+			}
+			seen[item] = struct{}{}
+			if !yield(item) {
+				return
+			}
+		}
+	})
+}
+
+// Sorted 按 less 稳定排序，需要缓存全部元素
+func (s *Stream[T]) Sorted(less func(a, b T) bool) *Stream[T] {
+	return s.derive(func(yield func(T) bool) {
+		items := s.Collect()
+		sort.SliceStable(items, func(i, j int) bool {
+			return less(items[i], items[j])
+		})
+		for _, item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	})
+}
+
+// MapStream 将流中的元素转换为新的类型，保持原流的执行模式
+func MapStream[T any, R any](s *Stream[T], mapper func(T) R) *Stream[R] {
+	if s.pool != nil {
+		seq := parallelApply(s.seq, s.pool, s.batchSize, func(item T) (R, bool) {
+			return mapper(item), true
+		})
+		return &Stream[R]{seq: seq, pool: s.pool, batchSize: s.batchSize}
+	}
+	return FromSeq[R](func(yield func(R) bool) {
+		for item := range s.seq {
+			if !yield(mapper(item)) {
+				return
+			}
+		}
+	})
+}
+
+// Seq 返回底层迭代器，可用于 for range
+func (s *Stream[T]) Seq() iter.Seq[T] {
+	return s.seq
+}
+
+// ForEach 对每个元素执行操作
+func (s *Stream[T]) ForEach(action func(T)) {
+	for item := range s.seq {
+		action(item)
+	}
+}
+
+// Collect 收集所有元素为切片
+func (s *Stream[T]) Collect() []T {
+	result := make([]T, 0)
+	for item := range s.seq {
+		result = append(result, item)
+	}
+	return result
+}
+
+// Reduce 从初始值开始依次累积所有元素
+func (s *Stream[T]) Reduce(initial T, reducer func(T, T) T) T {
+	acc := initial
+	for item := range s.seq {
+		acc = reducer(acc, item)
+	}
+	return acc
+}
+
+// Count 元素数量
+func (s *Stream[T]) Count() int {
+	count := 0
+	for range s.seq {
+		count++
+	}
+	return count
+}
+
+// First 返回第一个元素，流为空时返回false
+func (s *Stream[T]) First() (T, bool) {
+	for item := range s.seq {
+		return item, true
+	}
+	var zero T
+	return zero, false
+}
+
+// AnyMatch 是否存在满足条件的元素，找到后立即停止遍历
+func (s *Stream[T]) AnyMatch(predicate func(T) bool) bool {
+	for item := range s.seq {
+		if predicate(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllMatch 是否所有元素都满足条件，空流返回true
+func (s *Stream[T]) AllMatch(predicate func(T) bool) bool {
+	for item := range s.seq {
+		if !predicate(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// parallelApply 从 seq 中按批读取元素，提交到 pool 并发执行 fn，再按原有顺序输出保留的结果
+// fn 中的panic会在调用方重新抛出，pool 拒绝或提交后丢弃的任务在调用方直接执行
+func parallelApply[T any, R any](seq iter.Seq[T], pool TaskSubmitter, batchSize int, fn func(T) (R, bool)) iter.Seq[R] {
+	submit := func(task func(), _ func()) { pool.Submit(task) }
+	if tp, ok := pool.(taskDiscardSubmitter); ok {
+		submit = func(task func(), onDiscard func()) {
+			if err := tp.TrySubmitOnDiscard(task, onDiscard); err != nil {
+				task()
+			}
+		}
+	} else if tp, ok := pool.(taskTrySubmitter); ok {
+		submit = func(task func(), _ func()) {
+			if err := tp.TrySubmit(task); err != nil {
+				task()
+			}
+		}
+	}
+	return func(yield func(R) bool) {
+		batch := make([]T, 0, batchSize)
+		results := make([]R, batchSize)
+		keeps := make([]bool, batchSize)
+
+		flush := func() bool {
+			var wg sync.WaitGroup
+			var panicOnce sync.Once
+			var panicErr any
+			// 被丢弃的任务交回调用方执行，每个任务最多被丢弃一次，缓冲区不会满
+			evicted := make(chan func(), len(batch))
+			for i, item := range batch {
+				wg.Add(1)
+				task := func() {
+					defer wg.Done()
+					defer func() {
+						if err := recover(); err != nil {
+							panicOnce.Do(func() { panicErr = err })
+						}
+					}()
+					results[i], keeps[i] = fn(item)
+				}
+				submit(task, func() { evicted <- task })
+			}
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+		wait:
+			for {
+				select {
+				case task := <-evicted:
+					task()
+				case <-done:
+					break wait
+				}
+			}
+			if panicErr != nil {
+				logger.Log.Error(fmt.Sprintf("err=%v", panicErr))
+				panic(panicErr)
+			}
+			n := len(batch)
+			batch = batch[:0]
+			for i := 0; i < n; i++ {
+				if keeps[i] && !yield(results[i]) {
+					return false
+				}
+			}
+			return true
+		}
+
+		for item := range seq {
+			batch = append(batch, item)
+			if len(batch) == batchSize && !flush() {
+				return
+			}
+		}
+		if len(batch) > 0 {
+			flush()
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package sliceUtil
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// goSubmitter 每个任务启动一个goroutine执行
+type goSubmitter struct {
+	submitted atomic.Int32
+}
+
+func (g *goSubmitter) Submit(task func()) {
+	g.submitted.Add(1)
+	go task()
+}
+
+// rejectSubmitter 每隔一个任务拒绝提交，Submit 会丢弃任务
+type rejectSubmitter struct {
+	rejected atomic.Int32
+	count    atomic.Int32
+}
+
+func (r *rejectSubmitter) Submit(func()) {}
+
+func (r *rejectSubmitter) TrySubmit(task func()) error {
+	if r.count.Add(1)%2 == 0 {
+		r.rejected.Add(1)
+		return errors.New("queue is full")
+	}
+	go task()
+	return nil
+}
+
+// evictSubmitter 接受所有任务，每三个任务中丢弃一个并在另一个协程中通知，模拟 RejectDiscardOldest
+type evictSubmitter struct {
+	evicted atomic.Int32
+	count   atomic.Int32
+}
+
+func (e *evictSubmitter) Submit(func()) {}
+
+func (e *evictSubmitter) TrySubmitOnDiscard(task func(), onDiscard func()) error {
+	if e.count.Add(1)%3 == 0 {
+		e.evicted.Add(1)
+		go onDiscard()
+		return nil
+	}
+	go task()
+	return nil
+}
+
+type testSet[T comparable] []T
+
+func (s testSet[T]) Range(f func(T) bool) {
+	for _, v := range s {
+		if !f(v) {
+			return
+		}
+	}
+}
+
+type testMap map[string]int
+
+func (m testMap) Range(f func(key string, value int) bool) {
+	for k, v := range m {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+func TestStreamLazyOps(t *testing.T) {
+	var visited []int
+	got := Of(1, 2, 3, 4, 5, 6, 7, 8, 9, 10).
+		Peek(func(v int) { visited = append(visited, v) }).
+		Filter(isEven).
+		Skip(1).
+		Take(2).
+		Collect()
+	if !reflect.DeepEqual(got, []int{4, 6}) {
+		t.Errorf("Collect() = %v", got)
+	}
+	// 惰性求值：取够元素后不再读取上游
+	if !reflect.DeepEqual(visited, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("visited = %v", visited)
+	}
+
+	strs := MapStream(FromSlice([]int{3, 1, 3, 2, 1}).Distinct(), strconv.Itoa).Collect()
+	if !reflect.DeepEqual(strs, []string{"3", "1", "2"}) {
+		t.Errorf("MapStream() = %v", strs)
+	}
+
+	sorted := Of(testUsers...).Sorted(func(a, b testUser) bool { return a.Age < b.Age }).Collect()
+	if sorted[0].Name != "dave" || sorted[2].Name != "alice" || sorted[3].Name != "carol" {
+		t.Errorf("Sorted() = %v", sorted)
+	}
+
+	if got := Of(1, 2, 3).Take(0).Collect(); len(got) != 0 {
+		t.Errorf("Take(0) = %v", got)
+	}
+}
+
+func TestStreamTerminalOps(t *testing.T) {
+	s := Of(1, 2, 3, 4)
+	if got := s.Reduce(0, func(a, b int) int { return a + b }); got != 10 {
+		t.Errorf("Reduce() = %v", got)
+	}
+	if got := s.Count(); got != 4 {
+		t.Errorf("Count() = %v", got)
+	}
+	if v, ok := s.Filter(func(v int) bool { return v > 2 }).First(); !ok || v != 3 {
+		t.Errorf("First() = %v, %v", v, ok)
+	}
+	if _, ok := Of[int]().First(); ok {
+		t.Error("First() on empty stream should return false")
+	}
+	if !s.AnyMatch(isEven) || s.AllMatch(isEven) || !Of[int]().AllMatch(isEven) {
+		t.Error("AnyMatch()/AllMatch() returned unexpected result")
+	}
+
+	sum := 0
+	s.ForEach(func(v int) { sum += v })
+	for v := range s.Seq() {
+		sum += v
+	}
+	if sum != 20 {
+		t.Errorf("ForEach()/Seq() sum = %v", sum)
+	}
+}
+
+func TestStreamSources(t *testing.T) {
+	cow := NewCopyOnWriteSlice[int]()
+	cow.AddAll(1, 2, 3)
+	if got := FromCopyOnWriteSlice(cow).Filter(isEven).Collect(); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("FromCopyOnWriteSlice() = %v", got)
+	}
+
+	set := testSet[string]{"a", "b", "c"}
+	if got := FromSet[string](set).Take(2).Collect(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("FromSet() = %v", got)
+	}
+
+	m := testMap{"a": 1, "b": 2, "c": 3}
+	keys := MapStream(FromMap[string, int](m).Filter(func(p Pair[string, int]) bool {
+		return p.Second > 1
+	}), func(p Pair[string, int]) string { return p.First }).Collect()
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"b", "c"}) {
+		t.Errorf("FromMap() = %v", keys)
+	}
+}
+
+func TestStreamParallel(t *testing.T) {
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = i
+	}
+
+	pool := &goSubmitter{}
+	var mu sync.Mutex
+	peeked := 0
+	s := FromSlice(input).Parallel(pool, 64)
+	if !s.IsParallel() || s.Sequential().IsParallel() {
+		t.Error("IsParallel() returned unexpected result")
+	}
+	got := MapStream(s.Filter(isEven).Peek(func(int) {
+		mu.Lock()
+		peeked++
+		mu.Unlock()
+	}), func(v int) int { return v * 2 }).Collect()
+
+	if len(got) != 500 || peeked != 500 {
+		t.Fatalf("len = %d, peeked = %d", len(got), peeked)
+	}
+	// 并行模式保持原有顺序
+	for i, v := range got {
+		if v != i*4 {
+			t.Fatalf("got[%d] = %d, want %d", i, v, i*4)
+		}
+	}
+	if pool.submitted.Load() == 0 {
+		t.Error("Expected tasks to be submitted to pool")
+	}
+
+	// 提前终止时不再处理后续批次
+	pool2 := &goSubmitter{}
+	first, _ := FromSlice(input).Parallel(pool2, 10).Filter(isEven).First()
+	if first != 0 || pool2.submitted.Load() != 10 {
+		t.Errorf("First() = %d, submitted = %d", first, pool2.submitted.Load())
+	}
+
+	// 被拒绝的任务在调用方执行，不会一直等待
+	rp := &rejectSubmitter{}
+	got = MapStream(FromSlice(input).Parallel(rp, 64), func(v int) int { return v + 1 }).Collect()
+	if len(got) != len(input) || rp.rejected.Load() == 0 {
+		t.Fatalf("len = %d, rejected = %d", len(got), rp.rejected.Load())
+	}
+	for i, v := range got {
+		if v != i+1 {
+			t.Fatalf("got[%d] = %d, want %d", i, v, i+1)
+		}
+	}
+
+	// 提交后被丢弃的任务交回调用方执行
+	ep := &evictSubmitter{}
+	got = MapStream(FromSlice(input).Parallel(ep, 64), func(v int) int { return v + 1 }).Collect()
+	if len(got) != len(input) || ep.evicted.Load() == 0 {
+		t.Fatalf("len = %d, evicted = %d", len(got), ep.evicted.Load())
+	}
+	for i, v := range got {
+		if v != i+1 {
+			t.Fatalf("got[%d] = %d, want %d", i, v, i+1)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("Expected panic to propagate, got %v", r)
+		}
+	}()
+	Of(1, 2, 3).Parallel(pool).Filter(func(v int) bool {
+		if v == 2 {
+			panic("boom")
+		}
+		return true
+	}).Collect()
+}